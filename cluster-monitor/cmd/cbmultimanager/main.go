@@ -8,6 +8,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"sort"
 	"time"
 
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/configuration"
@@ -23,6 +24,8 @@ import (
 )
 
 const (
+	configFlagName = "config"
+
 	sqliteKeyFlagName = "sqlite-key"
 	sqliteDBFlagName  = "sqlite-db"
 
@@ -51,8 +54,34 @@ const (
 	couchbasePasswordFlagName       = "couchbase-password"
 
 	logCheckLifetimeFlagName = "log-check-lifetime"
+
+	heartbeatHistoryRetentionFlagName = "heartbeat-history-retention"
+
+	alertmanagerURLsFlagName        = "alertmanager-urls"
+	alertmanagerResendDelayFlagName = "alertmanager-resend-delay"
+
+	heartFrequencyFlagName     = "heart-frequency"
+	statusFrequencyFlagName    = "status-frequency"
+	discoveryFrequencyFlagName = "discovery-frequency"
+
+	swapUsageWarnFlagName     = "swap-usage-warn"
+	swapUsageAlertFlagName    = "swap-usage-alert"
+	diskUsageWarnFlagName     = "disk-usage-warn"
+	diskUsageAlertFlagName    = "disk-usage-alert"
+	memoryUsageWarnFlagName   = "memory-usage-warn"
+	memoryUsageAlertFlagName  = "memory-usage-alert"
+	minimumNodeMemoryFlagName = "minimum-node-memory"
+	eomWarnMonthsFlagName     = "eom-warn-months"
+
+	largeCheckpointMemoryFlagName = "large-checkpoint-memory"
+	largeCheckpointQuotaFlagName  = "large-checkpoint-quota"
+	largeCheckpointItemsFlagName  = "large-checkpoint-items"
 )
 
+// requiredFlags have to be given either as flags, environment variables or in the config file. They cannot be marked as
+// required in the flags themselves as that is checked before the config file is loaded.
+var requiredFlags = []string{sqliteKeyFlagName, sqliteDBFlagName, certPathFlagName, keyPathFlagName}
+
 func init() {
 	// Initialise a logger as early as possible, to ensure that any startup errors get logged.
 	// It will get replaced later, in logger.Init().
@@ -73,111 +102,19 @@ func main() {
 		Version:              meta.Version,
 		EnableBashCompletion: true,
 		Action:               run,
-		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:     sqliteKeyFlagName,
-				Usage:    "The password for the SQLiteStore",
-				EnvVars:  []string{"CB_MULTI_SQLITE_PASSWORD"},
-				Required: true,
-			},
-			&cli.StringFlag{
-				Name:     sqliteDBFlagName,
-				Usage:    "The path to the SQLite file to use. If the file does not exist it will create it.",
-				Required: true,
-			},
-			&cli.StringFlag{
-				Name:     certPathFlagName,
-				Usage:    "The certificate path to use for TLS",
-				EnvVars:  []string{"CB_MULTI_CERT_PATH"},
-				Required: true,
-			},
-			&cli.StringFlag{
-				Name:     keyPathFlagName,
-				Usage:    "The path to the key",
-				EnvVars:  []string{"CB_MULTI_KEY_PATH"},
-				Required: true,
-			},
-			&cli.StringFlag{
-				Name:  logLevelFlagName,
-				Usage: "Set the log level, options are [error, warn, info, debug]",
-				Value: "info",
-			},
-			&cli.IntFlag{
-				Name:  httpPortFlagName,
-				Usage: "The port to serve HTTP REST API",
-				Value: 7196,
-			},
-			&cli.IntFlag{
-				Name:  httpsPortFlagName,
-				Usage: "The port to serve HTTPS REST API",
-				Value: 7197,
-			},
-			&cli.StringFlag{
-				Name:  uiRootFlagName,
-				Usage: "The location of the packed UI",
-				Value: "./ui/dist/app",
-			},
-			&cli.IntFlag{
-				Name: maxWorkersFlagName,
-				Usage: "The maximum number of workers used for health monitoring and heartbeats " +
-					"(defaults to 75% of the number of CPUs)",
-			},
-			&cli.StringFlag{
-				Name:  logDirFlagName,
-				Usage: "The location to log too. If it does not exist it will try to create it.",
-			},
-			&cli.StringFlag{
-				Name:    adminUserFlagName,
-				Usage:   "The name of the admin user for auto-provisioning",
-				EnvVars: []string{"CB_MULTI_ADMIN_USER"},
-			},
-			&cli.StringFlag{
-				Name:    adminPasswordFlagName,
-				Usage:   "The password for the admin user for auto-provisioning",
-				EnvVars: []string{"CB_MULTI_ADMIN_PASSWORD"},
-			},
-			&cli.DurationFlag{
-				Name:  logCheckLifetimeFlagName,
-				Usage: "How long will log alerts fire before being expired.",
-				Value: time.Hour,
-			},
-			&cli.BoolFlag{
-				Name:  enableAdminAPIFlagName,
-				Usage: "Enable the admin REST API.",
-				Value: true,
-			},
-			&cli.BoolFlag{
-				Name:  enableExtendedAPIFlagName,
-				Usage: "Enable the extended REST API.",
-				Value: true,
-			},
-			&cli.BoolFlag{
-				Name:  enableClusterManagementAPIFlagName,
-				Usage: "Enable the cluster management REST API.",
-				Value: true,
-			},
-			&cli.StringFlag{
-				Name:    prometheusURLFlagName,
-				Usage:   "Base URL of Prometheus instance",
-				Value:   "",
-				EnvVars: []string{"CB_MULTI_PROMETHEUS_URL"},
-			},
-			&cli.StringFlag{
-				Name: prometheusLabelSelectorFlagName,
-				Usage: "Prometheus label selector to use to discover Couchbase Server clusters. " +
-					"Syntax: `label1=value label2=value`",
-				Value:   "",
-				EnvVars: []string{"CB_MULTI_PROMETHEUS_LABEL_SELECTOR"},
-			},
-			&cli.StringFlag{
-				Name:    couchbaseUserFlagName,
-				Usage:   "Couchbase user name (only needed when using Prometheus discovery)",
-				EnvVars: []string{"CB_MULTI_COUCHBASE_USER"},
-			},
-			&cli.StringFlag{
-				Name:    couchbasePasswordFlagName,
-				Usage:   "Couchbase password (only needed when using Prometheus discovery)",
-				EnvVars: []string{"CB_MULTI_COUCHBASE_PASSWORD"},
+		Flags:                flags(),
+		Commands: []*cli.Command{
+			{
+				Name:  "config",
+				Usage: "Work with the configuration",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "validate",
+						Usage:  "Checks the configuration given by the flags, environment and config file is valid",
+						Flags:  flags(),
+						Action: validateConfig,
+					},
+				},
 			},
 		},
 	}
@@ -188,8 +125,223 @@ func main() {
 	}
 }
 
+// flags returns the flags accepted by cbmultimanager. All of them can also be given in the config file using the flag
+// name as the key.
+func flags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name: configFlagName,
+			Usage: "Path to a YAML or JSON config file using the flag names as keys. Flags and environment " +
+				"variables take precedence over the file",
+			EnvVars: []string{"CB_MULTI_CONFIG"},
+		},
+		&cli.StringFlag{
+			Name:    sqliteKeyFlagName,
+			Usage:   "The password for the SQLiteStore",
+			EnvVars: []string{"CB_MULTI_SQLITE_PASSWORD"},
+		},
+		&cli.StringFlag{
+			Name:  sqliteDBFlagName,
+			Usage: "The path to the SQLite file to use. If the file does not exist it will create it.",
+		},
+		&cli.StringFlag{
+			Name:    certPathFlagName,
+			Usage:   "The certificate path to use for TLS",
+			EnvVars: []string{"CB_MULTI_CERT_PATH"},
+		},
+		&cli.StringFlag{
+			Name:    keyPathFlagName,
+			Usage:   "The path to the key",
+			EnvVars: []string{"CB_MULTI_KEY_PATH"},
+		},
+		&cli.StringFlag{
+			Name:  logLevelFlagName,
+			Usage: "Set the log level, options are [error, warn, info, debug]",
+			Value: "info",
+		},
+		&cli.IntFlag{
+			Name:  httpPortFlagName,
+			Usage: "The port to serve HTTP REST API",
+			Value: 7196,
+		},
+		&cli.IntFlag{
+			Name:  httpsPortFlagName,
+			Usage: "The port to serve HTTPS REST API",
+			Value: 7197,
+		},
+		&cli.StringFlag{
+			Name:  uiRootFlagName,
+			Usage: "The location of the packed UI",
+			Value: "./ui/dist/app",
+		},
+		&cli.IntFlag{
+			Name: maxWorkersFlagName,
+			Usage: "The maximum number of workers used for health monitoring and heartbeats " +
+				"(defaults to 75% of the number of CPUs)",
+		},
+		&cli.StringFlag{
+			Name:  logDirFlagName,
+			Usage: "The location to log too. If it does not exist it will try to create it.",
+		},
+		&cli.StringFlag{
+			Name:    adminUserFlagName,
+			Usage:   "The name of the admin user for auto-provisioning",
+			EnvVars: []string{"CB_MULTI_ADMIN_USER"},
+		},
+		&cli.StringFlag{
+			Name:    adminPasswordFlagName,
+			Usage:   "The password for the admin user for auto-provisioning",
+			EnvVars: []string{"CB_MULTI_ADMIN_PASSWORD"},
+		},
+		&cli.DurationFlag{
+			Name:  logCheckLifetimeFlagName,
+			Usage: "How long will log alerts fire before being expired.",
+			Value: time.Hour,
+		},
+		&cli.DurationFlag{
+			Name:    heartbeatHistoryRetentionFlagName,
+			Usage:   "How long the heartbeat history of the clusters is kept for.",
+			Value:   30 * 24 * time.Hour,
+			EnvVars: []string{"CB_MULTI_HEARTBEAT_HISTORY_RETENTION"},
+		},
+		&cli.BoolFlag{
+			Name:  enableAdminAPIFlagName,
+			Usage: "Enable the admin REST API.",
+			Value: true,
+		},
+		&cli.BoolFlag{
+			Name:  enableExtendedAPIFlagName,
+			Usage: "Enable the extended REST API.",
+			Value: true,
+		},
+		&cli.BoolFlag{
+			Name:  enableClusterManagementAPIFlagName,
+			Usage: "Enable the cluster management REST API.",
+			Value: true,
+		},
+		&cli.StringFlag{
+			Name:    prometheusURLFlagName,
+			Usage:   "Base URL of Prometheus instance",
+			Value:   "",
+			EnvVars: []string{"CB_MULTI_PROMETHEUS_URL"},
+		},
+		&cli.StringFlag{
+			Name: prometheusLabelSelectorFlagName,
+			Usage: "Prometheus label selector to use to discover Couchbase Server clusters. " +
+				"Syntax: `label1=value label2=value`",
+			Value:   "",
+			EnvVars: []string{"CB_MULTI_PROMETHEUS_LABEL_SELECTOR"},
+		},
+		&cli.StringFlag{
+			Name:    couchbaseUserFlagName,
+			Usage:   "Couchbase user name (only needed when using Prometheus discovery)",
+			EnvVars: []string{"CB_MULTI_COUCHBASE_USER"},
+		},
+		&cli.StringFlag{
+			Name:    couchbasePasswordFlagName,
+			Usage:   "Couchbase password (only needed when using Prometheus discovery)",
+			EnvVars: []string{"CB_MULTI_COUCHBASE_PASSWORD"},
+		},
+		&cli.StringFlag{
+			Name:    alertmanagerURLsFlagName,
+			Usage:   "Comma separated list of Alertmanager base URLs to send alerts to",
+			EnvVars: []string{"CB_MULTI_ALERTMANAGER_URLS"},
+		},
+		&cli.DurationFlag{
+			Name:    alertmanagerResendDelayFlagName,
+			Usage:   "How often active alerts are re-sent to Alertmanager",
+			Value:   time.Minute,
+			EnvVars: []string{"CB_MULTI_ALERTMANAGER_RESEND_DELAY"},
+		},
+		&cli.DurationFlag{
+			Name:    heartFrequencyFlagName,
+			Usage:   "How often the clusters heartbeat is checked",
+			Value:   manager.DefaultFrequencyConfiguration.Heart,
+			EnvVars: []string{"CB_MULTI_HEART_FREQUENCY"},
+		},
+		&cli.DurationFlag{
+			Name:    statusFrequencyFlagName,
+			Usage:   "How often the status checkers are run against the clusters",
+			Value:   manager.DefaultFrequencyConfiguration.Status,
+			EnvVars: []string{"CB_MULTI_STATUS_FREQUENCY"},
+		},
+		&cli.DurationFlag{
+			Name:    discoveryFrequencyFlagName,
+			Usage:   "How often Prometheus is queried to discover clusters",
+			Value:   manager.DefaultFrequencyConfiguration.Discovery,
+			EnvVars: []string{"CB_MULTI_DISCOVERY_FREQUENCY"},
+		},
+		&cli.Float64Flag{
+			Name:    swapUsageWarnFlagName,
+			Usage:   "Percentage of swap space used by a node above which a warning is raised",
+			Value:   configuration.DefaultCheckerThresholds.SwapUsageWarn,
+			EnvVars: []string{"CB_MULTI_SWAP_USAGE_WARN"},
+		},
+		&cli.Float64Flag{
+			Name:    swapUsageAlertFlagName,
+			Usage:   "Percentage of swap space used by a node above which an alert is raised",
+			Value:   configuration.DefaultCheckerThresholds.SwapUsageAlert,
+			EnvVars: []string{"CB_MULTI_SWAP_USAGE_ALERT"},
+		},
+		&cli.Float64Flag{
+			Name:    diskUsageWarnFlagName,
+			Usage:   "Percentage of a node's disk used above which a warning is raised",
+			Value:   configuration.DefaultCheckerThresholds.DiskUsageWarn,
+			EnvVars: []string{"CB_MULTI_DISK_USAGE_WARN"},
+		},
+		&cli.Float64Flag{
+			Name:    diskUsageAlertFlagName,
+			Usage:   "Percentage of a node's disk used above which an alert is raised",
+			Value:   configuration.DefaultCheckerThresholds.DiskUsageAlert,
+			EnvVars: []string{"CB_MULTI_DISK_USAGE_ALERT"},
+		},
+		&cli.Float64Flag{
+			Name:    memoryUsageWarnFlagName,
+			Usage:   "Percentage of a node's memory used above which a warning is raised",
+			Value:   configuration.DefaultCheckerThresholds.MemoryUsageWarn,
+			EnvVars: []string{"CB_MULTI_MEMORY_USAGE_WARN"},
+		},
+		&cli.Float64Flag{
+			Name:    memoryUsageAlertFlagName,
+			Usage:   "Percentage of a node's memory used above which an alert is raised",
+			Value:   configuration.DefaultCheckerThresholds.MemoryUsageAlert,
+			EnvVars: []string{"CB_MULTI_MEMORY_USAGE_ALERT"},
+		},
+		&cli.Uint64Flag{
+			Name:    minimumNodeMemoryFlagName,
+			Usage:   "Memory in MiB below which a node is reported as not meeting the minimum requirements",
+			Value:   configuration.DefaultCheckerThresholds.MinimumNodeMemory / 1024 / 1024,
+			EnvVars: []string{"CB_MULTI_MINIMUM_NODE_MEMORY"},
+		},
+		&cli.IntFlag{
+			Name:    eomWarnMonthsFlagName,
+			Usage:   "How many months before the end of maintenance of a Couchbase Server version to start warning",
+			Value:   configuration.DefaultCheckerThresholds.EOMWarnMonths,
+			EnvVars: []string{"CB_MULTI_EOM_WARN_MONTHS"},
+		},
+		&cli.Uint64Flag{
+			Name:    largeCheckpointMemoryFlagName,
+			Usage:   "Memory in MiB above which the checkpoints of a vBucket are reported as large",
+			Value:   configuration.DefaultCheckerThresholds.LargeCheckpointMemory / 1024 / 1024,
+			EnvVars: []string{"CB_MULTI_LARGE_CHECKPOINT_MEMORY"},
+		},
+		&cli.Float64Flag{
+			Name:    largeCheckpointQuotaFlagName,
+			Usage:   "Percentage of the bucket quota above which the checkpoints of a vBucket are reported as large",
+			Value:   configuration.DefaultCheckerThresholds.LargeCheckpointQuota,
+			EnvVars: []string{"CB_MULTI_LARGE_CHECKPOINT_QUOTA"},
+		},
+		&cli.Uint64Flag{
+			Name:    largeCheckpointItemsFlagName,
+			Usage:   "Items above which the checkpoints of a vBucket are reported as large, 0 to not check them",
+			Value:   configuration.DefaultCheckerThresholds.LargeCheckpointItems,
+			EnvVars: []string{"CB_MULTI_LARGE_CHECKPOINT_ITEMS"},
+		},
+	}
+}
+
 func run(c *cli.Context) error {
-	config, err := getConfig(c)
+	config, frequencies, err := getConfig(c)
 	if err != nil {
 		return fmt.Errorf("invalid configuration provided: %w", err)
 	}
@@ -200,41 +352,149 @@ func run(c *cli.Context) error {
 
 	argsToMask := []string{"--" + sqliteKeyFlagName, "--" + adminPasswordFlagName, "--" + couchbasePasswordFlagName}
 	zap.S().Infof("(Main) Running options %s", log.MaskArguments(os.Args[1:], argsToMask))
-	zap.S().Infow("(Main) Using configuration", "config", config)
+	zap.S().Infow("(Main) Using configuration", "config", config, "config-file", c.String(configFlagName))
 
 	node, err := manager.NewManager(config)
 	if err != nil {
 		return fmt.Errorf("could not create manager: %w", err)
 	}
 
-	// TODO (CMOS-57) move this to runtime configuration
-	node.Start(manager.DefaultFrequencyConfiguration)
+	node.Start(frequencies)
 	return nil
 }
 
-func getConfig(c *cli.Context) (*configuration.Config, error) {
+// validateConfig checks the configuration is valid without starting the manager.
+func validateConfig(c *cli.Context) error {
+	if _, _, err := getConfig(c); err != nil {
+		return fmt.Errorf("invalid configuration provided: %w", err)
+	}
+
+	fmt.Println("Configuration is valid")
+	return nil
+}
+
+// loadConfigFile sets the flags from the config file, if one is given. Flags that were given in the command line or
+// as environment variables are left as they are so they take precedence over the file.
+func loadConfigFile(c *cli.Context) error {
+	path := c.String(configFlagName)
+	if path == "" {
+		return nil
+	}
+
+	fileValues, err := configuration.LoadFile(path)
+	if err != nil {
+		return err
+	}
+
+	knownFlags := make(map[string]bool)
+	for _, flag := range flags() {
+		for _, name := range flag.Names() {
+			knownFlags[name] = true
+		}
+	}
+
+	keys := make([]string, 0, len(fileValues))
+	for key := range fileValues {
+		keys = append(keys, key)
+	}
+
+	// sort the keys so the errors are consistent between runs
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !knownFlags[key] || key == configFlagName {
+			return fmt.Errorf("unknown key '%s' in config file", key)
+		}
+
+		if c.IsSet(key) {
+			continue
+		}
+
+		if err = c.Set(key, fileValues[key]); err != nil {
+			return fmt.Errorf("invalid value for '%s' in config file: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+func getConfig(c *cli.Context) (*configuration.Config, manager.FrequencyConfiguration, error) {
+	var frequencies manager.FrequencyConfiguration
+	if err := loadConfigFile(c); err != nil {
+		return nil, frequencies, err
+	}
+
+	for _, name := range requiredFlags {
+		if c.String(name) == "" {
+			return nil, frequencies, fmt.Errorf("'%s' is required", name)
+		}
+	}
+
 	selectors, err := configuration.ParseLabelSelectors(c.String(prometheusLabelSelectorFlagName))
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse config: %w", err)
+		return nil, frequencies, fmt.Errorf("invalid value for '%s': %w", prometheusLabelSelectorFlagName, err)
 	}
+
+	alertmanagerURLs, err := configuration.ParseURLs(c.String(alertmanagerURLsFlagName))
+	if err != nil {
+		return nil, frequencies, fmt.Errorf("invalid value for '%s': %w", alertmanagerURLsFlagName, err)
+	}
+
+	for _, name := range []string{
+		alertmanagerResendDelayFlagName, heartFrequencyFlagName, statusFrequencyFlagName, discoveryFrequencyFlagName,
+	} {
+		if c.Duration(name) <= 0 {
+			return nil, frequencies, fmt.Errorf("invalid value for '%s': must be positive", name)
+		}
+	}
+
+	frequencies = manager.FrequencyConfiguration{
+		Heart:     c.Duration(heartFrequencyFlagName),
+		Status:    c.Duration(statusFrequencyFlagName),
+		Discovery: c.Duration(discoveryFrequencyFlagName),
+	}
+
+	thresholds := configuration.CheckerThresholds{
+		SwapUsageWarn:     c.Float64(swapUsageWarnFlagName),
+		SwapUsageAlert:    c.Float64(swapUsageAlertFlagName),
+		DiskUsageWarn:     c.Float64(diskUsageWarnFlagName),
+		DiskUsageAlert:    c.Float64(diskUsageAlertFlagName),
+		MemoryUsageWarn:   c.Float64(memoryUsageWarnFlagName),
+		MemoryUsageAlert:  c.Float64(memoryUsageAlertFlagName),
+		MinimumNodeMemory: c.Uint64(minimumNodeMemoryFlagName) * 1024 * 1024,
+		EOMWarnMonths:     c.Int(eomWarnMonthsFlagName),
+
+		LargeCheckpointMemory: c.Uint64(largeCheckpointMemoryFlagName) * 1024 * 1024,
+		LargeCheckpointQuota:  c.Float64(largeCheckpointQuotaFlagName),
+		LargeCheckpointItems:  c.Uint64(largeCheckpointItemsFlagName),
+	}
+
+	if err = thresholds.Validate(); err != nil {
+		return nil, frequencies, fmt.Errorf("invalid checker thresholds: %w", err)
+	}
+
 	config := &configuration.Config{
-		SQLiteKey:               c.String(sqliteKeyFlagName),
-		SQLiteDB:                c.String(sqliteDBFlagName),
-		CertPath:                c.String(certPathFlagName),
-		KeyPath:                 c.String(keyPathFlagName),
-		HTTPPort:                c.Int(httpPortFlagName),
-		HTTPSPort:               c.Int(httpsPortFlagName),
-		UIRoot:                  c.String(uiRootFlagName),
-		AdminUser:               c.String(adminUserFlagName),
-		AdminPassword:           c.String(adminPasswordFlagName),
-		EnableAdminAPI:          c.Bool(enableAdminAPIFlagName),
-		EnableExtendedAPI:       c.Bool(enableExtendedAPIFlagName),
-		EnableClusterAPI:        c.Bool(enableClusterManagementAPIFlagName),
-		PrometheusBaseURL:       c.String(prometheusURLFlagName),
-		PrometheusLabelSelector: selectors,
-		CouchbaseUser:           c.String(couchbaseUserFlagName),
-		CouchbasePassword:       c.String(couchbasePasswordFlagName),
-		LogCheckLifetime:        c.Duration(logCheckLifetimeFlagName),
+		SQLiteKey:                 c.String(sqliteKeyFlagName),
+		SQLiteDB:                  c.String(sqliteDBFlagName),
+		CertPath:                  c.String(certPathFlagName),
+		KeyPath:                   c.String(keyPathFlagName),
+		HTTPPort:                  c.Int(httpPortFlagName),
+		HTTPSPort:                 c.Int(httpsPortFlagName),
+		UIRoot:                    c.String(uiRootFlagName),
+		AdminUser:                 c.String(adminUserFlagName),
+		AdminPassword:             c.String(adminPasswordFlagName),
+		EnableAdminAPI:            c.Bool(enableAdminAPIFlagName),
+		EnableExtendedAPI:         c.Bool(enableExtendedAPIFlagName),
+		EnableClusterAPI:          c.Bool(enableClusterManagementAPIFlagName),
+		PrometheusBaseURL:         c.String(prometheusURLFlagName),
+		PrometheusLabelSelector:   selectors,
+		CouchbaseUser:             c.String(couchbaseUserFlagName),
+		CouchbasePassword:         c.String(couchbasePasswordFlagName),
+		LogCheckLifetime:          c.Duration(logCheckLifetimeFlagName),
+		HeartbeatHistoryRetention: c.Duration(heartbeatHistoryRetentionFlagName),
+		AlertmanagerURLs:          alertmanagerURLs,
+		AlertmanagerResendDelay:   c.Duration(alertmanagerResendDelayFlagName),
+		CheckerThresholds:         thresholds,
 	}
 
 	switch c.String(logLevelFlagName) {
@@ -247,10 +507,11 @@ func getConfig(c *cli.Context) (*configuration.Config, error) {
 	case "debug":
 		config.LogLevel = zapcore.DebugLevel
 	default:
-		return nil, fmt.Errorf("unknown log level '%s'", c.String(logLevelFlagName))
+		return nil, frequencies, fmt.Errorf("invalid value for '%s': unknown log level '%s'", logLevelFlagName,
+			c.String(logLevelFlagName))
 	}
 
 	config.MaxWorkers = system.NumWorkers(c.Int(maxWorkersFlagName))
 
-	return config, nil
+	return config, frequencies, nil
 }
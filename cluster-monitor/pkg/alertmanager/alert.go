@@ -0,0 +1,122 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package alertmanager
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+)
+
+const (
+	// HeartbeatAlertName is the alert name used for clusters that failed their last heartbeat.
+	HeartbeatAlertName = "clusterHeartbeat"
+
+	severityWarning  = "warning"
+	severityCritical = "critical"
+)
+
+// Alert is an alert in the format expected by the Alertmanager v2 API.
+type Alert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+}
+
+// fingerprint uniquely identifies the alert by its labels, as Alertmanager does.
+func (a *Alert) fingerprint() string {
+	keys := make([]string, 0, len(a.Labels))
+	for key := range a.Labels {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var builder strings.Builder
+	for _, key := range keys {
+		builder.WriteString(key)
+		builder.WriteByte('=')
+		builder.WriteString(a.Labels[key])
+		builder.WriteByte(',')
+	}
+
+	return builder.String()
+}
+
+// clusterLabels are the labels shared by all the alerts for a cluster.
+func clusterLabels(cluster *values.CouchbaseCluster) map[string]string {
+	labels := map[string]string{
+		"cluster_uuid": cluster.UUID,
+		"cluster_name": cluster.Name,
+	}
+
+	if cluster.Alias != "" {
+		labels["cluster_alias"] = cluster.Alias
+	}
+
+	return labels
+}
+
+// newCheckerAlert creates the alert for a warn or alert checker result.
+func newCheckerAlert(cluster *values.CouchbaseCluster, result *values.WrappedCheckerResult) *Alert {
+	alert := &Alert{
+		Labels:      clusterLabels(cluster),
+		Annotations: make(map[string]string),
+	}
+
+	alert.Labels["alertname"] = result.Result.Name
+	alert.Labels["severity"] = severityWarning
+	if result.Result.Status == values.AlertCheckerStatus {
+		alert.Labels["severity"] = severityCritical
+	}
+
+	if result.Node != "" {
+		alert.Labels["node"] = result.Node
+	}
+
+	if result.Bucket != "" {
+		alert.Labels["bucket"] = result.Bucket
+	}
+
+	if result.LogFile != "" {
+		alert.Labels["log_file"] = result.LogFile
+	}
+
+	if def, ok := values.AllCheckerDefs[result.Result.Name]; ok {
+		alert.Labels["checker_id"] = def.ID
+		alert.Annotations["title"] = def.Title
+		alert.Annotations["description"] = def.Description
+	}
+
+	if result.Result.Remediation != "" {
+		alert.Annotations["remediation"] = result.Result.Remediation
+	}
+
+	if len(result.Result.Value) != 0 {
+		alert.Annotations["value"] = string(result.Result.Value)
+	}
+
+	return alert
+}
+
+// newHeartbeatAlert creates the alert for a cluster that failed its last heartbeat.
+func newHeartbeatAlert(cluster *values.CouchbaseCluster) *Alert {
+	alert := &Alert{
+		Labels: clusterLabels(cluster),
+		Annotations: map[string]string{
+			"title":       "Cluster heartbeat failed",
+			"description": "Could not connect to the cluster during the last heartbeat: " + cluster.HeartBeatIssue.String(),
+		},
+	}
+
+	alert.Labels["alertname"] = HeartbeatAlertName
+	alert.Labels["severity"] = severityCritical
+
+	return alert
+}
@@ -0,0 +1,245 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package alertmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/storage"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"go.uber.org/zap"
+)
+
+const (
+	alertsEndpoint = "/api/v2/alerts"
+
+	// endsAtFactor is how many resend delays an alert stays active without being re-sent. This matches what
+	// Prometheus does so alerts resolve on their own if the dispatcher goes away.
+	endsAtFactor = 4
+)
+
+// Dispatcher periodically sends the warnings and alerts from the health checkers as well as the heartbeat failures to
+// Alertmanager. Active alerts are re-sent on every run and the ones that cleared are resolved by setting their end
+// time.
+type Dispatcher struct {
+	store       storage.Store
+	urls        []string
+	resendDelay time.Duration
+	client      *http.Client
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	// active is keyed by the alert fingerprint. It is only accessed by Dispatch.
+	active map[string]*Alert
+}
+
+func NewDispatcher(store storage.Store, urls []string, resendDelay time.Duration) *Dispatcher {
+	return &Dispatcher{
+		store:       store,
+		urls:        urls,
+		resendDelay: resendDelay,
+		client:      &http.Client{Timeout: 30 * time.Second},
+		active:      make(map[string]*Alert),
+	}
+}
+
+func (d *Dispatcher) Start() {
+	// dispatcher already running
+	if d.ctx != nil {
+		return
+	}
+
+	zap.S().Infow("(Alert Dispatcher) Starting dispatcher", "urls", d.urls, "resend-delay", d.resendDelay)
+	d.ctx, d.cancel = context.WithCancel(context.Background())
+	d.wg.Add(1)
+	go d.dispatchLoop()
+}
+
+func (d *Dispatcher) Stop() {
+	// not running
+	if d.ctx == nil {
+		return
+	}
+
+	zap.S().Info("(Alert Dispatcher) Stopping dispatcher")
+	d.cancel()
+	d.wg.Wait()
+	d.ctx, d.cancel = nil, nil
+}
+
+func (d *Dispatcher) dispatchLoop() {
+	ticker := time.NewTicker(d.resendDelay)
+	defer func() {
+		d.wg.Done()
+		ticker.Stop()
+	}()
+
+	for {
+		if err := d.Dispatch(); err != nil {
+			zap.S().Warnw("(Alert Dispatcher) Could not dispatch alerts", "err", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-d.ctx.Done():
+			return
+		}
+	}
+}
+
+// Dispatch sends all the active alerts and resolves the ones that are no longer active. Resolved alerts that could not
+// be sent to any Alertmanager are retried on the next dispatch. It must not be called concurrently.
+func (d *Dispatcher) Dispatch() error {
+	current, err := d.collectAlerts()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	toSend := make([]*Alert, 0, len(current)+len(d.active))
+	for fingerprint, alert := range current {
+		alert.StartsAt = now
+		if previous, ok := d.active[fingerprint]; ok && previous.EndsAt.After(now) {
+			alert.StartsAt = previous.StartsAt
+		}
+
+		alert.EndsAt = now.Add(endsAtFactor * d.resendDelay)
+		d.active[fingerprint] = alert
+		toSend = append(toSend, alert)
+	}
+
+	resolved := make([]string, 0)
+	for fingerprint, alert := range d.active {
+		if _, ok := current[fingerprint]; ok {
+			continue
+		}
+
+		alert.EndsAt = now
+		toSend = append(toSend, alert)
+		resolved = append(resolved, fingerprint)
+	}
+
+	if len(toSend) == 0 {
+		return nil
+	}
+
+	sort.Slice(toSend, func(i, j int) bool {
+		return toSend[i].fingerprint() < toSend[j].fingerprint()
+	})
+
+	if err = d.send(toSend); err != nil {
+		return err
+	}
+
+	for _, fingerprint := range resolved {
+		delete(d.active, fingerprint)
+	}
+
+	zap.S().Debugw("(Alert Dispatcher) Dispatched alerts", "active", len(current), "resolved", len(resolved))
+	return nil
+}
+
+// collectAlerts builds the alerts for all the non dismissed warnings and alerts as well as the clusters with heartbeat
+// issues. They are keyed by fingerprint.
+func (d *Dispatcher) collectAlerts() (map[string]*Alert, error) {
+	clusters, err := d.store.GetClusters(false, false)
+	if err != nil {
+		return nil, fmt.Errorf("could not get clusters: %w", err)
+	}
+
+	results, err := d.store.GetCheckerResult(values.CheckerSearch{})
+	if err != nil {
+		return nil, fmt.Errorf("could not get checker results: %w", err)
+	}
+
+	dismissals, err := d.store.GetDismissals(values.DismissalSearchSpace{})
+	if err != nil {
+		return nil, fmt.Errorf("could not get dismissals: %w", err)
+	}
+
+	clustersByUUID := make(map[string]*values.CouchbaseCluster, len(clusters))
+	alerts := make(map[string]*Alert)
+	for _, cluster := range clusters {
+		clustersByUUID[cluster.UUID] = cluster
+
+		if cluster.HeartBeatIssue != values.NoHeartIssue {
+			alert := newHeartbeatAlert(cluster)
+			alerts[alert.fingerprint()] = alert
+		}
+	}
+
+	for _, result := range results {
+		if result.Result.Status != values.WarnCheckerStatus && result.Result.Status != values.AlertCheckerStatus {
+			continue
+		}
+
+		cluster, ok := clustersByUUID[result.Cluster]
+		if !ok || values.IsDismissed(result, dismissals) {
+			continue
+		}
+
+		alert := newCheckerAlert(cluster, result)
+		alerts[alert.fingerprint()] = alert
+	}
+
+	return alerts, nil
+}
+
+// send posts the alerts to all the Alertmanager instances. It only fails if none of them accepted the alerts.
+func (d *Dispatcher) send(alerts []*Alert) error {
+	body, err := json.Marshal(alerts)
+	if err != nil {
+		return fmt.Errorf("could not marshal alerts: %w", err)
+	}
+
+	var failed int
+	for _, url := range d.urls {
+		if err := d.post(url+alertsEndpoint, body); err != nil {
+			zap.S().Warnw("(Alert Dispatcher) Could not send alerts", "url", url, "err", err)
+			failed++
+		}
+	}
+
+	if failed == len(d.urls) {
+		return fmt.Errorf("could not send alerts to any Alertmanager")
+	}
+
+	return nil
+}
+
+func (d *Dispatcher) post(url string, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.client.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("could not create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not post alerts: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+
+	return nil
+}
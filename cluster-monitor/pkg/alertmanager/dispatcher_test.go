@@ -0,0 +1,236 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package alertmanager
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/storage"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/storage/sqlite"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func init() {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+	encoderConfig.ConsoleSeparator = " "
+
+	encoder := zapcore.NewConsoleEncoder(encoderConfig)
+	core := zapcore.NewCore(encoder, os.Stdout, zapcore.WarnLevel)
+
+	zap.ReplaceGlobals(zap.New(core))
+}
+
+// testAlertmanager is a stand-in for the Alertmanager alerts endpoint that records every request it receives.
+type testAlertmanager struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	requests [][]*Alert
+}
+
+func newTestAlertmanager(t *testing.T, status int) *testAlertmanager {
+	am := &testAlertmanager{}
+	am.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != alertsEndpoint {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var alerts []*Alert
+		if err := json.NewDecoder(r.Body).Decode(&alerts); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		am.lock.Lock()
+		am.requests = append(am.requests, alerts)
+		am.lock.Unlock()
+
+		w.WriteHeader(status)
+	}))
+
+	t.Cleanup(am.Close)
+	return am
+}
+
+func (a *testAlertmanager) last(t *testing.T) []*Alert {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	require.NotEmpty(t, a.requests)
+	return a.requests[len(a.requests)-1]
+}
+
+func (a *testAlertmanager) count() int {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	return len(a.requests)
+}
+
+func createTestStore(t *testing.T) storage.Store {
+	store, err := sqlite.NewSQLiteDB(filepath.Join(t.TempDir(), "store.sqlite"), "key")
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+
+	require.NoError(t, store.AddCluster(&values.CouchbaseCluster{
+		UUID:         "uuid-0",
+		Enterprise:   true,
+		Name:         "cluster-0",
+		Alias:        "a-0",
+		NodesSummary: values.NodesSummary{{NodeUUID: "N0", Host: "http://localhost:9000"}},
+	}))
+
+	results := []*values.WrappedCheckerResult{
+		{
+			Cluster: "uuid-0",
+			Node:    "N0",
+			Result: &values.CheckerResult{
+				Name:        values.CheckUnhealthyNode,
+				Status:      values.AlertCheckerStatus,
+				Remediation: "fix it",
+				Value:       json.RawMessage(`{"status":"unhealthy"}`),
+			},
+		},
+		{
+			Cluster: "uuid-0",
+			Bucket:  "B0",
+			Result:  &values.CheckerResult{Name: values.CheckResidentRatio, Status: values.WarnCheckerStatus},
+		},
+		{
+			Cluster: "uuid-0",
+			Result:  &values.CheckerResult{Name: values.CheckMixedMode, Status: values.GoodCheckerStatus},
+		},
+	}
+
+	for _, result := range results {
+		require.NoError(t, store.SetCheckerResult(result))
+	}
+
+	return store
+}
+
+func TestDispatch(t *testing.T) {
+	store := createTestStore(t)
+	am := newTestAlertmanager(t, http.StatusOK)
+	dispatcher := NewDispatcher(store, []string{am.URL}, time.Minute)
+
+	require.NoError(t, dispatcher.Dispatch())
+
+	alerts := am.last(t)
+	require.Len(t, alerts, 2)
+
+	bucketAlert, nodeAlert := alerts[0], alerts[1]
+	require.Equal(t, map[string]string{
+		"alertname":     values.CheckResidentRatio,
+		"severity":      severityWarning,
+		"cluster_uuid":  "uuid-0",
+		"cluster_name":  "cluster-0",
+		"cluster_alias": "a-0",
+		"bucket":        "B0",
+		"checker_id":    values.AllCheckerDefs[values.CheckResidentRatio].ID,
+	}, bucketAlert.Labels)
+
+	require.Equal(t, map[string]string{
+		"alertname":     values.CheckUnhealthyNode,
+		"severity":      severityCritical,
+		"cluster_uuid":  "uuid-0",
+		"cluster_name":  "cluster-0",
+		"cluster_alias": "a-0",
+		"node":          "N0",
+		"checker_id":    values.AllCheckerDefs[values.CheckUnhealthyNode].ID,
+	}, nodeAlert.Labels)
+	require.Equal(t, "fix it", nodeAlert.Annotations["remediation"])
+	require.Equal(t, `{"status":"unhealthy"}`, nodeAlert.Annotations["value"])
+	require.WithinDuration(t, nodeAlert.StartsAt.Add(endsAtFactor*time.Minute), nodeAlert.EndsAt, time.Second)
+
+	t.Run("resend-keeps-start", func(t *testing.T) {
+		require.NoError(t, dispatcher.Dispatch())
+
+		resent := am.last(t)
+		require.Len(t, resent, 2)
+		require.True(t, nodeAlert.StartsAt.Equal(resent[1].StartsAt))
+	})
+
+	t.Run("resolve-dismissed-and-heartbeat", func(t *testing.T) {
+		require.NoError(t, store.AddDismissal(values.Dismissal{
+			ID:          "d0",
+			Level:       values.AllDismissLevel,
+			CheckerName: values.CheckResidentRatio,
+			Forever:     true,
+		}))
+		require.NoError(t, store.UpdateCluster(&values.CouchbaseCluster{
+			UUID:           "uuid-0",
+			HeartBeatIssue: values.NoConnectionHeartIssue,
+		}))
+
+		before := time.Now().UTC()
+		require.NoError(t, dispatcher.Dispatch())
+
+		alerts := am.last(t)
+		require.Len(t, alerts, 3)
+
+		byName := make(map[string]*Alert)
+		for _, alert := range alerts {
+			byName[alert.Labels["alertname"]] = alert
+		}
+
+		require.False(t, byName[values.CheckResidentRatio].EndsAt.Before(before))
+		require.False(t, byName[values.CheckResidentRatio].EndsAt.After(time.Now().UTC()))
+		require.True(t, byName[values.CheckUnhealthyNode].EndsAt.After(time.Now().UTC()))
+		require.Equal(t, severityCritical, byName[HeartbeatAlertName].Labels["severity"])
+
+		// once resolved the alert is not sent again
+		require.NoError(t, dispatcher.Dispatch())
+		require.Len(t, am.last(t), 2)
+	})
+}
+
+func TestDispatchFailure(t *testing.T) {
+	store := createTestStore(t)
+	good := newTestAlertmanager(t, http.StatusOK)
+	bad := newTestAlertmanager(t, http.StatusInternalServerError)
+
+	t.Run("one-failing", func(t *testing.T) {
+		require.NoError(t, NewDispatcher(store, []string{bad.URL, good.URL}, time.Minute).Dispatch())
+		require.Len(t, good.last(t), 2)
+	})
+
+	t.Run("all-failing", func(t *testing.T) {
+		dispatcher := NewDispatcher(store, []string{bad.URL}, time.Minute)
+		require.NoError(t, store.DeleteCluster("uuid-0"))
+		dispatcher.active["stale"] = &Alert{Labels: map[string]string{"alertname": "stale"}}
+
+		require.Error(t, dispatcher.Dispatch())
+		// the resolved alert is kept so it can be retried
+		require.Contains(t, dispatcher.active, "stale")
+	})
+}
+
+func TestDispatcherStartStop(t *testing.T) {
+	am := newTestAlertmanager(t, http.StatusOK)
+	dispatcher := NewDispatcher(createTestStore(t), []string{am.URL}, 200*time.Millisecond)
+
+	dispatcher.Start()
+	time.Sleep(500 * time.Millisecond)
+	dispatcher.Stop()
+
+	// it dispatches once on start and then on every tick
+	require.GreaterOrEqual(t, am.count(), 2)
+}
@@ -40,6 +40,9 @@ type Config struct {
 
 	LogCheckLifetime time.Duration
 
+	// HeartbeatHistoryRetention is how long the heartbeat history of the clusters is kept for.
+	HeartbeatHistoryRetention time.Duration
+
 	EncryptKey []byte
 	SignKey    []byte
 	UUID       string
@@ -64,6 +67,13 @@ type Config struct {
 	PrometheusLabelSelector LabelSelectors
 	CouchbaseUser           string
 	CouchbasePassword       string
+
+	// Alertmanager instances to send the alerts to, if none are given alerts are not sent.
+	AlertmanagerURLs        Strings
+	AlertmanagerResendDelay time.Duration
+
+	// CheckerThresholds are the warning and alert limits used by the status checkers.
+	CheckerThresholds CheckerThresholds
 }
 
 func (c *Config) MarshalLogObject(enc zapcore.ObjectEncoder) error {
@@ -82,6 +92,10 @@ func (c *Config) MarshalLogObject(enc zapcore.ObjectEncoder) error {
 	enc.AddString("PrometheusLabelSelector", fmt.Sprint(c.PrometheusLabelSelector))
 	enc.AddString("CouchbaseUser", c.CouchbaseUser)
 	enc.AddDuration("LogCheckLifetime", c.LogCheckLifetime)
+	enc.AddDuration("HeartbeatHistoryRetention", c.HeartbeatHistoryRetention)
+	_ = enc.AddArray("AlertmanagerURLs", c.AlertmanagerURLs)
+	enc.AddDuration("AlertmanagerResendDelay", c.AlertmanagerResendDelay)
+	_ = enc.AddReflected("CheckerThresholds", c.CheckerThresholds)
 
 	// Do not log these as protected:
 	// enc.AddString("", c.AdminPassword)
@@ -0,0 +1,69 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package configuration
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// LoadFile reads a YAML or JSON configuration file. The keys in the file are the names of the command line flags and
+// the values are returned as they would be given on the command line, with lists joined by commas.
+func LoadFile(path string) (map[string]string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read config file: %w", err)
+	}
+
+	// JSON is valid YAML so both can be decoded the same way
+	var raw map[string]interface{}
+	if err = yaml.UnmarshalStrict(data, &raw); err != nil {
+		return nil, fmt.Errorf("could not parse config file: %w", err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		str, err := valueToString(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for '%s': %w", key, err)
+		}
+
+		values[key] = str
+	}
+
+	return values, nil
+}
+
+func valueToString(value interface{}) (string, error) {
+	switch value := value.(type) {
+	case nil:
+		return "", nil
+	case string, bool, int, int64, uint64, float64:
+		return fmt.Sprint(value), nil
+	case []interface{}:
+		parts := make([]string, 0, len(value))
+		for _, item := range value {
+			switch item.(type) {
+			case []interface{}, map[interface{}]interface{}:
+				return "", fmt.Errorf("lists can only contain plain values")
+			}
+
+			str, err := valueToString(item)
+			if err != nil {
+				return "", err
+			}
+
+			parts = append(parts, str)
+		}
+
+		return strings.Join(parts, ","), nil
+	default:
+		return "", fmt.Errorf("expected a plain value or a list but got %T", value)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package configuration
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFile(t *testing.T) {
+	type testCase struct {
+		name          string
+		file          string
+		contents      string
+		expected      map[string]string
+		expectedError string
+	}
+
+	cases := []testCase{
+		{
+			name: "YAML",
+			file: "config.yaml",
+			contents: `
+sqlite-db: /data/db.sqlite
+http-port: 7196
+enable-admin-api: false
+heart-frequency: 30s
+alertmanager-urls:
+  - http://am-0:9093
+  - http://am-1:9093
+`,
+			expected: map[string]string{
+				"sqlite-db":         "/data/db.sqlite",
+				"http-port":         "7196",
+				"enable-admin-api":  "false",
+				"heart-frequency":   "30s",
+				"alertmanager-urls": "http://am-0:9093,http://am-1:9093",
+			},
+		},
+		{
+			name:     "JSON",
+			file:     "config.json",
+			contents: `{"sqlite-db": "/data/db.sqlite", "http-port": 7196, "prometheus-url": null}`,
+			expected: map[string]string{
+				"sqlite-db":      "/data/db.sqlite",
+				"http-port":      "7196",
+				"prometheus-url": "",
+			},
+		},
+		{
+			name:          "nestedValue",
+			file:          "config.yaml",
+			contents:      "heart-frequency:\n  every: 30s\n",
+			expectedError: "invalid value for 'heart-frequency'",
+		},
+		{
+			name:          "duplicateKey",
+			file:          "config.yaml",
+			contents:      "http-port: 1\nhttp-port: 2\n",
+			expectedError: "could not parse config file",
+		},
+		{
+			name:          "notAMap",
+			file:          "config.yaml",
+			contents:      "- http-port\n",
+			expectedError: "could not parse config file",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.file)
+			require.NoError(t, ioutil.WriteFile(path, []byte(tc.contents), 0o600))
+
+			values, err := LoadFile(path)
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, values)
+		})
+	}
+
+	t.Run("missingFile", func(t *testing.T) {
+		_, err := LoadFile(filepath.Join(t.TempDir(), "missing.yaml"))
+		require.Error(t, err)
+	})
+}
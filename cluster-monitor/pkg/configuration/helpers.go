@@ -7,6 +7,7 @@ package configuration
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 )
 
@@ -25,3 +26,27 @@ func ParseLabelSelectors(input string) (LabelSelectors, error) {
 	}
 	return result, nil
 }
+
+// ParseURLs parses a comma separated list of HTTP(S) base URLs. Empty entries are ignored.
+func ParseURLs(input string) (Strings, error) {
+	result := make(Strings, 0)
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		parsed, err := url.Parse(part)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse URL '%s': %w", part, err)
+		}
+
+		if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("URL '%s' must be an absolute http or https URL", part)
+		}
+
+		result = append(result, strings.TrimSuffix(part, "/"))
+	}
+
+	return result, nil
+}
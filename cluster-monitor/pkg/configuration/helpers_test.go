@@ -36,3 +36,23 @@ func TestParseLabelSelector(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestParseURLs(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseURLs("http://alertmanager-0:9093/, https://alertmanager-1:9093")
+		require.NoError(t, err)
+		require.Equal(t, Strings{"http://alertmanager-0:9093", "https://alertmanager-1:9093"}, result)
+	})
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+		result, err := ParseURLs("")
+		require.NoError(t, err)
+		require.Equal(t, Strings{}, result)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		_, err := ParseURLs("alertmanager:9093")
+		require.Error(t, err)
+	})
+}
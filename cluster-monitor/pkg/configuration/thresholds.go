@@ -0,0 +1,85 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package configuration
+
+import (
+	"fmt"
+)
+
+// CheckerThresholds are the limits the checkers use to decide whether a result is a warning or an alert. Usages are
+// percentages between 0 and 100.
+type CheckerThresholds struct {
+	SwapUsageWarn  float64
+	SwapUsageAlert float64
+
+	DiskUsageWarn  float64
+	DiskUsageAlert float64
+
+	MemoryUsageWarn  float64
+	MemoryUsageAlert float64
+
+	// MinimumNodeMemory is the least memory, in bytes, a node should have.
+	MinimumNodeMemory uint64
+
+	// EOMWarnMonths is how many months before the end of maintenance of a version the nodes running it are warned
+	// about.
+	EOMWarnMonths int
+
+	// LargeCheckpointMemory is the memory, in bytes, above which the checkpoints of a vBucket are reported as large.
+	LargeCheckpointMemory uint64
+	// LargeCheckpointQuota is the percentage of the bucket quota above which the checkpoints of a vBucket are reported
+	// as large.
+	LargeCheckpointQuota float64
+	// LargeCheckpointItems is the number of items above which the checkpoints of a vBucket are reported as large, zero
+	// means the number of items is not checked.
+	LargeCheckpointItems uint64
+}
+
+// DefaultCheckerThresholds are the thresholds given in the checker documentation.
+var DefaultCheckerThresholds = CheckerThresholds{
+	SwapUsageWarn:     0,
+	SwapUsageAlert:    90,
+	DiskUsageWarn:     90,
+	DiskUsageAlert:    95,
+	MemoryUsageWarn:   90,
+	MemoryUsageAlert:  95,
+	MinimumNodeMemory: 4 * 1024 * 1024 * 1024,
+	EOMWarnMonths:     6,
+
+	LargeCheckpointMemory: 50 * 1024 * 1024,
+	LargeCheckpointQuota:  1,
+}
+
+// Validate checks that the usages are percentages and that the warnings are not above the alerts.
+func (t CheckerThresholds) Validate() error {
+	for _, usage := range []struct {
+		name        string
+		warn, alert float64
+	}{
+		{name: "swap usage", warn: t.SwapUsageWarn, alert: t.SwapUsageAlert},
+		{name: "disk usage", warn: t.DiskUsageWarn, alert: t.DiskUsageAlert},
+		{name: "memory usage", warn: t.MemoryUsageWarn, alert: t.MemoryUsageAlert},
+	} {
+		if usage.warn < 0 || usage.alert > 100 {
+			return fmt.Errorf("%s thresholds must be between 0 and 100", usage.name)
+		}
+
+		if usage.warn > usage.alert {
+			return fmt.Errorf("%s warning threshold %g is above the alert threshold %g", usage.name, usage.warn,
+				usage.alert)
+		}
+	}
+
+	if t.EOMWarnMonths < 0 {
+		return fmt.Errorf("the end of maintenance warning must not be negative")
+	}
+
+	if t.LargeCheckpointQuota < 0 || t.LargeCheckpointQuota > 100 {
+		return fmt.Errorf("large checkpoint quota threshold must be between 0 and 100")
+	}
+
+	return nil
+}
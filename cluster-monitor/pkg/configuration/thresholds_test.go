@@ -0,0 +1,36 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package configuration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckerThresholdsValidate(t *testing.T) {
+	require.NoError(t, DefaultCheckerThresholds.Validate())
+
+	thresholds := DefaultCheckerThresholds
+	thresholds.DiskUsageWarn = 99
+	require.Error(t, thresholds.Validate())
+
+	thresholds = DefaultCheckerThresholds
+	thresholds.MemoryUsageAlert = 101
+	require.Error(t, thresholds.Validate())
+
+	thresholds = DefaultCheckerThresholds
+	thresholds.SwapUsageWarn = -1
+	require.Error(t, thresholds.Validate())
+
+	thresholds = DefaultCheckerThresholds
+	thresholds.EOMWarnMonths = -1
+	require.Error(t, thresholds.Validate())
+
+	thresholds = DefaultCheckerThresholds
+	thresholds.LargeCheckpointQuota = 101
+	require.Error(t, thresholds.Validate())
+}
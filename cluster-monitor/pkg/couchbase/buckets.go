@@ -7,14 +7,15 @@ package couchbase
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
 )
 
-func (c *Client) GetPoolsBucket() ([]Bucket, error) {
-	res, err := c.get(PoolsBucketEndpoint)
+func (c *Client) GetPoolsBucket(ctx context.Context) ([]Bucket, error) {
+	res, err := c.get(ctx, PoolsBucketEndpoint)
 	if err != nil {
 		return nil, fmt.Errorf("could not get pools buckets data: %w", err)
 	}
@@ -27,8 +28,8 @@ func (c *Client) GetPoolsBucket() ([]Bucket, error) {
 	return buckets, err
 }
 
-func (c *Client) GetBucketsSummary() (values.BucketsSummary, error) {
-	res, err := c.get(PoolsBucketEndpoint)
+func (c *Client) GetBucketsSummary(ctx context.Context) (values.BucketsSummary, error) {
+	res, err := c.get(ctx, PoolsBucketEndpoint)
 	if err != nil {
 		return nil, fmt.Errorf("could not get pools buckets data: %w", err)
 	}
@@ -36,8 +37,8 @@ func (c *Client) GetBucketsSummary() (values.BucketsSummary, error) {
 	return values.MarshallBucketsSummaryFromRest(bytes.NewReader(res.Body))
 }
 
-func (c *Client) GetBucketStats(bucketName string) (*values.BucketStat, error) {
-	res, err := c.get(PoolsBucketStatsEndpoint.Format(bucketName))
+func (c *Client) GetBucketStats(ctx context.Context, bucketName string) (*values.BucketStat, error) {
+	res, err := c.get(ctx, PoolsBucketStatsEndpoint.Format(bucketName))
 	if err != nil {
 		return nil, fmt.Errorf("could not get pools bucket stats data: %w", err)
 	}
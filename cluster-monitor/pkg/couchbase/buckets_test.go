@@ -6,6 +6,7 @@
 package couchbase
 
 import (
+	"context"
 	"net/http"
 	"reflect"
 	"testing"
@@ -70,7 +71,7 @@ func TestGetPoolsBucket(t *testing.T) {
 
 	t.Run("401", func(t *testing.T) {
 		statusCode = http.StatusUnauthorized
-		_, err := client.GetPoolsBucket()
+		_, err := client.GetPoolsBucket(context.Background())
 		if err == nil {
 			t.Fatalf("Expected and error but got <nil>")
 		}
@@ -97,7 +98,7 @@ func TestGetPoolsBucket(t *testing.T) {
 			},
 		}
 
-		buckets, err := client.GetPoolsBucket()
+		buckets, err := client.GetPoolsBucket(context.Background())
 		if err != nil {
 			t.Fatalf("Unexpected error getting buckets: %v", err)
 		}
@@ -175,7 +176,7 @@ func TestGetBucketSummary(t *testing.T) {
 
 	t.Run("401", func(t *testing.T) {
 		statusCode = http.StatusUnauthorized
-		_, err := client.GetBucketsSummary()
+		_, err := client.GetBucketsSummary(context.Background())
 		require.Error(t, err)
 	})
 
@@ -210,7 +211,7 @@ func TestGetBucketSummary(t *testing.T) {
 			},
 		}
 
-		buckets, err := client.GetBucketsSummary()
+		buckets, err := client.GetBucketsSummary(context.Background())
 		require.NoError(t, err)
 		require.Equal(t, expected, buckets)
 	})
@@ -243,7 +244,7 @@ func TestGetBucketStats(t *testing.T) {
 		statusCode = http.StatusOK
 		data = []byte(`{"op": {"samples": {"vb_active_resident_items_ratio": [7]}}}`)
 
-		res, err := client.GetBucketStats("bucket")
+		res, err := client.GetBucketStats(context.Background(), "bucket")
 		require.NoError(t, err)
 		require.Equal(t, &values.BucketStat{VbActiveRatio: []float64{7}}, res)
 	})
@@ -251,7 +252,7 @@ func TestGetBucketStats(t *testing.T) {
 	t.Run("NotFound", func(t *testing.T) {
 		statusCode = http.StatusNotFound
 
-		_, err := client.GetBucketStats("bucket")
+		_, err := client.GetBucketStats(context.Background(), "bucket")
 		require.Error(t, err)
 		require.ErrorIs(t, err, values.ErrNotFound)
 	})
@@ -6,10 +6,12 @@
 package couchbase
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
@@ -26,10 +28,17 @@ type Client struct {
 	ClusterInfo   *PoolsMetadata
 	BootstrapTime time.Time
 	authSettings  *clientAuth
+
+	// infoLock protects ClusterInfo as clients that are shared can be refreshed while in use.
+	infoLock sync.RWMutex
+	// requestTimeout bounds every request made by the client on top of the context given, zero means no bound.
+	requestTimeout time.Duration
 }
 
-// NewClient creates a new Couchbase REST client to use when communicating with the cluster.
-func NewClient(hosts []string, user, password string, config *tls.Config, thisNodeOnly bool) (*Client, error) {
+// NewClient creates a new Couchbase REST client to use when communicating with the cluster. The bootstrap itself cannot
+// be cancelled so it is only bounded by the REST client timeout, ctx is used for the requests that follow it.
+func NewClient(ctx context.Context, hosts []string, user, password string, config *tls.Config,
+	thisNodeOnly bool) (*Client, error) {
 	c := &Client{}
 
 	opts := cbrest.ClientOptions{
@@ -55,12 +64,72 @@ func NewClient(hosts []string, user, password string, config *tls.Config, thisNo
 		DeveloperPreview: c.internalClient.DeveloperPreview(),
 	}
 
-	res, err := c.get(cbrest.EndpointPoolsDefault)
+	if err = c.getClusterInfo(ctx, c.ClusterInfo); err != nil {
+		c.Close()
+		return nil, err
+	}
+
+	c.BootstrapTime = time.Now().UTC()
+
+	c.authSettings = &clientAuth{
+		tlsConfig: config,
+		username:  user,
+		password:  password,
+	}
+
+	return c, nil
+}
+
+// Refresh gets the cluster information again so clients that are reused see the current state of the cluster. The
+// client is not bootstrapped again so the cluster UUID is taken from /pools instead.
+func (c *Client) Refresh(ctx context.Context) error {
+	res, err := c.get(ctx, cbrest.EndpointPools)
+	if err != nil {
+		return fmt.Errorf("could not get cluster UUID: %w", err)
+	}
+
+	var overlay struct {
+		UUID json.RawMessage `json:"uuid"`
+	}
+
+	if err = json.Unmarshal(res.Body, &overlay); err != nil {
+		return fmt.Errorf("could not unmarshal server response: %w", err)
+	}
+
+	// uninitialized nodes give an empty list instead of a UUID, that is treated as no UUID
+	var uuid string
+	_ = json.Unmarshal(overlay.UUID, &uuid)
+
+	current := c.GetClusterInfo()
+	info := &PoolsMetadata{
+		ClusterUUID:      uuid,
+		Enterprise:       current.Enterprise,
+		DeveloperPreview: current.DeveloperPreview,
+	}
+
+	if err = c.getClusterInfo(ctx, info); err != nil {
+		return err
+	}
+
+	c.infoLock.Lock()
+	defer c.infoLock.Unlock()
+	c.ClusterInfo = info
+	return nil
+}
+
+// Close releases the resources used by the client, it should not be used afterwards.
+func (c *Client) Close() {
+	c.internalClient.Close()
+}
+
+// getClusterInfo fills in the cluster name, totals and nodes of info from /pools/default and /pools/nodes.
+func (c *Client) getClusterInfo(ctx context.Context, info *PoolsMetadata) error {
+	res, err := c.get(ctx, cbrest.EndpointPoolsDefault)
 	if err != nil {
-		return nil, fmt.Errorf("error retrieveing cluster name: %w", err)
+		return fmt.Errorf("error retrieveing cluster name: %w", err)
 	}
 
-	c.ClusterInfo.PoolsRaw = res.Body
+	info.PoolsRaw = res.Body
 
 	overlay := struct {
 		ClusterName   string `json:"clusterName"`
@@ -77,32 +146,24 @@ func NewClient(hosts []string, user, password string, config *tls.Config, thisNo
 		} `json:"storageTotals"`
 	}{}
 
-	if err := json.Unmarshal(c.ClusterInfo.PoolsRaw, &overlay); err != nil {
-		return nil, fmt.Errorf("could not get cluster name: %w", err)
+	if err := json.Unmarshal(info.PoolsRaw, &overlay); err != nil {
+		return fmt.Errorf("could not get cluster name: %w", err)
 	}
 
-	c.ClusterInfo.ClusterName = overlay.ClusterName
-	c.ClusterInfo.ClusterInfo = &values.ClusterInfo{
+	info.ClusterName = overlay.ClusterName
+	info.ClusterInfo = &values.ClusterInfo{
 		RAMQuota:       overlay.StorageTotals.RAM.QuotaTotal,
 		RAMUsed:        overlay.StorageTotals.RAM.QuotaUsed,
 		DiskTotal:      overlay.StorageTotals.HDD.QuotaTotal,
 		DiskUsed:       overlay.StorageTotals.HDD.Used,
 		DiskUsedByData: overlay.StorageTotals.HDD.UsedByData,
 	}
-	c.ClusterInfo.NodesSummary, err = c.GetNodesSummary()
+	info.NodesSummary, err = c.GetNodesSummary(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("could not get node summary: %w", err)
-	}
-
-	c.BootstrapTime = time.Now().UTC()
-
-	c.authSettings = &clientAuth{
-		tlsConfig: config,
-		username:  user,
-		password:  password,
+		return fmt.Errorf("could not get node summary: %w", err)
 	}
 
-	return c, nil
+	return nil
 }
 
 func (c *Client) GetBootstrap() time.Time {
@@ -110,6 +171,8 @@ func (c *Client) GetBootstrap() time.Time {
 }
 
 func (c *Client) GetClusterInfo() *PoolsMetadata {
+	c.infoLock.RLock()
+	defer c.infoLock.RUnlock()
 	return c.ClusterInfo
 }
 
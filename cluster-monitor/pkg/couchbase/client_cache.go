@@ -0,0 +1,181 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package couchbase
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"go.uber.org/zap"
+)
+
+// ClientCache keeps the REST clients of the clusters so they are bootstrapped once and reused rather than every time
+// a cluster is contacted. Clients are keyed by cluster UUID and are replaced when the hosts, credentials, CA
+// certificate or request timeout of the cluster change. Clients that are not used for the idle timeout are closed the
+// next time the cache is used.
+type ClientCache struct {
+	idleTimeout time.Duration
+
+	// now and newClient are replaced in the tests.
+	now       func() time.Time
+	newClient func(ctx context.Context, hosts []string, user, password string, config *tls.Config,
+		thisNodeOnly bool) (*Client, error)
+
+	lock    sync.Mutex
+	clients map[cacheKey]*cacheEntry
+}
+
+// cacheKey identifies a cached client, node is only set for clients that only talk to that node.
+type cacheKey struct {
+	cluster string
+	node    string
+}
+
+type cacheEntry struct {
+	client *Client
+	// fingerprint is a hash of the connection details the client was created with so changes to them can be spotted.
+	fingerprint [sha256.Size]byte
+	lastUsed    time.Time
+}
+
+// NewClientCache creates a cache that closes the clients that have not been used for idleTimeout.
+func NewClientCache(idleTimeout time.Duration) *ClientCache {
+	return &ClientCache{
+		idleTimeout: idleTimeout,
+		now:         time.Now,
+		newClient:   NewClient,
+		clients:     make(map[cacheKey]*cacheEntry),
+	}
+}
+
+// GetClient returns a client for the whole cluster. The cluster configuration is not polled by the clients so one is
+// created again when the hosts of the cluster change.
+func (c *ClientCache) GetClient(ctx context.Context, cluster *values.CouchbaseCluster) (*Client, error) {
+	return c.get(ctx, cacheKey{cluster: cluster.UUID}, cluster, cluster.NodesSummary.GetHosts(), false)
+}
+
+// GetNodeClient returns a client that only talks to the node with the given host.
+func (c *ClientCache) GetNodeClient(ctx context.Context, cluster *values.CouchbaseCluster,
+	host string) (*Client, error) {
+	return c.get(ctx, cacheKey{cluster: cluster.UUID, node: host}, cluster, []string{host}, true)
+}
+
+func (c *ClientCache) get(ctx context.Context, key cacheKey, cluster *values.CouchbaseCluster, hosts []string,
+	thisNodeOnly bool) (*Client, error) {
+	fingerprint := clientFingerprint(cluster, hosts)
+
+	c.lock.Lock()
+	c.evictIdle()
+	if entry, ok := c.clients[key]; ok && entry.fingerprint == fingerprint {
+		entry.lastUsed = c.now()
+		c.lock.Unlock()
+		return entry.client, nil
+	}
+	c.lock.Unlock()
+
+	// bootstrapping can take a while so it is done without holding the lock
+	timeout := cluster.GetRequestTimeout()
+	createCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, err := c.newClient(createCtx, hosts, cluster.User, cluster.Password, cluster.GetTLSConfig(), thisNodeOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	client.requestTimeout = timeout
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// another caller could have created a client for the same details in the meantime, keep only one of them
+	if entry, ok := c.clients[key]; ok {
+		if entry.fingerprint == fingerprint {
+			client.Close()
+			entry.lastUsed = c.now()
+			return entry.client, nil
+		}
+
+		entry.client.Close()
+	}
+
+	c.clients[key] = &cacheEntry{client: client, fingerprint: fingerprint, lastUsed: c.now()}
+	return client, nil
+}
+
+// Invalidate closes and removes all the clients of the cluster, it should be called when the cluster connection
+// details change or the cluster is removed.
+func (c *ClientCache) Invalidate(uuid string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for key, entry := range c.clients {
+		if key.cluster == uuid {
+			entry.client.Close()
+			delete(c.clients, key)
+		}
+	}
+}
+
+// Close closes and removes all the clients.
+func (c *ClientCache) Close() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for key, entry := range c.clients {
+		entry.client.Close()
+		delete(c.clients, key)
+	}
+}
+
+// evictIdle closes the clients that have not been used for the idle timeout. The lock must be held by the caller.
+func (c *ClientCache) evictIdle() {
+	now := c.now()
+	for key, entry := range c.clients {
+		if now.Sub(entry.lastUsed) < c.idleTimeout {
+			continue
+		}
+
+		zap.S().Debugw("(Client Cache) Closing idle client", "cluster", key.cluster, "node", key.node)
+		entry.client.Close()
+		delete(c.clients, key)
+	}
+}
+
+// clientFingerprint hashes the details a client is created with, the order of the hosts does not matter.
+func clientFingerprint(cluster *values.CouchbaseCluster, hosts []string) [sha256.Size]byte {
+	sorted := append([]string(nil), hosts...)
+	sort.Strings(sorted)
+
+	hash := sha256.New()
+	for _, host := range sorted {
+		_, _ = hash.Write([]byte(host))
+		_, _ = hash.Write([]byte{0})
+	}
+
+	_, _ = hash.Write([]byte(cluster.User))
+	_, _ = hash.Write([]byte{0})
+	_, _ = hash.Write([]byte(cluster.Password))
+	_, _ = hash.Write([]byte{0})
+	_, _ = hash.Write(cluster.CaCert)
+	_, _ = hash.Write([]byte{0})
+	_, _ = hash.Write([]byte(cluster.GetRequestTimeout().String()))
+
+	// the TLS configuration also depends on whether the cluster is Enterprise
+	if cluster.Enterprise {
+		_, _ = hash.Write([]byte{1})
+	}
+
+	var fingerprint [sha256.Size]byte
+	copy(fingerprint[:], hash.Sum(nil))
+	return fingerprint
+}
@@ -0,0 +1,133 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package couchbase
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createTestClientCache(t *testing.T) (*ClientCache, *values.CouchbaseCluster, *int) {
+	handler := &TestHandler{
+		ClusterUUID:      "uuid-0",
+		Nodes:            []TestNode{{NodeUUID: "N0", Hostname: "127.0.0.1:9000", Status: "healthy"}},
+		NodesReturnCode:  http.StatusOK,
+		BucketReturnCode: http.StatusOK,
+	}
+
+	handler.Start(t, false, true)
+	t.Cleanup(handler.Close)
+
+	cache := NewClientCache(time.Minute)
+	t.Cleanup(cache.Close)
+
+	var created int
+	cache.newClient = func(ctx context.Context, hosts []string, user, password string, config *tls.Config,
+		thisNodeOnly bool) (*Client, error) {
+		created++
+		return NewClient(ctx, hosts, user, password, config, thisNodeOnly)
+	}
+
+	cluster := &values.CouchbaseCluster{
+		UUID:         "uuid-0",
+		User:         "user",
+		Password:     "password",
+		NodesSummary: values.NodesSummary{{NodeUUID: "N0", Host: handler.URL()}},
+	}
+
+	return cache, cluster, &created
+}
+
+func TestClientCacheReuse(t *testing.T) {
+	cache, cluster, created := createTestClientCache(t)
+
+	client, err := cache.GetClient(context.Background(), cluster)
+	require.NoError(t, err)
+	require.Equal(t, "uuid-0", client.GetClusterInfo().ClusterUUID)
+
+	again, err := cache.GetClient(context.Background(), cluster)
+	require.NoError(t, err)
+	require.Same(t, client, again)
+	require.Equal(t, 1, *created)
+
+	// node clients are kept separately from the cluster ones
+	nodeClient, err := cache.GetNodeClient(context.Background(), cluster, cluster.NodesSummary[0].Host)
+	require.NoError(t, err)
+	require.NotSame(t, client, nodeClient)
+	require.Equal(t, 2, *created)
+}
+
+func TestClientCacheDetailsChanged(t *testing.T) {
+	cache, cluster, created := createTestClientCache(t)
+
+	client, err := cache.GetClient(context.Background(), cluster)
+	require.NoError(t, err)
+
+	cluster.Password = "new-password"
+	changed, err := cache.GetClient(context.Background(), cluster)
+	require.NoError(t, err)
+	require.NotSame(t, client, changed)
+	require.Equal(t, 2, *created)
+	require.Len(t, cache.clients, 1)
+}
+
+func TestClientCacheInvalidate(t *testing.T) {
+	cache, cluster, created := createTestClientCache(t)
+
+	_, err := cache.GetClient(context.Background(), cluster)
+	require.NoError(t, err)
+	_, err = cache.GetNodeClient(context.Background(), cluster, cluster.NodesSummary[0].Host)
+	require.NoError(t, err)
+
+	// invalidating other clusters does not affect this one
+	cache.Invalidate("uuid-1")
+	require.Len(t, cache.clients, 2)
+
+	cache.Invalidate(cluster.UUID)
+	require.Empty(t, cache.clients)
+
+	_, err = cache.GetClient(context.Background(), cluster)
+	require.NoError(t, err)
+	require.Equal(t, 3, *created)
+}
+
+func TestClientCacheIdleEviction(t *testing.T) {
+	cache, cluster, created := createTestClientCache(t)
+
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	client, err := cache.GetClient(context.Background(), cluster)
+	require.NoError(t, err)
+
+	// using the client keeps it in the cache
+	now = now.Add(50 * time.Second)
+	again, err := cache.GetClient(context.Background(), cluster)
+	require.NoError(t, err)
+	require.Same(t, client, again)
+
+	now = now.Add(time.Minute)
+	evicted, err := cache.GetClient(context.Background(), cluster)
+	require.NoError(t, err)
+	require.NotSame(t, client, evicted)
+	require.Equal(t, 2, *created)
+}
+
+func TestClientCacheError(t *testing.T) {
+	cache, cluster, _ := createTestClientCache(t)
+
+	cluster.NodesSummary = nil
+	_, err := cache.GetClient(context.Background(), cluster)
+	require.Error(t, err)
+	require.Empty(t, cache.clients)
+}
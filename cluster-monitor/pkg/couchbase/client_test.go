@@ -6,9 +6,11 @@
 package couchbase
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
 
@@ -37,7 +39,7 @@ func TestNewClientError(t *testing.T) {
 
 	t.Run("no-hosts", func(t *testing.T) {
 		errorCode = http.StatusOK
-		_, err := NewClient(nil, "user", "password", nil, false)
+		_, err := NewClient(context.Background(), nil, "user", "password", nil, false)
 		require.Error(t, err)
 	})
 
@@ -67,7 +69,7 @@ func TestNewClientError(t *testing.T) {
 			count = 0
 			body = tc.body
 
-			_, err := NewClient([]string{testCluster.URL()}, "user", "password", nil, false)
+			_, err := NewClient(context.Background(), []string{testCluster.URL()}, "user", "password", nil, false)
 			require.Error(t, err)
 
 			var bootstrapFailure *cbrest.BootstrapFailureError
@@ -126,7 +128,7 @@ func TestNewClient(t *testing.T) {
 	handler.Start(t, false, true)
 	defer handler.Close()
 
-	client, err := NewClient([]string{handler.URL()}, "user", "password", nil, false)
+	client, err := NewClient(context.Background(), []string{handler.URL()}, "user", "password", nil, false)
 	require.NoError(t, err)
 
 	// make sure that the data is set correctly in the client
@@ -157,3 +159,75 @@ func TestNewClient(t *testing.T) {
 
 	require.Equal(t, expectedNodeSummary, client.ClusterInfo.NodesSummary)
 }
+
+func TestClientRefresh(t *testing.T) {
+	handler := &TestHandler{
+		ClusterUUID:      "cluster_x",
+		PoolsDefault:     TestPoolsDefaultData{ClusterName: "grumpy"},
+		Nodes:            []TestNode{{NodeUUID: "node-0", Hostname: "127.0.0.1:9000", Status: "warmup"}},
+		NodesReturnCode:  http.StatusOK,
+		BucketReturnCode: http.StatusOK,
+	}
+
+	handler.Start(t, false, true)
+	defer handler.Close()
+
+	client, err := NewClient(context.Background(), []string{handler.URL()}, "user", "password", nil, false)
+	require.NoError(t, err)
+	defer client.Close()
+
+	bootstrap := client.GetBootstrap()
+	old := client.GetClusterInfo()
+	require.Equal(t, "grumpy", old.ClusterName)
+
+	handler.PoolsDefault.ClusterName = "happy"
+	handler.Nodes[0].Status = "healthy"
+	require.NoError(t, client.Refresh(context.Background()))
+
+	info := client.GetClusterInfo()
+	require.Equal(t, "cluster_x", info.ClusterUUID)
+	require.True(t, info.Enterprise)
+	require.Equal(t, "happy", info.ClusterName)
+	require.Len(t, info.NodesSummary, 1)
+	require.Equal(t, "healthy", info.NodesSummary[0].Status)
+
+	// the client is not bootstrapped again and the previous information is left untouched
+	require.Equal(t, bootstrap, client.GetBootstrap())
+	require.Equal(t, "grumpy", old.ClusterName)
+}
+
+func TestClientRequestTimeout(t *testing.T) {
+	handlers := make(cbrest.TestHandlers)
+	handlers.Add(http.MethodGet, string(AutoCompactionSettingsEndpoint), func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	})
+
+	cluster := cbrest.NewTestCluster(t, cbrest.TestClusterOptions{
+		Enterprise: true,
+		UUID:       "cluster_0",
+		Nodes:      cbrest.TestNodes{{}},
+		Handlers:   handlers,
+	})
+	defer cluster.Close()
+
+	client := getTestClient(t, cluster.URL())
+	client.requestTimeout = 50 * time.Millisecond
+
+	t.Run("timeout", func(t *testing.T) {
+		_, err := client.GetAutoCompactionSettings(context.Background())
+		require.Error(t, err)
+		require.True(t, IsTimeout(err))
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := client.GetAutoCompactionSettings(ctx)
+		require.Error(t, err)
+		require.False(t, IsTimeout(err))
+	})
+}
@@ -19,7 +19,8 @@ const (
 	UILogsEndpoint   cbrest.Endpoint = "/logs"
 	SASLLogsEndpoint cbrest.Endpoint = "/sasl_logs/%s"
 
-	AutoFailOverSettings cbrest.Endpoint = "/settings/autoFailover"
+	AutoFailOverSettings           cbrest.Endpoint = "/settings/autoFailover"
+	AutoCompactionSettingsEndpoint cbrest.Endpoint = "/settings/autoCompaction"
 
 	PrometheusQueryEndpoint cbrest.Endpoint = "/_prometheus/api/v1/query_range"
 
@@ -5,7 +5,12 @@
 
 package couchbase
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+)
 
 type AuthError struct {
 	Authentication bool
@@ -19,3 +24,14 @@ func (e AuthError) Error() string {
 func (e AuthError) Unwrap() error {
 	return e.err
 }
+
+// IsTimeout returns whether the error is because a request took too long, either due to the context deadline or the
+// HTTP client timeout.
+func IsTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -6,6 +6,7 @@
 package couchbase
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -15,9 +16,9 @@ import (
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
 )
 
-func (c *Client) GetFTSIndexStatus() (values.FTSIndexStatus, error) {
+func (c *Client) GetFTSIndexStatus(ctx context.Context) (values.FTSIndexStatus, error) {
 	// getFTSIndexStatus is a scatter-gather endpoint, so we only need to make the request to one FTS node
-	res, err := c.internalClient.Execute(&cbrest.Request{
+	res, err := c.execute(ctx, &cbrest.Request{
 		Method:             http.MethodGet,
 		Endpoint:           "/api/index",
 		Service:            cbrest.ServiceSearch,
@@ -6,6 +6,7 @@
 package couchbase
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -23,8 +24,8 @@ const (
 	indexStatusEndpoint = "/indexStatus"
 )
 
-func (c *Client) GetGSISettings() (*values.GSISettings, error) {
-	res, err := c.get(gsiSettingsEndpoint)
+func (c *Client) GetGSISettings(ctx context.Context) (*values.GSISettings, error) {
+	res, err := c.get(ctx, gsiSettingsEndpoint)
 	if err != nil {
 		return nil, fmt.Errorf("could not get GSI settings: %w", err)
 	}
@@ -36,9 +37,9 @@ func (c *Client) GetGSISettings() (*values.GSISettings, error) {
 	return &settings, nil
 }
 
-func (c *Client) GetIndexStatus() ([]*values.IndexStatus, error) {
+func (c *Client) GetIndexStatus(ctx context.Context) ([]*values.IndexStatus, error) {
 	// getIndexStatus is a scatter-gather endpoint, so we only need to make the request to one GSI node
-	res, err := c.get(indexStatusEndpoint)
+	res, err := c.get(ctx, indexStatusEndpoint)
 	if err != nil {
 		return nil, fmt.Errorf("could not get index status: %w", err)
 	}
@@ -59,7 +60,7 @@ func (c *Client) GetIndexStatus() ([]*values.IndexStatus, error) {
 	return result.Indexes, nil
 }
 
-func (c *Client) GetIndexStorageStats() ([]*values.IndexStatsStorage, error) {
+func (c *Client) GetIndexStorageStats(ctx context.Context) ([]*values.IndexStatsStorage, error) {
 	indexesStatsStorage := make([]*values.IndexStatsStorage, 0)
 	for _, node := range c.internalClient.Nodes() {
 		// check if this node has the index service on it so we don't create useless clients
@@ -86,7 +87,8 @@ func (c *Client) GetIndexStorageStats() ([]*values.IndexStatsStorage, error) {
 				return fmt.Errorf("could not create client for node %s: %w", host, err)
 			}
 			defer rest.Close()
-			result, err := rest.Execute(&cbrest.Request{
+
+			result, err := c.executeOn(ctx, rest, &cbrest.Request{
 				Method:             http.MethodGet,
 				Endpoint:           "/stats/storage",
 				Service:            cbrest.ServiceGSI,
@@ -6,6 +6,7 @@
 package couchbase
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
@@ -14,8 +15,8 @@ import (
 	"github.com/couchbase/tools-common/cbrest"
 )
 
-func (c *Client) get(Endpoint cbrest.Endpoint) (*cbrest.Response, error) {
-	res, err := c.internalClient.Execute(&cbrest.Request{
+func (c *Client) get(ctx context.Context, Endpoint cbrest.Endpoint) (*cbrest.Response, error) {
+	res, err := c.execute(ctx, &cbrest.Request{
 		Method:             http.MethodGet,
 		Endpoint:           Endpoint,
 		Service:            cbrest.ServiceManagement,
@@ -32,3 +33,32 @@ func (c *Client) get(Endpoint cbrest.Endpoint) (*cbrest.Response, error) {
 
 	return res, getAuthError(err)
 }
+
+// execute runs the request, if the client has a request timeout the request is cancelled once it expires.
+func (c *Client) execute(ctx context.Context, request *cbrest.Request) (*cbrest.Response, error) {
+	return c.executeOn(ctx, c.internalClient, request)
+}
+
+// executeOn runs the request using the given REST client, bounding it by the request timeout of c.
+func (c *Client) executeOn(ctx context.Context, rest *cbrest.Client, request *cbrest.Request) (*cbrest.Response,
+	error) {
+	// cbrest panics when given a context that is already done so it is checked up front
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
+
+	return rest.ExecuteWithContext(ctx, request)
+}
+
+// requestContext derives the context for a single request from ctx, bounding it by the request timeout if there is
+// one.
+func (c *Client) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.requestTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, c.requestTimeout)
+}
@@ -19,21 +19,24 @@ import (
 
 // ClientIFace is an interface that will be used to switch a real REST client for a test one during unit testing.
 type ClientIFace interface {
-	GetPoolsBucket() ([]Bucket, error)
-	GetBucketsSummary() (values.BucketsSummary, error)
-	GetBucketStats(bucketName string) (*values.BucketStat, error)
-	GetAutoFailOverSettings() (*AutoFailoverSettings, error)
-	GetUILogs() ([]UILogEntry, error)
+	GetPoolsBucket(ctx context.Context) ([]Bucket, error)
+	GetBucketsSummary(ctx context.Context) (values.BucketsSummary, error)
+	GetBucketStats(ctx context.Context, bucketName string) (*values.BucketStat, error)
+	GetAutoFailOverSettings(ctx context.Context) (*AutoFailoverSettings, error)
+	GetAutoCompactionSettings(ctx context.Context) (*AutoCompactionSettings, error)
+	GetMemoryQuotas(ctx context.Context) (*MemoryQuotas, error)
+	GetUILogs(ctx context.Context) ([]UILogEntry, error)
 	GetSASLLogs(ctx context.Context, logName string) (io.ReadCloser, error)
 	GetDiagLog(ctx context.Context) (io.ReadCloser, error)
-	GetNodesSummary() (values.NodesSummary, error)
-	GetMetric(start, end, metricName, step string) (*Metric, error)
-	GetNodeStorage() (*values.Storage, error)
-	GetIndexStatus() ([]*values.IndexStatus, error)
-	GetFTSIndexStatus() (values.FTSIndexStatus, error)
-	PingService(service cbrest.Service) error
+	GetNodesSummary(ctx context.Context) (values.NodesSummary, error)
+	GetMetric(ctx context.Context, start, end, metricName, step string) (*Metric, error)
+	GetNodeStorage(ctx context.Context) (*values.Storage, error)
+	GetIndexStatus(ctx context.Context) ([]*values.IndexStatus, error)
+	GetFTSIndexStatus(ctx context.Context) (values.FTSIndexStatus, error)
+	PingService(ctx context.Context, service cbrest.Service) error
 	GetBootstrap() time.Time
 	GetClusterInfo() *PoolsMetadata
-	GetServerGroups() ([]values.ServerGroup, error)
-	GetIndexStorageStats() ([]*values.IndexStatsStorage, error)
+	GetServerGroups(ctx context.Context) ([]values.ServerGroup, error)
+	GetIndexStorageStats(ctx context.Context) ([]*values.IndexStatsStorage, error)
+	GetGSISettings(ctx context.Context) (*values.GSISettings, error)
 }
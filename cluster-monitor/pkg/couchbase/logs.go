@@ -17,8 +17,8 @@ import (
 	"github.com/couchbase/tools-common/cbrest"
 )
 
-func (c *Client) GetUILogs() ([]UILogEntry, error) {
-	res, err := c.get(UILogsEndpoint)
+func (c *Client) GetUILogs(ctx context.Context) ([]UILogEntry, error) {
+	res, err := c.get(ctx, UILogsEndpoint)
 	if err != nil {
 		return nil, fmt.Errorf("could not get UI logs: %w", err)
 	}
@@ -45,6 +45,11 @@ func (c *Client) GetDiagLog(ctx context.Context) (io.ReadCloser, error) {
 
 // GetLogREST returns the response body reader. It is the callers responsibility to close the body.
 func (c *Client) GetLogREST(ctx context.Context, endpoint cbrest.Endpoint) (io.ReadCloser, error) {
+	// cbrest panics when given a context that is already done so it is checked up front
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	res, err := c.internalClient.Do(ctx, &cbrest.Request{
 		Method:             http.MethodGet,
 		Endpoint:           endpoint,
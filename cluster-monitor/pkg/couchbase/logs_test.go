@@ -57,7 +57,7 @@ func TestGetUILogs(t *testing.T) {
 
 	t.Run("404", func(t *testing.T) {
 		statusCode = http.StatusNotFound
-		_, err := client.GetUILogs()
+		_, err := client.GetUILogs(context.Background())
 		if !errors.Is(err, values.ErrNotFound) {
 			t.Fatalf("Expected a not found error but got %v", err)
 		}
@@ -65,7 +65,7 @@ func TestGetUILogs(t *testing.T) {
 
 	t.Run("200", func(t *testing.T) {
 		statusCode = http.StatusOK
-		uiLogsOut, err := client.GetUILogs()
+		uiLogsOut, err := client.GetUILogs(context.Background())
 		if err != nil {
 			t.Fatalf("Unexpected error getting UI logs: %v", err)
 		}
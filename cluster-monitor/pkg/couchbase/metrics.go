@@ -6,6 +6,7 @@
 package couchbase
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -20,7 +21,7 @@ import (
 )
 
 // GetMetric collects specific metrics from prometheus query API in 7.0.0+.
-func (c *Client) GetMetric(start, end, metricName, step string) (*Metric, error) {
+func (c *Client) GetMetric(ctx context.Context, start, end, metricName, step string) (*Metric, error) {
 	params := url.Values{}
 	params.Set("query", metricName)
 	params.Set("start", start)
@@ -28,7 +29,7 @@ func (c *Client) GetMetric(start, end, metricName, step string) (*Metric, error)
 	params.Set("step", step)
 
 	var notFound *cbrest.EndpointNotFoundError
-	res, err := c.internalClient.Execute(&cbrest.Request{
+	res, err := c.execute(ctx, &cbrest.Request{
 		Method:             http.MethodGet,
 		Endpoint:           PrometheusQueryEndpoint,
 		Service:            cbrest.ServiceManagement,
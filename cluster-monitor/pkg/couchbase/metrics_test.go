@@ -6,6 +6,7 @@
 package couchbase
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"testing"
@@ -39,15 +40,15 @@ func TestGetMetrics(t *testing.T) {
 
 	t.Run("404", func(t *testing.T) {
 		statusCode = 404
-		_, err := client.GetMetric("2021-04-15T14:00:00.00Z", "2021-04-15T20:00:00.00Z", "sys_cpu_utilization_rate",
-			"10m")
+		_, err := client.GetMetric(context.Background(), "2021-04-15T14:00:00.00Z", "2021-04-15T20:00:00.00Z",
+			"sys_cpu_utilization_rate", "10m")
 		require.ErrorIs(t, err, values.ErrNotFound)
 	})
 
 	t.Run("200", func(t *testing.T) {
 		statusCode = 200
-		outmetric, err := client.GetMetric("2021-04-15T14:00:00.00Z", "2021-04-15T20:00:00.00Z", "sys_cpu_utilization_rate",
-			"10m")
+		outmetric, err := client.GetMetric(context.Background(), "2021-04-15T14:00:00.00Z", "2021-04-15T20:00:00.00Z",
+			"sys_cpu_utilization_rate", "10m")
 		require.NoError(t, err)
 
 		expected := &Metric{
@@ -23,13 +23,36 @@ type ClientIFace struct {
 	mock.Mock
 }
 
-// GetAutoFailOverSettings provides a mock function with given fields:
-func (_m *ClientIFace) GetAutoFailOverSettings() (*couchbase.AutoFailoverSettings, error) {
-	ret := _m.Called()
+// GetAutoCompactionSettings provides a mock function with given fields: ctx
+func (_m *ClientIFace) GetAutoCompactionSettings(ctx context.Context) (*couchbase.AutoCompactionSettings, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *couchbase.AutoCompactionSettings
+	if rf, ok := ret.Get(0).(func(context.Context) *couchbase.AutoCompactionSettings); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*couchbase.AutoCompactionSettings)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAutoFailOverSettings provides a mock function with given fields: ctx
+func (_m *ClientIFace) GetAutoFailOverSettings(ctx context.Context) (*couchbase.AutoFailoverSettings, error) {
+	ret := _m.Called(ctx)
 
 	var r0 *couchbase.AutoFailoverSettings
-	if rf, ok := ret.Get(0).(func() *couchbase.AutoFailoverSettings); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) *couchbase.AutoFailoverSettings); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*couchbase.AutoFailoverSettings)
@@ -37,8 +60,8 @@ func (_m *ClientIFace) GetAutoFailOverSettings() (*couchbase.AutoFailoverSetting
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -60,13 +83,13 @@ func (_m *ClientIFace) GetBootstrap() time.Time {
 	return r0
 }
 
-// GetBucketStats provides a mock function with given fields: bucketName
-func (_m *ClientIFace) GetBucketStats(bucketName string) (*values.BucketStat, error) {
-	ret := _m.Called(bucketName)
+// GetBucketStats provides a mock function with given fields: ctx, bucketName
+func (_m *ClientIFace) GetBucketStats(ctx context.Context, bucketName string) (*values.BucketStat, error) {
+	ret := _m.Called(ctx, bucketName)
 
 	var r0 *values.BucketStat
-	if rf, ok := ret.Get(0).(func(string) *values.BucketStat); ok {
-		r0 = rf(bucketName)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *values.BucketStat); ok {
+		r0 = rf(ctx, bucketName)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*values.BucketStat)
@@ -74,8 +97,8 @@ func (_m *ClientIFace) GetBucketStats(bucketName string) (*values.BucketStat, er
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(bucketName)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, bucketName)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -83,13 +106,13 @@ func (_m *ClientIFace) GetBucketStats(bucketName string) (*values.BucketStat, er
 	return r0, r1
 }
 
-// GetBucketsSummary provides a mock function with given fields:
-func (_m *ClientIFace) GetBucketsSummary() (values.BucketsSummary, error) {
-	ret := _m.Called()
+// GetBucketsSummary provides a mock function with given fields: ctx
+func (_m *ClientIFace) GetBucketsSummary(ctx context.Context) (values.BucketsSummary, error) {
+	ret := _m.Called(ctx)
 
 	var r0 values.BucketsSummary
-	if rf, ok := ret.Get(0).(func() values.BucketsSummary); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) values.BucketsSummary); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(values.BucketsSummary)
@@ -97,8 +120,8 @@ func (_m *ClientIFace) GetBucketsSummary() (values.BucketsSummary, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -145,20 +168,20 @@ func (_m *ClientIFace) GetDiagLog(ctx context.Context) (io.ReadCloser, error) {
 	return r0, r1
 }
 
-// GetFTSIndexStatus provides a mock function with given fields:
-func (_m *ClientIFace) GetFTSIndexStatus() (values.FTSIndexStatus, error) {
-	ret := _m.Called()
+// GetFTSIndexStatus provides a mock function with given fields: ctx
+func (_m *ClientIFace) GetFTSIndexStatus(ctx context.Context) (values.FTSIndexStatus, error) {
+	ret := _m.Called(ctx)
 
 	var r0 values.FTSIndexStatus
-	if rf, ok := ret.Get(0).(func() values.FTSIndexStatus); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) values.FTSIndexStatus); ok {
+		r0 = rf(ctx)
 	} else {
 		r0 = ret.Get(0).(values.FTSIndexStatus)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -166,13 +189,36 @@ func (_m *ClientIFace) GetFTSIndexStatus() (values.FTSIndexStatus, error) {
 	return r0, r1
 }
 
-// GetIndexStatus provides a mock function with given fields:
-func (_m *ClientIFace) GetIndexStatus() ([]*values.IndexStatus, error) {
-	ret := _m.Called()
+// GetGSISettings provides a mock function with given fields: ctx
+func (_m *ClientIFace) GetGSISettings(ctx context.Context) (*values.GSISettings, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *values.GSISettings
+	if rf, ok := ret.Get(0).(func(context.Context) *values.GSISettings); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*values.GSISettings)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIndexStatus provides a mock function with given fields: ctx
+func (_m *ClientIFace) GetIndexStatus(ctx context.Context) ([]*values.IndexStatus, error) {
+	ret := _m.Called(ctx)
 
 	var r0 []*values.IndexStatus
-	if rf, ok := ret.Get(0).(func() []*values.IndexStatus); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []*values.IndexStatus); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*values.IndexStatus)
@@ -180,8 +226,8 @@ func (_m *ClientIFace) GetIndexStatus() ([]*values.IndexStatus, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -189,13 +235,13 @@ func (_m *ClientIFace) GetIndexStatus() ([]*values.IndexStatus, error) {
 	return r0, r1
 }
 
-// GetIndexStorageStats provides a mock function with given fields:
-func (_m *ClientIFace) GetIndexStorageStats() ([]*values.IndexStatsStorage, error) {
-	ret := _m.Called()
+// GetIndexStorageStats provides a mock function with given fields: ctx
+func (_m *ClientIFace) GetIndexStorageStats(ctx context.Context) ([]*values.IndexStatsStorage, error) {
+	ret := _m.Called(ctx)
 
 	var r0 []*values.IndexStatsStorage
-	if rf, ok := ret.Get(0).(func() []*values.IndexStatsStorage); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []*values.IndexStatsStorage); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*values.IndexStatsStorage)
@@ -203,8 +249,8 @@ func (_m *ClientIFace) GetIndexStorageStats() ([]*values.IndexStatsStorage, erro
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -212,13 +258,36 @@ func (_m *ClientIFace) GetIndexStorageStats() ([]*values.IndexStatsStorage, erro
 	return r0, r1
 }
 
-// GetMetric provides a mock function with given fields: start, end, metricName, step
-func (_m *ClientIFace) GetMetric(start string, end string, metricName string, step string) (*couchbase.Metric, error) {
-	ret := _m.Called(start, end, metricName, step)
+// GetMemoryQuotas provides a mock function with given fields: ctx
+func (_m *ClientIFace) GetMemoryQuotas(ctx context.Context) (*couchbase.MemoryQuotas, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *couchbase.MemoryQuotas
+	if rf, ok := ret.Get(0).(func(context.Context) *couchbase.MemoryQuotas); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*couchbase.MemoryQuotas)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMetric provides a mock function with given fields: ctx, start, end, metricName, step
+func (_m *ClientIFace) GetMetric(ctx context.Context, start string, end string, metricName string, step string) (*couchbase.Metric, error) {
+	ret := _m.Called(ctx, start, end, metricName, step)
 
 	var r0 *couchbase.Metric
-	if rf, ok := ret.Get(0).(func(string, string, string, string) *couchbase.Metric); ok {
-		r0 = rf(start, end, metricName, step)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) *couchbase.Metric); ok {
+		r0 = rf(ctx, start, end, metricName, step)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*couchbase.Metric)
@@ -226,8 +295,8 @@ func (_m *ClientIFace) GetMetric(start string, end string, metricName string, st
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string, string, string) error); ok {
-		r1 = rf(start, end, metricName, step)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) error); ok {
+		r1 = rf(ctx, start, end, metricName, step)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -235,13 +304,13 @@ func (_m *ClientIFace) GetMetric(start string, end string, metricName string, st
 	return r0, r1
 }
 
-// GetNodeStorage provides a mock function with given fields:
-func (_m *ClientIFace) GetNodeStorage() (*values.Storage, error) {
-	ret := _m.Called()
+// GetNodeStorage provides a mock function with given fields: ctx
+func (_m *ClientIFace) GetNodeStorage(ctx context.Context) (*values.Storage, error) {
+	ret := _m.Called(ctx)
 
 	var r0 *values.Storage
-	if rf, ok := ret.Get(0).(func() *values.Storage); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) *values.Storage); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*values.Storage)
@@ -249,8 +318,8 @@ func (_m *ClientIFace) GetNodeStorage() (*values.Storage, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -258,13 +327,13 @@ func (_m *ClientIFace) GetNodeStorage() (*values.Storage, error) {
 	return r0, r1
 }
 
-// GetNodesSummary provides a mock function with given fields:
-func (_m *ClientIFace) GetNodesSummary() (values.NodesSummary, error) {
-	ret := _m.Called()
+// GetNodesSummary provides a mock function with given fields: ctx
+func (_m *ClientIFace) GetNodesSummary(ctx context.Context) (values.NodesSummary, error) {
+	ret := _m.Called(ctx)
 
 	var r0 values.NodesSummary
-	if rf, ok := ret.Get(0).(func() values.NodesSummary); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) values.NodesSummary); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(values.NodesSummary)
@@ -272,8 +341,8 @@ func (_m *ClientIFace) GetNodesSummary() (values.NodesSummary, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -281,13 +350,13 @@ func (_m *ClientIFace) GetNodesSummary() (values.NodesSummary, error) {
 	return r0, r1
 }
 
-// GetPoolsBucket provides a mock function with given fields:
-func (_m *ClientIFace) GetPoolsBucket() ([]couchbase.Bucket, error) {
-	ret := _m.Called()
+// GetPoolsBucket provides a mock function with given fields: ctx
+func (_m *ClientIFace) GetPoolsBucket(ctx context.Context) ([]couchbase.Bucket, error) {
+	ret := _m.Called(ctx)
 
 	var r0 []couchbase.Bucket
-	if rf, ok := ret.Get(0).(func() []couchbase.Bucket); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []couchbase.Bucket); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]couchbase.Bucket)
@@ -295,8 +364,8 @@ func (_m *ClientIFace) GetPoolsBucket() ([]couchbase.Bucket, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -327,13 +396,13 @@ func (_m *ClientIFace) GetSASLLogs(ctx context.Context, logName string) (io.Read
 	return r0, r1
 }
 
-// GetServerGroups provides a mock function with given fields:
-func (_m *ClientIFace) GetServerGroups() ([]values.ServerGroup, error) {
-	ret := _m.Called()
+// GetServerGroups provides a mock function with given fields: ctx
+func (_m *ClientIFace) GetServerGroups(ctx context.Context) ([]values.ServerGroup, error) {
+	ret := _m.Called(ctx)
 
 	var r0 []values.ServerGroup
-	if rf, ok := ret.Get(0).(func() []values.ServerGroup); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []values.ServerGroup); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]values.ServerGroup)
@@ -341,8 +410,8 @@ func (_m *ClientIFace) GetServerGroups() ([]values.ServerGroup, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -350,13 +419,13 @@ func (_m *ClientIFace) GetServerGroups() ([]values.ServerGroup, error) {
 	return r0, r1
 }
 
-// GetUILogs provides a mock function with given fields:
-func (_m *ClientIFace) GetUILogs() ([]couchbase.UILogEntry, error) {
-	ret := _m.Called()
+// GetUILogs provides a mock function with given fields: ctx
+func (_m *ClientIFace) GetUILogs(ctx context.Context) ([]couchbase.UILogEntry, error) {
+	ret := _m.Called(ctx)
 
 	var r0 []couchbase.UILogEntry
-	if rf, ok := ret.Get(0).(func() []couchbase.UILogEntry); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []couchbase.UILogEntry); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]couchbase.UILogEntry)
@@ -364,8 +433,8 @@ func (_m *ClientIFace) GetUILogs() ([]couchbase.UILogEntry, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -373,13 +442,13 @@ func (_m *ClientIFace) GetUILogs() ([]couchbase.UILogEntry, error) {
 	return r0, r1
 }
 
-// PingService provides a mock function with given fields: service
-func (_m *ClientIFace) PingService(service cbrest.Service) error {
-	ret := _m.Called(service)
+// PingService provides a mock function with given fields: ctx, service
+func (_m *ClientIFace) PingService(ctx context.Context, service cbrest.Service) error {
+	ret := _m.Called(ctx, service)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(cbrest.Service) error); ok {
-		r0 = rf(service)
+	if rf, ok := ret.Get(0).(func(context.Context, cbrest.Service) error); ok {
+		r0 = rf(ctx, service)
 	} else {
 		r0 = ret.Error(0)
 	}
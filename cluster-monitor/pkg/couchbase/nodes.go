@@ -6,12 +6,14 @@
 package couchbase
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
 
@@ -21,8 +23,8 @@ import (
 )
 
 // GetNodesSummary returns a slice with the addresses of all the nodes in the cluster
-func (c *Client) GetNodesSummary() (values.NodesSummary, error) {
-	res, err := c.get(PoolsNodesEndpoint)
+func (c *Client) GetNodesSummary(ctx context.Context) (values.NodesSummary, error) {
+	res, err := c.get(ctx, PoolsNodesEndpoint)
 	if err != nil {
 		return nil, fmt.Errorf("could not get node summary: %w", err)
 	}
@@ -60,10 +62,11 @@ func (c *Client) GetNodesSummary() (values.NodesSummary, error) {
 	}
 
 	useAlt := c.internalClient.AltAddr()
+	kvHosts := c.getKVHosts()
 	summary := make([]values.NodeSummary, 0, len(nodesData.Nodes))
 
 	getAltPort := func(node node) (uint16, error) {
-		if c.ClusterInfo.Enterprise {
+		if c.GetClusterInfo().Enterprise {
 			if node.AlternateAddresses.External.Services.ManagementSSL != 0 {
 				return node.AlternateAddresses.External.Services.ManagementSSL, nil
 			}
@@ -99,7 +102,7 @@ func (c *Client) GetNodesSummary() (values.NodesSummary, error) {
 	getNodeHostName := func(useAlt bool, node node) (string, error) {
 		if useAlt {
 			scheme := "http://"
-			if c.ClusterInfo.Enterprise {
+			if c.GetClusterInfo().Enterprise {
 				scheme = "https://"
 			}
 
@@ -116,7 +119,7 @@ func (c *Client) GetNodesSummary() (values.NodesSummary, error) {
 				port), nil
 		}
 
-		if !c.ClusterInfo.Enterprise {
+		if !c.GetClusterInfo().Enterprise {
 			return fmt.Sprintf("http://%s", node.Hostname), nil
 		}
 
@@ -159,6 +162,10 @@ func (c *Client) GetNodesSummary() (values.NodesSummary, error) {
 			return nil, err
 		}
 
+		if host, port, err := net.SplitHostPort(node.Hostname); err == nil {
+			nodeSummary.KVHost = kvHosts[net.JoinHostPort(host, port)]
+		}
+
 		// Some versions do not expose the node uuid in that case we will use the hostname as the node uuid. Note this
 		// value can change in some specific cases (when changing a 1 node cluster two a 2 node cluster) but it is the
 		// best we can do.
@@ -172,12 +179,35 @@ func (c *Client) GetNodesSummary() (values.NodesSummary, error) {
 	return summary, nil
 }
 
+// getKVHosts returns the host-port memcached listens on for each Data Service node, in the same form as the hosts
+// returned by GetAllServiceHosts. They are keyed by the host-port of the node's management port, which is how the node
+// is identified in /pools/default, so nodes sharing an address can be told apart.
+func (c *Client) getKVHosts() map[string]string {
+	useSSL, useAlt := c.internalClient.TLS(), c.internalClient.AltAddr()
+	hosts := make(map[string]string)
+	for _, node := range c.internalClient.Nodes() {
+		if node.Services == nil || node.Services.Management == 0 {
+			continue
+		}
+
+		kvHost, _ := node.GetQualifiedHostname(cbrest.ServiceData, useSSL, useAlt)
+		if kvHost == "" {
+			continue
+		}
+
+		nodeHost := strings.Trim(node.Hostname, "[]")
+		hosts[net.JoinHostPort(nodeHost, strconv.Itoa(int(node.Services.Management)))] = netutil.TrimSchema(kvHost)
+	}
+
+	return hosts
+}
+
 func (c *Client) GetAllServiceHosts(service cbrest.Service) ([]string, error) {
 	return c.internalClient.GetAllServiceHosts(service)
 }
 
-func (c *Client) GetNodeStorage() (*values.Storage, error) {
-	res, err := c.get(NodesSelfEndpoint)
+func (c *Client) GetNodeStorage(ctx context.Context) (*values.Storage, error) {
+	res, err := c.get(ctx, NodesSelfEndpoint)
 	if err != nil {
 		return nil, fmt.Errorf("could not issue get request: %w", err)
 	}
@@ -193,7 +223,7 @@ func (c *Client) GetNodeStorage() (*values.Storage, error) {
 }
 
 // PingService verifies that this cbmultimanager can communicate with the given service.
-func (c *Client) PingService(service cbrest.Service) error {
+func (c *Client) PingService(ctx context.Context, service cbrest.Service) error {
 	var endpoint cbrest.Endpoint
 	switch service {
 	case cbrest.ServiceQuery, cbrest.ServiceAnalytics:
@@ -207,7 +237,7 @@ func (c *Client) PingService(service cbrest.Service) error {
 	default:
 		return fmt.Errorf("cannot ping %s with PingService", service)
 	}
-	_, err := c.internalClient.Execute(&cbrest.Request{
+	_, err := c.execute(ctx, &cbrest.Request{
 		Method:             http.MethodGet,
 		Endpoint:           endpoint,
 		Service:            service,
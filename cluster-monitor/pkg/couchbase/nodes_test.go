@@ -6,6 +6,7 @@
 package couchbase
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net"
@@ -41,13 +42,13 @@ func TestClientGetNodeStorage(t *testing.T) {
 
 	t.Run("404", func(t *testing.T) {
 		statusCode = 404
-		_, err := client.GetNodeStorage()
+		_, err := client.GetNodeStorage(context.Background())
 		require.ErrorIs(t, err, values.ErrNotFound)
 	})
 
 	t.Run("200", func(t *testing.T) {
 		statusCode = 200
-		res, _ := client.GetNodeStorage()
+		res, _ := client.GetNodeStorage(context.Background())
 
 		expected := &values.Storage{
 			Available: values.AvailableStorage{
@@ -290,7 +291,7 @@ func TestClientGetNodesSummary(t *testing.T) {
 
 			client := getTestClient(t, handler.URL())
 
-			nodesOut, err := client.GetNodesSummary()
+			nodesOut, err := client.GetNodesSummary(context.Background())
 
 			if tc.returnCode == http.StatusOK && err != nil {
 				require.NoError(t, err)
@@ -304,3 +305,24 @@ func TestClientGetNodesSummary(t *testing.T) {
 		})
 	}
 }
+
+func TestClientGetNodesSummaryKVHost(t *testing.T) {
+	// the test cluster only knows the services by their cbrest names
+	handler := &TestHandler{
+		ClusterUUID:      "cluster_x",
+		Nodes:            []TestNode{{NodeUUID: "a", Services: []string{string(cbrest.ServiceData)}}},
+		NodesReturnCode:  http.StatusOK,
+		BucketReturnCode: http.StatusOK,
+	}
+	handler.Start(t, false, true)
+	defer handler.Close()
+
+	// the test cluster serves every node on its own address, with memcached on the same port
+	noSchemaHost := netutil.TrimSchema(handler.URL())
+	handler.Nodes[0].Hostname = noSchemaHost
+
+	nodes, err := getTestClient(t, handler.URL()).GetNodesSummary(context.Background())
+	require.NoError(t, err)
+	require.Len(t, nodes, 1)
+	require.Equal(t, noSchemaHost, nodes[0].KVHost)
+}
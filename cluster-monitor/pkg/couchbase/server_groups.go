@@ -6,6 +6,7 @@
 package couchbase
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -16,8 +17,8 @@ type serverGroupResult struct {
 	Groups []values.ServerGroup `json:"groups"`
 }
 
-func (c *Client) GetServerGroups() ([]values.ServerGroup, error) {
-	res, err := c.get(PoolsServerGroup)
+func (c *Client) GetServerGroups(ctx context.Context) ([]values.ServerGroup, error) {
+	res, err := c.get(ctx, PoolsServerGroup)
 	if err != nil {
 		return nil, fmt.Errorf("could not get pools server group data: %w", err)
 	}
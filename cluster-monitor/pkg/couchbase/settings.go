@@ -6,12 +6,15 @@
 package couchbase
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+
+	"github.com/couchbase/tools-common/cbrest"
 )
 
-func (c *Client) GetAutoFailOverSettings() (*AutoFailoverSettings, error) {
-	res, err := c.get(AutoFailOverSettings)
+func (c *Client) GetAutoFailOverSettings(ctx context.Context) (*AutoFailoverSettings, error) {
+	res, err := c.get(ctx, AutoFailOverSettings)
 	if err != nil {
 		return nil, fmt.Errorf("could not get auto failover settings: %w", err)
 	}
@@ -23,3 +26,38 @@ func (c *Client) GetAutoFailOverSettings() (*AutoFailoverSettings, error) {
 
 	return &settings, nil
 }
+
+func (c *Client) GetAutoCompactionSettings(ctx context.Context) (*AutoCompactionSettings, error) {
+	res, err := c.get(ctx, AutoCompactionSettingsEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("could not get auto compaction settings: %w", err)
+	}
+
+	var overlay struct {
+		Settings      AutoCompactionSettings `json:"autoCompactionSettings"`
+		PurgeInterval float64                `json:"purgeInterval"`
+	}
+
+	if err = json.Unmarshal(res.Body, &overlay); err != nil {
+		return nil, fmt.Errorf("could not unmarshall the auto compaction settings: %w", err)
+	}
+
+	overlay.Settings.PurgeInterval = overlay.PurgeInterval
+	return &overlay.Settings, nil
+}
+
+// GetMemoryQuotas gets the current memory quotas of the services. They are requested every time rather than taken
+// from the pools data kept at bootstrap, as they can be changed while the client is in use.
+func (c *Client) GetMemoryQuotas(ctx context.Context) (*MemoryQuotas, error) {
+	res, err := c.get(ctx, cbrest.EndpointPoolsDefault)
+	if err != nil {
+		return nil, fmt.Errorf("could not get memory quotas: %w", err)
+	}
+
+	var quotas MemoryQuotas
+	if err = json.Unmarshal(res.Body, &quotas); err != nil {
+		return nil, fmt.Errorf("could not unmarshall the memory quotas: %w", err)
+	}
+
+	return &quotas, nil
+}
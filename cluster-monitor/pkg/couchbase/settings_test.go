@@ -6,6 +6,7 @@
 package couchbase
 
 import (
+	"context"
 	"net/http"
 	"testing"
 
@@ -50,7 +51,13 @@ func TestGetAutoFailOverSettings(t *testing.T) {
 		{
 			name:       "enabled",
 			returnCode: http.StatusOK,
-			settings:   AutoFailoverSettings{Enabled: true},
+			settings: AutoFailoverSettings{
+				Enabled:                  true,
+				Timeout:                  120,
+				MaxCount:                 1,
+				FailoverServerGroup:      true,
+				FailoverOnDataDiskIssues: DiskFailoverSettings{Enabled: true, TimePeriod: 120},
+			},
 		},
 		{
 			name:       "disable",
@@ -64,7 +71,7 @@ func TestGetAutoFailOverSettings(t *testing.T) {
 			statusCode = tc.returnCode
 			settings = tc.settings
 
-			settingsOut, err := client.GetAutoFailOverSettings()
+			settingsOut, err := client.GetAutoFailOverSettings(context.Background())
 			if tc.returnCode == http.StatusOK {
 				require.NoError(t, err)
 			} else {
@@ -79,3 +86,74 @@ func TestGetAutoFailOverSettings(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAutoCompactionSettings(t *testing.T) {
+	var body string
+
+	handlers := make(cbrest.TestHandlers)
+	handlers.Add(http.MethodGet, string(AutoCompactionSettingsEndpoint), func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	})
+
+	cluster := cbrest.NewTestCluster(t, cbrest.TestClusterOptions{
+		Enterprise: true,
+		UUID:       "cluster_0",
+		Nodes:      cbrest.TestNodes{{}},
+		Handlers:   handlers,
+	})
+	defer cluster.Close()
+
+	client := getTestClient(t, cluster.URL())
+
+	thirty := uint64(30)
+
+	t.Run("set", func(t *testing.T) {
+		body = `{"autoCompactionSettings":{"parallelDBAndViewCompaction":false,` +
+			`"databaseFragmentationThreshold":{"percentage":30,"size":"undefined"},` +
+			`"viewFragmentationThreshold":{"percentage":30,"size":"undefined"},"indexCompactionMode":"circular",` +
+			`"indexFragmentationThreshold":{"percentage":30}},"purgeInterval":3}`
+
+		settings, err := client.GetAutoCompactionSettings(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, &AutoCompactionSettings{
+			DatabaseFragmentationThreshold: FragmentationThreshold{Percentage: &thirty},
+			ViewFragmentationThreshold:     FragmentationThreshold{Percentage: &thirty},
+			IndexCompactionMode:            "circular",
+			IndexFragmentationThreshold:    FragmentationThreshold{Percentage: &thirty},
+			PurgeInterval:                  3,
+		}, settings)
+		require.True(t, settings.DatabaseFragmentationThreshold.IsSet())
+	})
+
+	t.Run("undefined", func(t *testing.T) {
+		body = `{"autoCompactionSettings":{"databaseFragmentationThreshold":{"percentage":"undefined",` +
+			`"size":"undefined"}},"purgeInterval":0.04}`
+
+		settings, err := client.GetAutoCompactionSettings(context.Background())
+		require.NoError(t, err)
+		require.False(t, settings.DatabaseFragmentationThreshold.IsSet())
+		require.Equal(t, 0.04, settings.PurgeInterval)
+	})
+}
+
+func TestGetMemoryQuotas(t *testing.T) {
+	handlers := make(cbrest.TestHandlers)
+	handlers.Add(http.MethodGet, string(cbrest.EndpointPoolsDefault), func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"nodes":[{"version":"7.0.3-7031-enterprise"}],"memoryQuota":2048,` +
+			`"indexMemoryQuota":512,"ftsMemoryQuota":256,"cbasMemoryQuota":1024,"eventingMemoryQuota":256}`))
+	})
+
+	cluster := cbrest.NewTestCluster(t, cbrest.TestClusterOptions{
+		Enterprise: true,
+		UUID:       "cluster_0",
+		Nodes:      cbrest.TestNodes{{}},
+		Handlers:   handlers,
+	})
+	defer cluster.Close()
+
+	quotas, err := getTestClient(t, cluster.URL()).GetMemoryQuotas(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, &MemoryQuotas{KV: 2048, Index: 512, FTS: 256, CBAS: 1024, Eventing: 256}, quotas)
+	require.Equal(t, uint64(256), quotas.ServiceQuota("eventing"))
+	require.Zero(t, quotas.ServiceQuota("n1ql"))
+}
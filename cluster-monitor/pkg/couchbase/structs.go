@@ -7,6 +7,7 @@ package couchbase
 
 import (
 	"crypto/tls"
+	"encoding/json"
 	"time"
 
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
@@ -61,6 +62,96 @@ type Services struct {
 
 type AutoFailoverSettings struct {
 	Enabled bool `json:"enabled"`
+	// Timeout is how long, in seconds, a node has to be unresponsive before it is failed over.
+	Timeout uint64 `json:"timeout"`
+	// Count is how many nodes have been automatically failed over since the count was last reset.
+	Count               int  `json:"count"`
+	MaxCount            int  `json:"maxCount"`
+	FailoverServerGroup bool `json:"failoverServerGroup"`
+
+	FailoverOnDataDiskIssues DiskFailoverSettings `json:"failoverOnDataDiskIssues"`
+}
+
+type DiskFailoverSettings struct {
+	Enabled bool `json:"enabled"`
+	// TimePeriod is how long, in seconds, the data disk has to be failing before the node is failed over.
+	TimePeriod uint64 `json:"timePeriod"`
+}
+
+// AutoCompactionSettings are the global auto-compaction settings, which apply to the buckets that do not override them.
+type AutoCompactionSettings struct {
+	ParallelDBAndViewCompaction    bool                   `json:"parallelDBAndViewCompaction"`
+	DatabaseFragmentationThreshold FragmentationThreshold `json:"databaseFragmentationThreshold"`
+	ViewFragmentationThreshold     FragmentationThreshold `json:"viewFragmentationThreshold"`
+	IndexCompactionMode            string                 `json:"indexCompactionMode,omitempty"`
+	IndexFragmentationThreshold    FragmentationThreshold `json:"indexFragmentationThreshold"`
+	// PurgeInterval is how often, in days, the tombstones are purged.
+	PurgeInterval float64 `json:"purgeInterval"`
+}
+
+// FragmentationThreshold is the fragmentation at which compaction is triggered. Either of them is nil if it is not set,
+// which the cluster manager reports as "undefined".
+type FragmentationThreshold struct {
+	Percentage *uint64 `json:"percentage,omitempty"`
+	Size       *uint64 `json:"size,omitempty"`
+}
+
+// IsSet returns whether either of the thresholds is set.
+func (t FragmentationThreshold) IsSet() bool {
+	return t.Percentage != nil || t.Size != nil
+}
+
+func (t *FragmentationThreshold) UnmarshalJSON(data []byte) error {
+	var overlay struct {
+		Percentage json.RawMessage `json:"percentage"`
+		Size       json.RawMessage `json:"size"`
+	}
+
+	if err := json.Unmarshal(data, &overlay); err != nil {
+		return err
+	}
+
+	t.Percentage = optionalUint64(overlay.Percentage)
+	t.Size = optionalUint64(overlay.Size)
+
+	return nil
+}
+
+// optionalUint64 returns the number in the raw JSON, or nil if it is not a number such as when it is "undefined".
+func optionalUint64(raw json.RawMessage) *uint64 {
+	var value uint64
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil
+	}
+
+	return &value
+}
+
+// MemoryQuotas are the memory quotas, in MiB, of the services on each node.
+type MemoryQuotas struct {
+	KV       uint64 `json:"memoryQuota"`
+	Index    uint64 `json:"indexMemoryQuota"`
+	FTS      uint64 `json:"ftsMemoryQuota"`
+	CBAS     uint64 `json:"cbasMemoryQuota"`
+	Eventing uint64 `json:"eventingMemoryQuota"`
+}
+
+// ServiceQuota returns the memory quota of the service, services without a quota have 0.
+func (q MemoryQuotas) ServiceQuota(service string) uint64 {
+	switch service {
+	case "kv":
+		return q.KV
+	case "index":
+		return q.Index
+	case "fts":
+		return q.FTS
+	case "cbas":
+		return q.CBAS
+	case "eventing":
+		return q.Eventing
+	}
+
+	return 0
 }
 
 type VBucketServerMap struct {
@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
 
@@ -98,6 +99,7 @@ type TestHandler struct {
 	NodeStorage            values.NodeStorage
 	Buckets                []BucketsEndpointData
 	BucketReturnCode       int
+	NodesDelay             time.Duration
 
 	Cluster *cbrest.TestCluster
 }
@@ -115,6 +117,12 @@ func (h *TestHandler) Start(t *testing.T, https, enterprise bool) {
 	}
 
 	handlers.Add(http.MethodGet, string(PoolsNodesEndpoint), func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(h.NodesDelay):
+		case <-r.Context().Done():
+			return
+		}
+
 		wrapped := struct {
 			Nodes []TestNode `json:"nodes"`
 		}{Nodes: h.Nodes}
@@ -18,11 +18,15 @@ type ClusterDiscoveryManager struct {
 	ctx       context.Context
 	cancel    context.CancelFunc
 	wg        sync.WaitGroup
+
+	// intervals receives the new interval when the manager is reconfigured.
+	intervals chan time.Duration
 }
 
 func NewDiscoveryManager(disco CouchbaseClusterDiscovery) (*ClusterDiscoveryManager, error) {
 	dm := ClusterDiscoveryManager{
 		discovery: disco,
+		intervals: make(chan time.Duration, 1),
 	}
 	return &dm, nil
 }
@@ -34,6 +38,24 @@ func (d *ClusterDiscoveryManager) Start(interval time.Duration) {
 	go d.discoverLoop(interval)
 }
 
+// Reconfigure changes the interval between discoveries. A discovery in progress is not interrupted.
+func (d *ClusterDiscoveryManager) Reconfigure(interval time.Duration) {
+	zap.S().Infow("(Discovery Manager) Reconfiguring discovery", "interval", interval)
+	for {
+		select {
+		case d.intervals <- interval:
+			return
+		default:
+		}
+
+		// replace the interval that has not been picked up yet
+		select {
+		case <-d.intervals:
+		default:
+		}
+	}
+}
+
 func (d *ClusterDiscoveryManager) Stop() {
 	if d.ctx == nil {
 		return
@@ -63,6 +85,8 @@ func (d *ClusterDiscoveryManager) discoverLoop(interval time.Duration) {
 			} else {
 				zap.S().Infof("(Discovery Manager) Discovery complete, next run will be in %v.", interval)
 			}
+		case interval = <-d.intervals:
+			ticker.Reset(interval)
 		case <-d.ctx.Done():
 			return
 		}
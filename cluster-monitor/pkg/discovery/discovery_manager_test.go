@@ -42,6 +42,21 @@ func TestDiscoveryLoopError(t *testing.T) {
 	mockDisco.AssertNumberOfCalls(t, "Discover", 2)
 }
 
+func TestDiscoveryReconfigure(t *testing.T) {
+	t.Parallel()
+	mockDisco := mocks.CouchbaseClusterDiscovery{}
+	mockDisco.On("Discover", mock.Anything).Return(nil)
+	dm, err := NewDiscoveryManager(&mockDisco)
+	require.NoError(t, err)
+	dm.Start(time.Hour)
+	time.Sleep(100 * time.Millisecond)
+	mockDisco.AssertNumberOfCalls(t, "Discover", 1)
+	dm.Reconfigure(time.Second)
+	time.Sleep(1500 * time.Millisecond)
+	dm.Stop()
+	mockDisco.AssertNumberOfCalls(t, "Discover", 2)
+}
+
 func TestDiscoveryStopBeforeStart(t *testing.T) {
 	mockDisco := mocks.CouchbaseClusterDiscovery{}
 	mockDisco.On("Discover", mock.Anything).Return(nil)
@@ -21,4 +21,5 @@ type CouchbaseClusterDiscovery interface {
 type Manager interface {
 	Start(interval time.Duration)
 	Stop()
+	Reconfigure(interval time.Duration)
 }
@@ -13,6 +13,11 @@ type Manager struct {
 	mock.Mock
 }
 
+// Reconfigure provides a mock function with given fields: interval
+func (_m *Manager) Reconfigure(interval time.Duration) {
+	_m.Called(interval)
+}
+
 // Start provides a mock function with given fields: interval
 func (_m *Manager) Start(interval time.Duration) {
 	_m.Called(interval)
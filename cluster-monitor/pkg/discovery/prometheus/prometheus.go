@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"net"
 	"strconv"
+	"time"
 
 	promapi "github.com/prometheus/client_golang/api"
 	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
@@ -20,6 +21,7 @@ import (
 
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/configuration"
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/couchbase"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/events"
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/storage"
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
 )
@@ -47,10 +49,16 @@ type CouchbaseClusterDiscovery struct {
 	cfg   *configuration.Config
 	store storage.Store
 	prom  promAPI
+
+	// events is where the discovered and removed clusters are published.
+	events *events.Bus
+	// clients are the cluster REST clients shared with the rest of the manager, the ones of removed clusters are
+	// closed.
+	clients *couchbase.ClientCache
 }
 
-func NewPrometheusCouchbaseClusterDiscovery(cfg *configuration.Config, store storage.Store) (
-	*CouchbaseClusterDiscovery, error) {
+func NewPrometheusCouchbaseClusterDiscovery(cfg *configuration.Config, store storage.Store, bus *events.Bus,
+	clients *couchbase.ClientCache) (*CouchbaseClusterDiscovery, error) {
 	client, err := promapi.NewClient(promapi.Config{
 		Address: cfg.PrometheusBaseURL,
 	})
@@ -58,13 +66,15 @@ func NewPrometheusCouchbaseClusterDiscovery(cfg *configuration.Config, store sto
 		return nil, err
 	}
 	return &CouchbaseClusterDiscovery{
-		cfg:   cfg,
-		store: store,
-		prom:  promv1.NewAPI(client),
+		cfg:     cfg,
+		store:   store,
+		prom:    promv1.NewAPI(client),
+		events:  bus,
+		clients: clients,
 	}, nil
 }
 
-func (p *CouchbaseClusterDiscovery) findManagementAddressForCluster(
+func (p *CouchbaseClusterDiscovery) findManagementAddressForCluster(ctx context.Context,
 	knownAddress string) (*couchbase.Client, string, error) {
 	// TODO (CMOS-101): this is a fairly expensive operation, involving three failing REST calls in the worst case,
 	// plus however many retries cbrest performs.
@@ -84,14 +94,14 @@ func (p *CouchbaseClusterDiscovery) findManagementAddressForCluster(
 	} else {
 		protocol = ProtocolInsecure
 	}
-	cb, err := couchbase.NewClient([]string{fmt.Sprintf("%s://%s", protocol, knownAddress)}, p.cfg.CouchbaseUser,
+	cb, err := couchbase.NewClient(ctx, []string{fmt.Sprintf("%s://%s", protocol, knownAddress)}, p.cfg.CouchbaseUser,
 		p.cfg.CouchbasePassword, nil, true)
 	if err == nil {
 		return cb, knownAddress, nil
 	}
 
 	// Try replacing the port with the secure management port
-	cb, err = couchbase.NewClient([]string{
+	cb, err = couchbase.NewClient(ctx, []string{
 		fmt.Sprintf("%s://%s:%d", ProtocolSecure, host,
 			DefaultCouchbaseManagementSecurePort),
 	}, p.cfg.CouchbaseUser, p.cfg.CouchbasePassword, nil, true)
@@ -100,7 +110,7 @@ func (p *CouchbaseClusterDiscovery) findManagementAddressForCluster(
 	}
 
 	// Ditto for the insecure port
-	cb, err = couchbase.NewClient([]string{
+	cb, err = couchbase.NewClient(ctx, []string{
 		fmt.Sprintf("%s://%s:%d", ProtocolInsecure, host,
 			DefaultCouchbaseManagementInsecurePort),
 	}, p.cfg.CouchbaseUser, p.cfg.CouchbasePassword, nil, true)
@@ -137,7 +147,7 @@ func (p *CouchbaseClusterDiscovery) Discover(ctx context.Context) error {
 		if !p.matchesLabelSelector(tgt) {
 			continue
 		}
-		cb, address, err := p.findManagementAddressForCluster(tgt.DiscoveredLabels[AddressLabel])
+		cb, address, err := p.findManagementAddressForCluster(ctx, tgt.DiscoveredLabels[AddressLabel])
 		if err != nil {
 			zap.S().Warnw("(Prometheus Discovery) Failed to connect to target", "target", tgt, "err", err)
 			continue
@@ -156,7 +166,7 @@ func (p *CouchbaseClusterDiscovery) Discover(ctx context.Context) error {
 			seenClusters[uuid] = true
 			continue
 		} else if errors.Is(err, values.ErrNotFound) {
-			buckets, err := cb.GetBucketsSummary()
+			buckets, err := cb.GetBucketsSummary(ctx)
 			if err != nil {
 				zap.S().Warnw("(Prometheus Discovery) Connected to cluster, but could not get buckets summary", "err", err)
 				continue
@@ -179,6 +189,13 @@ func (p *CouchbaseClusterDiscovery) Discover(ctx context.Context) error {
 			if err = p.store.AddCluster(&clusterInfo); err != nil {
 				return fmt.Errorf("failed to store new cluster: %w", err)
 			}
+
+			p.events.Publish(values.Event{
+				Type:        values.ClusterAddedEvent,
+				ClusterUUID: uuid,
+				ClusterName: cluster.ClusterName,
+				Time:        time.Now().UTC(),
+			})
 			seenClusters[uuid] = true
 		} else {
 			return fmt.Errorf("failed to check existing cluster in store for UUID %v: %w", uuid, err)
@@ -198,6 +215,15 @@ func (p *CouchbaseClusterDiscovery) Discover(ctx context.Context) error {
 		if err = p.store.DeleteCluster(cluster.UUID); err != nil {
 			return fmt.Errorf("failed to delete cluster %s: %w", cluster.UUID, err)
 		}
+
+		p.clients.Invalidate(cluster.UUID)
+
+		p.events.Publish(values.Event{
+			Type:        values.ClusterRemovedEvent,
+			ClusterUUID: cluster.UUID,
+			ClusterName: cluster.Name,
+			Time:        time.Now().UTC(),
+		})
 	}
 	return nil
 }
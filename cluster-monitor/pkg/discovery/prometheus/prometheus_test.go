@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/couchbase/tools-common/netutil"
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
@@ -22,6 +23,7 @@ import (
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/configuration"
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/couchbase"
 	promMocks "github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/discovery/prometheus/mocks"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/events"
 	storeMocks "github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/storage/mocks"
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
 )
@@ -100,7 +102,7 @@ func testServer(t *testing.T, clusterUUID string, is7 bool) (handler *couchbase.
 
 func TestDiscoverNoTargets(t *testing.T) {
 	store := storeMocks.Store{}
-	disco, err := NewPrometheusCouchbaseClusterDiscovery(&testConfig, &store)
+	disco, err := NewPrometheusCouchbaseClusterDiscovery(&testConfig, &store, nil, couchbase.NewClientCache(time.Minute))
 	require.NoError(t, err)
 	mockProm := promMocks.PromAPI{}
 	disco.prom = &mockProm
@@ -119,7 +121,7 @@ func TestDiscoverNoTargets(t *testing.T) {
 
 func TestDiscoverLabelMismatch(t *testing.T) {
 	store := storeMocks.Store{}
-	disco, err := NewPrometheusCouchbaseClusterDiscovery(&testConfig, &store)
+	disco, err := NewPrometheusCouchbaseClusterDiscovery(&testConfig, &store, nil, couchbase.NewClientCache(time.Minute))
 	require.NoError(t, err)
 	mockProm := promMocks.PromAPI{}
 	disco.prom = &mockProm
@@ -155,7 +157,11 @@ func TestDiscoverLabelMismatch(t *testing.T) {
 
 func TestDiscoverOneTarget(t *testing.T) {
 	store := storeMocks.Store{}
-	disco, err := NewPrometheusCouchbaseClusterDiscovery(&testConfig, &store)
+	bus := events.NewBus()
+	sub := bus.Subscribe()
+	defer sub.Close()
+
+	disco, err := NewPrometheusCouchbaseClusterDiscovery(&testConfig, &store, bus, couchbase.NewClientCache(time.Minute))
 	require.NoError(t, err)
 	mockProm := promMocks.PromAPI{}
 	disco.prom = &mockProm
@@ -189,11 +195,16 @@ func TestDiscoverOneTarget(t *testing.T) {
 
 	store.AssertNumberOfCalls(t, "GetCluster", 1)
 	store.AssertNumberOfCalls(t, "AddCluster", 1)
+
+	require.Len(t, sub.Events, 1)
+	event := <-sub.Events
+	require.Equal(t, values.ClusterAddedEvent, event.Type)
+	require.Equal(t, "TDOT-0", event.ClusterUUID)
 }
 
 func TestDiscoverExistingTarget(t *testing.T) {
 	store := storeMocks.Store{}
-	disco, err := NewPrometheusCouchbaseClusterDiscovery(&testConfig, &store)
+	disco, err := NewPrometheusCouchbaseClusterDiscovery(&testConfig, &store, nil, couchbase.NewClientCache(time.Minute))
 	require.NoError(t, err)
 	mockProm := promMocks.PromAPI{}
 	disco.prom = &mockProm
@@ -235,7 +246,7 @@ func TestDiscoverExistingTarget(t *testing.T) {
 
 func TestDiscoverMultipleTargetsSameCluster(t *testing.T) {
 	store := storeMocks.Store{}
-	disco, err := NewPrometheusCouchbaseClusterDiscovery(&testConfig, &store)
+	disco, err := NewPrometheusCouchbaseClusterDiscovery(&testConfig, &store, nil, couchbase.NewClientCache(time.Minute))
 	require.NoError(t, err)
 	mockProm := promMocks.PromAPI{}
 	disco.prom = &mockProm
@@ -285,7 +296,11 @@ func TestDiscoverMultipleTargetsSameCluster(t *testing.T) {
 
 func TestDiscoverGone(t *testing.T) {
 	store := storeMocks.Store{}
-	disco, err := NewPrometheusCouchbaseClusterDiscovery(&testConfig, &store)
+	bus := events.NewBus()
+	sub := bus.Subscribe("TDG-0")
+	defer sub.Close()
+
+	disco, err := NewPrometheusCouchbaseClusterDiscovery(&testConfig, &store, bus, couchbase.NewClientCache(time.Minute))
 	require.NoError(t, err)
 	mockProm := promMocks.PromAPI{}
 	disco.prom = &mockProm
@@ -310,6 +325,9 @@ func TestDiscoverGone(t *testing.T) {
 	store.AssertNumberOfCalls(t, "AddCluster", 0)
 	store.AssertNumberOfCalls(t, "GetClusters", 1)
 	store.AssertCalled(t, "DeleteCluster", "TDG-0")
+
+	require.Len(t, sub.Events, 1)
+	require.Equal(t, values.ClusterRemovedEvent, (<-sub.Events).Type)
 }
 
 // TODO: we don't have a good way of testing CB6 support without CMOS-91 - the cbrest test server starts on a
@@ -0,0 +1,109 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+// Package events provides a bus to broadcast changes in the state of the clusters to any interested subscribers.
+package events
+
+import (
+	"sync"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"go.uber.org/zap"
+)
+
+// subscriptionBuffer is how many events a subscriber can fall behind before events start being dropped for it.
+const subscriptionBuffer = 64
+
+// Bus broadcasts events to all its subscribers. Publishing never blocks, if a subscriber is too slow to keep up the
+// events it cannot take are dropped. A nil bus is valid and drops every event.
+type Bus struct {
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+}
+
+// Subscription receives the events published to the bus that match its clusters.
+type Subscription struct {
+	// Events is closed once the subscription is closed.
+	Events <-chan values.Event
+
+	events   chan values.Event
+	clusters map[string]bool
+	bus      *Bus
+}
+
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[*Subscription]struct{})}
+}
+
+// Subscribe returns a subscription for the events of the given clusters, if no clusters are given it will receive the
+// events for all clusters. The subscription must be closed once it is no longer needed.
+func (b *Bus) Subscribe(clusterUUIDs ...string) *Subscription {
+	sub := &Subscription{
+		events:   make(chan values.Event, subscriptionBuffer),
+		clusters: make(map[string]bool, len(clusterUUIDs)),
+		bus:      b,
+	}
+
+	sub.Events = sub.events
+	for _, uuid := range clusterUUIDs {
+		sub.clusters[uuid] = true
+	}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub
+}
+
+// Publish sends the events to all the subscribers interested in them.
+func (b *Bus) Publish(events ...values.Event) {
+	if b == nil || len(events) == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		for _, event := range events {
+			if len(sub.clusters) != 0 && !sub.clusters[event.ClusterUUID] {
+				continue
+			}
+
+			select {
+			case sub.events <- event:
+			default:
+				zap.S().Warnw("(Events) Subscriber is not keeping up, dropping event", "type", event.Type, "cluster",
+					event.ClusterUUID)
+			}
+		}
+	}
+}
+
+// CloseSubscriptions closes all the current subscriptions. The bus can still be used afterwards.
+func (b *Bus) CloseSubscriptions() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		delete(b.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// Close stops the subscription from receiving any more events. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	if _, ok := s.bus.subscribers[s]; !ok {
+		return
+	}
+
+	delete(s.bus.subscribers, s)
+	close(s.events)
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package events
+
+import (
+	"testing"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBusFiltersByCluster(t *testing.T) {
+	bus := NewBus()
+
+	all := bus.Subscribe()
+	defer all.Close()
+
+	filtered := bus.Subscribe("uuid-1")
+	defer filtered.Close()
+
+	bus.Publish(
+		values.Event{Type: values.ClusterAddedEvent, ClusterUUID: "uuid-0"},
+		values.Event{Type: values.ClusterAddedEvent, ClusterUUID: "uuid-1"},
+	)
+
+	require.Equal(t, "uuid-0", (<-all.Events).ClusterUUID)
+	require.Equal(t, "uuid-1", (<-all.Events).ClusterUUID)
+	require.Equal(t, "uuid-1", (<-filtered.Events).ClusterUUID)
+	require.Len(t, filtered.Events, 0)
+}
+
+func TestBusDropsEventsForSlowSubscribers(t *testing.T) {
+	bus := NewBus()
+
+	sub := bus.Subscribe()
+	defer sub.Close()
+
+	for i := 0; i < subscriptionBuffer+10; i++ {
+		bus.Publish(values.Event{Type: values.ClusterAddedEvent, ClusterUUID: "uuid-0"})
+	}
+
+	require.Len(t, sub.Events, subscriptionBuffer)
+}
+
+func TestBusClose(t *testing.T) {
+	bus := NewBus()
+
+	sub := bus.Subscribe()
+	sub.Close()
+	sub.Close()
+
+	_, ok := <-sub.Events
+	require.False(t, ok)
+
+	other := bus.Subscribe()
+	bus.CloseSubscriptions()
+	other.Close()
+
+	_, ok = <-other.Events
+	require.False(t, ok)
+
+	// publishing with no subscribers or on a nil bus should do nothing
+	bus.Publish(values.Event{Type: values.ClusterAddedEvent})
+	(*Bus)(nil).Publish(values.Event{Type: values.ClusterAddedEvent})
+}
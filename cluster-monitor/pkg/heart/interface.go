@@ -6,6 +6,7 @@
 package heart
 
 import (
+	"context"
 	"time"
 
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
@@ -16,5 +17,6 @@ import (
 type MonitorIFace interface {
 	Start(heartBeatFrequency time.Duration)
 	Stop()
-	HeartBeatCluster(cluster *values.CouchbaseCluster) error
+	Reconfigure(heartBeatFrequency time.Duration)
+	HeartBeatCluster(ctx context.Context, cluster *values.CouchbaseCluster) error
 }
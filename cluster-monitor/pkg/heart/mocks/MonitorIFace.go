@@ -3,10 +3,12 @@
 package mocks
 
 import (
-	time "time"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
 
+	time "time"
+
 	values "github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
 )
 
@@ -15,13 +17,13 @@ type MonitorIFace struct {
 	mock.Mock
 }
 
-// HeartBeatCluster provides a mock function with given fields: cluster
-func (_m *MonitorIFace) HeartBeatCluster(cluster *values.CouchbaseCluster) error {
-	ret := _m.Called(cluster)
+// HeartBeatCluster provides a mock function with given fields: ctx, cluster
+func (_m *MonitorIFace) HeartBeatCluster(ctx context.Context, cluster *values.CouchbaseCluster) error {
+	ret := _m.Called(ctx, cluster)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*values.CouchbaseCluster) error); ok {
-		r0 = rf(cluster)
+	if rf, ok := ret.Get(0).(func(context.Context, *values.CouchbaseCluster) error); ok {
+		r0 = rf(ctx, cluster)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -29,6 +31,11 @@ func (_m *MonitorIFace) HeartBeatCluster(cluster *values.CouchbaseCluster) error
 	return r0
 }
 
+// Reconfigure provides a mock function with given fields: heartBeatFrequency
+func (_m *MonitorIFace) Reconfigure(heartBeatFrequency time.Duration) {
+	_m.Called(heartBeatFrequency)
+}
+
 // Start provides a mock function with given fields: heartBeatFrequency
 func (_m *MonitorIFace) Start(heartBeatFrequency time.Duration) {
 	_m.Called(heartBeatFrequency)
@@ -13,6 +13,8 @@ import (
 	"time"
 
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/couchbase"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/events"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/scheduler"
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/storage"
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
 
@@ -30,10 +32,35 @@ type Monitor struct {
 	workStream chan *values.CouchbaseCluster
 	numWorkers int
 	workerWg   sync.WaitGroup
+
+	// historyRetention is how long the heartbeat history is kept for.
+	historyRetention time.Duration
+
+	// events is where the changes found by the heartbeats are published.
+	events *events.Bus
+
+	// clients keeps the REST clients between heartbeats so the clusters are not bootstrapped every time.
+	clients *couchbase.ClientCache
+
+	// scheduler decides which clusters are due a heartbeat given their frequencies.
+	scheduler *scheduler.Scheduler
+	// reconfigured is signalled when the frequencies change so the heartbeat loop can adjust its ticker.
+	reconfigured chan struct{}
 }
 
-func NewMonitor(store storage.Store, workers int) *Monitor {
-	return &Monitor{store: store, numWorkers: workers}
+func NewMonitor(store storage.Store, workers int, historyRetention time.Duration, bus *events.Bus,
+	clients *couchbase.ClientCache) *Monitor {
+	return &Monitor{
+		store:            store,
+		numWorkers:       workers,
+		historyRetention: historyRetention,
+		events:           bus,
+		clients:          clients,
+		scheduler: scheduler.NewScheduler(store, func(frequencies *values.ClusterFrequencies) time.Duration {
+			return frequencies.Heart
+		}, "(Heart Monitor)"),
+		reconfigured: make(chan struct{}, 1),
+	}
 }
 
 func (m *Monitor) Start(heartBeatFrequency time.Duration) {
@@ -43,9 +70,22 @@ func (m *Monitor) Start(heartBeatFrequency time.Duration) {
 	}
 
 	zap.S().Infow("(Heart Monitor) Starting monitor", "frequency", heartBeatFrequency)
+	m.scheduler.SetFrequency(heartBeatFrequency)
 	m.ctx, m.cancel = context.WithCancel(context.Background())
 	m.wg.Add(1)
-	go m.heartBeat(heartBeatFrequency)
+	go m.heartBeat()
+}
+
+// Reconfigure changes the default heartbeat frequency and picks up any changes to the cluster frequencies. Heartbeats
+// in progress are not interrupted, the new frequencies are used from the next tick.
+func (m *Monitor) Reconfigure(heartBeatFrequency time.Duration) {
+	zap.S().Infow("(Heart Monitor) Reconfiguring monitor", "frequency", heartBeatFrequency)
+	m.scheduler.SetFrequency(heartBeatFrequency)
+
+	select {
+	case m.reconfigured <- struct{}{}:
+	default:
+	}
 }
 
 func (m *Monitor) Stop() {
@@ -60,8 +100,9 @@ func (m *Monitor) Stop() {
 	m.ctx, m.cancel = nil, nil
 }
 
-func (m *Monitor) heartBeat(heartBeatFrequency time.Duration) {
-	ticker := time.NewTicker(heartBeatFrequency)
+func (m *Monitor) heartBeat() {
+	tick := m.scheduler.TickInterval()
+	ticker := time.NewTicker(tick)
 	defer func() {
 		m.wg.Done()
 		ticker.Stop()
@@ -70,33 +111,49 @@ func (m *Monitor) heartBeat(heartBeatFrequency time.Duration) {
 	for {
 		select {
 		case <-ticker.C:
-			if err := m.doClustersHeartBeat(); err != nil {
+			if err := m.doClustersHeartBeat(m.ctx, tick); err != nil {
 				zap.S().Warnw("(Heart Monitor) There was an issue performing clusters heartbeat", "err", err.Error())
 			}
+		case <-m.reconfigured:
 		case <-m.ctx.Done():
 			return
 		}
+
+		if newTick := m.scheduler.TickInterval(); newTick != tick {
+			zap.S().Infow("(Heart Monitor) Changing tick interval", "old", tick, "new", newTick)
+			tick = newTick
+			ticker.Reset(tick)
+		}
 	}
 }
 
-func (m *Monitor) doClustersHeartBeat() error {
+// doClustersHeartBeat does the heartbeat for the clusters that are due given their frequency and the tick interval.
+// Cancelling ctx stops the heartbeats in progress and skips the clusters that have not been started.
+func (m *Monitor) doClustersHeartBeat(ctx context.Context, tick time.Duration) error {
 	zap.S().Infow("(Heart Monitor) Starting heartbeat")
 	start := time.Now()
-	clusters, err := m.store.GetClusters(true, false)
+	allClusters, err := m.store.GetClusters(true, false)
 	if err != nil {
 		return fmt.Errorf("could not get clusters to perform heartbeat: %w", err)
 	}
 
+	clusters := m.scheduler.DueClusters(allClusters, start, tick)
+
 	m.workStream = make(chan *values.CouchbaseCluster)
 	// start the workers
 	for i := 0; i < m.numWorkers; i++ {
 		m.workerWg.Add(1)
-		go m.heartBeatWorkerFn()
+		go m.heartBeatWorkerFn(ctx)
 	}
 
 	// send the data
+sendLoop:
 	for _, cluster := range clusters {
-		m.workStream <- cluster
+		select {
+		case m.workStream <- cluster:
+		case <-ctx.Done():
+			break sendLoop
+		}
 	}
 
 	close(m.workStream)
@@ -104,26 +161,46 @@ func (m *Monitor) doClustersHeartBeat() error {
 	// to avoid starting the next heartbeat before finishing this one we wait until all the workers are done
 	m.workerWg.Wait()
 
+	if err = m.store.DeleteHeartbeatsBefore(time.Now().Add(-m.historyRetention)); err != nil {
+		zap.S().Warnw("(Heart Monitor) Could not remove old heartbeat history", "err", err)
+	}
+
 	zap.S().Debugw("(Heart Monitor) heartbeat finished", "elapsed", time.Since(start).String(), "#clusters",
 		len(clusters))
 	return nil
 }
 
-func (m *Monitor) heartBeatWorkerFn() {
+func (m *Monitor) heartBeatWorkerFn(ctx context.Context) {
 	defer m.workerWg.Done()
 
 	for cluster := range m.workStream {
-		if err := m.HeartBeatCluster(cluster); err != nil {
+		if err := m.HeartBeatCluster(ctx, cluster); err != nil {
 			zap.S().Errorw("(Heart Monitor) Could not update cluster state", "uuid", cluster.UUID, "err", err)
 		}
 	}
 }
 
-func (m *Monitor) HeartBeatCluster(cluster *values.CouchbaseCluster) error {
+// HeartBeatCluster checks the cluster can be reached and updates its state in the store. Each request to the cluster is
+// bounded by its request timeout, if ctx is cancelled the heartbeat is abandoned without recording anything.
+func (m *Monitor) HeartBeatCluster(ctx context.Context, cluster *values.CouchbaseCluster) error {
 	zap.S().Debugw("(Heart Monitor) Heat beat for cluster", "uuid", cluster.UUID, "hosts",
 		cluster.NodesSummary.GetHosts())
-	client, err := couchbase.NewClient(cluster.NodesSummary.GetHosts(), cluster.User, cluster.Password,
-		cluster.GetTLSConfig(), false)
+	start := time.Now()
+	client, err := m.clients.GetClient(ctx, cluster)
+	// clients only get the cluster information when bootstrapping so the ones reused from previous heartbeats have to
+	// get it again
+	if err == nil && client.GetBootstrap().Before(start) {
+		if err = client.Refresh(ctx); err != nil {
+			m.clients.Invalidate(cluster.UUID)
+		}
+	}
+
+	latency := time.Since(start)
+	// the failure is down to the heartbeat being cancelled rather than the cluster so there is nothing to record
+	if ctx.Err() != nil {
+		return fmt.Errorf("heartbeat cancelled: %w", ctx.Err())
+	}
+
 	// in failure cases update cluster entry to reflect issue
 	if err != nil {
 		zap.S().Warnw("(Heart Monitor) Cluster heartbeat failed", "uuid", cluster.UUID, "err", err)
@@ -131,40 +208,72 @@ func (m *Monitor) HeartBeatCluster(cluster *values.CouchbaseCluster) error {
 		var authError couchbase.AuthError
 		if errors.As(err, &authError) {
 			issue = values.BadAuthHeartIssue
+		} else if couchbase.IsTimeout(err) {
+			issue = values.TimeoutHeartIssue
 		}
 
-		return m.store.UpdateCluster(&values.CouchbaseCluster{
+		m.addHeartbeat(cluster.UUID, issue, err.Error(), start, latency)
+		return m.updateCluster(cluster, &values.CouchbaseCluster{
 			UUID:           cluster.UUID,
 			HeartBeatIssue: issue,
 		})
 	}
 
+	info := client.GetClusterInfo()
 	// check that the uuid has not changed
-	if client.ClusterInfo.ClusterUUID != cluster.UUID {
-		zap.S().Warnw("(Heart Monitor) Cluster UUID changed", "old", cluster.UUID, "new",
-			client.ClusterInfo.ClusterUUID)
-		return m.store.UpdateCluster(&values.CouchbaseCluster{
+	if info.ClusterUUID != cluster.UUID {
+		zap.S().Warnw("(Heart Monitor) Cluster UUID changed", "old", cluster.UUID, "new", info.ClusterUUID)
+		m.addHeartbeat(cluster.UUID, values.UUIDMismatchHeartIssue,
+			fmt.Sprintf("cluster UUID changed to '%s'", info.ClusterUUID), start, latency)
+		return m.updateCluster(cluster, &values.CouchbaseCluster{
 			UUID:           cluster.UUID,
 			HeartBeatIssue: values.UUIDMismatchHeartIssue,
-			Enterprise:     client.ClusterInfo.Enterprise,
+			Enterprise:     info.Enterprise,
 		})
 	}
 
 	// get up to date buckets information. If we fail to get the buckets we still update all the other stuff
 	var buckets values.BucketsSummary
-	buckets, err = client.GetBucketsSummary()
+	buckets, err = client.GetBucketsSummary(ctx)
 	if err != nil {
 		zap.S().Errorw("(Heart Monitor) Could not update buckets summary", "cluster", cluster.UUID, "err", err)
 	}
 
 	// otherwise the heartbeat is OK so we just update the hosts and cluster name
-	return m.store.UpdateCluster(&values.CouchbaseCluster{
+	m.addHeartbeat(cluster.UUID, values.NoHeartIssue, "", start, latency)
+	return m.updateCluster(cluster, &values.CouchbaseCluster{
 		UUID:           cluster.UUID,
-		Enterprise:     client.ClusterInfo.Enterprise,
-		NodesSummary:   client.ClusterInfo.NodesSummary,
-		Name:           client.ClusterInfo.ClusterName,
-		ClusterInfo:    client.ClusterInfo.ClusterInfo,
+		Enterprise:     info.Enterprise,
+		NodesSummary:   info.NodesSummary,
+		Name:           info.ClusterName,
+		ClusterInfo:    info.ClusterInfo,
 		HeartBeatIssue: values.NoHeartIssue,
 		BucketsSummary: buckets,
 	})
 }
+
+// updateCluster stores the update and publishes the changes from the previous state of the cluster.
+func (m *Monitor) updateCluster(cluster, update *values.CouchbaseCluster) error {
+	if err := m.store.UpdateCluster(update); err != nil {
+		return err
+	}
+
+	m.events.Publish(values.ClusterChangeEvents(cluster, update, time.Now().UTC())...)
+	return nil
+}
+
+// addHeartbeat adds the heartbeat to the cluster history. Failing to do so should not stop the cluster from being
+// updated so errors are only logged.
+func (m *Monitor) addHeartbeat(uuid string, issue values.HeartIssue, errMsg string, start time.Time,
+	latency time.Duration) {
+	err := m.store.AddHeartbeat(&values.HeartbeatRecord{
+		ClusterUUID: uuid,
+		Issue:       issue,
+		Error:       errMsg,
+		Latency:     latency,
+		Time:        start.UTC(),
+	})
+	if err != nil {
+		zap.S().Warnw("(Heart Monitor) Could not add heartbeat to history", "uuid", uuid, "err", err)
+	}
+}
@@ -6,6 +6,7 @@
 package heart
 
 import (
+	"context"
 	"net"
 	"net/http"
 	"os"
@@ -15,6 +16,7 @@ import (
 	"time"
 
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/couchbase"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/events"
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/storage/sqlite"
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
 
@@ -127,7 +129,11 @@ func TestHeartMonitorClusterOK(t *testing.T) {
 
 	beforeHeartBeat := time.Now()
 
-	monitor := NewMonitor(store, 1)
+	bus := events.NewBus()
+	sub := bus.Subscribe()
+	defer sub.Close()
+
+	monitor := NewMonitor(store, 1, time.Hour, bus, couchbase.NewClientCache(time.Hour))
 	monitor.Start(300 * time.Millisecond)
 	time.Sleep(1 * time.Second)
 	monitor.Stop()
@@ -140,6 +146,17 @@ func TestHeartMonitorClusterOK(t *testing.T) {
 
 	require.Len(t, cluster.BucketsSummary, 0)
 
+	// only the first heartbeat changes the cluster so later ones should not publish anything
+	require.Len(t, sub.Events, 2)
+	event := <-sub.Events
+	require.Equal(t, values.NodeStatusChangedEvent, event.Type)
+	require.Equal(t, "N0", event.Node)
+	require.Equal(t, "warmup", event.Old)
+	require.Equal(t, "healthy", event.New)
+	event = <-sub.Events
+	require.Equal(t, values.BucketRemovedEvent, event.Type)
+	require.Equal(t, "B0", event.Bucket)
+
 	//  things we do not want to compare
 	cluster.LastUpdate = time.Time{}
 	cluster.ClusterInfo = nil
@@ -166,6 +183,13 @@ func TestHeartMonitorClusterOK(t *testing.T) {
 	}
 
 	require.Equal(t, expectedCluster, cluster)
+
+	// several heartbeats happened but as the state did not change only one is recorded
+	history, err := store.GetHeartbeats("uuid-0", beforeHeartBeat)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, values.NoHeartIssue, history[0].Issue)
+	require.Empty(t, history[0].Error)
 }
 
 func TestHeartMonitorClusterBadAuth(t *testing.T) {
@@ -257,11 +281,21 @@ func TestHeartMonitorClusterBadAuth(t *testing.T) {
 
 	beforeHeartBeat := time.Now()
 
-	monitor := NewMonitor(store, 1)
+	bus := events.NewBus()
+	sub := bus.Subscribe("uuid-0")
+	defer sub.Close()
+
+	monitor := NewMonitor(store, 1, time.Hour, bus, couchbase.NewClientCache(time.Hour))
 	monitor.Start(300 * time.Millisecond)
 	time.Sleep(1 * time.Second)
 	monitor.Stop()
 
+	require.Len(t, sub.Events, 1)
+	event := <-sub.Events
+	require.Equal(t, values.HeartbeatIssueChangedEvent, event.Type)
+	require.Equal(t, values.NoHeartIssue.String(), event.Old)
+	require.Equal(t, values.BadAuthHeartIssue.String(), event.New)
+
 	outCluster, err := store.GetCluster("uuid-0", true)
 	require.NoError(t, err)
 
@@ -279,6 +313,12 @@ func TestHeartMonitorClusterBadAuth(t *testing.T) {
 	cluster.HeartBeatIssue = values.BadAuthHeartIssue
 
 	require.Equal(t, cluster, outCluster)
+
+	history, err := store.GetHeartbeats("uuid-0", beforeHeartBeat)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, values.BadAuthHeartIssue, history[0].Issue)
+	require.NotEmpty(t, history[0].Error)
 }
 
 func TestHeartMonitorClusterUUIDMismatch(t *testing.T) {
@@ -369,7 +409,7 @@ func TestHeartMonitorClusterUUIDMismatch(t *testing.T) {
 
 	beforeHeartBeat := time.Now()
 
-	monitor := NewMonitor(store, 1)
+	monitor := NewMonitor(store, 1, time.Hour, nil, couchbase.NewClientCache(time.Hour))
 	monitor.Start(200 * time.Millisecond)
 	time.Sleep(1 * time.Second)
 	monitor.Stop()
@@ -392,4 +432,62 @@ func TestHeartMonitorClusterUUIDMismatch(t *testing.T) {
 	cluster.HeartBeatIssue = values.UUIDMismatchHeartIssue
 
 	require.Equal(t, cluster, outCluster)
+
+	history, err := store.GetHeartbeats("uuid-0", beforeHeartBeat)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, values.UUIDMismatchHeartIssue, history[0].Issue)
+	require.NotEmpty(t, history[0].Error)
+}
+
+func TestHeartMonitorClusterTimeout(t *testing.T) {
+	store, err := sqlite.NewSQLiteDB(filepath.Join(t.TempDir(), "store.sqlite"), "key")
+	require.NoError(t, err)
+	defer store.Close()
+
+	testHandler := couchbase.TestHandler{
+		ClusterUUID:      "uuid-0",
+		Nodes:            []couchbase.TestNode{{NodeUUID: "N0", Hostname: "127.0.0.1:9000", Status: "healthy"}},
+		NodesReturnCode:  http.StatusOK,
+		BucketReturnCode: http.StatusOK,
+	}
+
+	testHandler.Start(t, false, true)
+	defer testHandler.Close()
+
+	cluster := &values.CouchbaseCluster{
+		UUID:           "uuid-0",
+		Name:           "cluster-0",
+		Enterprise:     true,
+		User:           "user",
+		Password:       "password",
+		NodesSummary:   values.NodesSummary{{NodeUUID: "N0", Host: testHandler.URL(), Status: "healthy"}},
+		RequestTimeout: 200 * time.Millisecond,
+	}
+	require.NoError(t, store.AddCluster(cluster))
+
+	clients := couchbase.NewClientCache(time.Hour)
+	defer clients.Close()
+
+	// bootstrapping the client cannot be bounded so it is done before the cluster becomes slow
+	_, err = clients.GetClient(context.Background(), cluster)
+	require.NoError(t, err)
+
+	testHandler.NodesDelay = time.Minute
+	monitor := NewMonitor(store, 1, time.Hour, events.NewBus(), clients)
+
+	start := time.Now()
+	require.NoError(t, monitor.HeartBeatCluster(context.Background(), cluster))
+	require.Less(t, time.Since(start), 10*time.Second)
+
+	outCluster, err := store.GetCluster("uuid-0", false)
+	require.NoError(t, err)
+	require.Equal(t, values.TimeoutHeartIssue, outCluster.HeartBeatIssue)
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		require.ErrorIs(t, monitor.HeartBeatCluster(ctx, cluster), context.Canceled)
+	})
 }
@@ -91,6 +91,7 @@ func (m *Manager) initializeCluster(w http.ResponseWriter, r *http.Request) {
 		User:     info.User,
 		Password: hashedPassword,
 		Admin:    true,
+		Role:     values.AdminRole,
 	})
 	if err != nil {
 		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
@@ -159,6 +160,7 @@ func (m *Manager) SetupAdminUser(user string, hashedPassword []byte) error {
 		User:     user,
 		Password: hashedPassword,
 		Admin:    true,
+		Role:     values.AdminRole,
 	})
 	if err != nil {
 		if errors.Is(err, storage.ErrUserAlreadyExists) {
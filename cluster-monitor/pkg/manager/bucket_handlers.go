@@ -0,0 +1,120 @@
+// Copyright (C) 2022 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/memcached"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/couchbase/tools-common/restutil"
+	"github.com/gorilla/mux"
+)
+
+type bucketCollections struct {
+	ClusterUUID string `json:"cluster_uuid"`
+	Bucket      string `json:"bucket"`
+	// Collections are the statistics of each collection summed across the data nodes, largest memory usage first.
+	Collections []*memcached.CollectionStats `json:"collections"`
+	// Errors are the data nodes the statistics could not be got from, the collections are then only summed across the
+	// other nodes.
+	Errors []nodeError `json:"errors,omitempty"`
+}
+
+type nodeError struct {
+	Host  string `json:"host"`
+	Error string `json:"error"`
+}
+
+// getBucketCollections returns the statistics of every collection in the bucket, aggregated across all the data nodes
+// of the cluster. If some of the nodes fail the statistics of the others are still returned, together with the errors.
+func (m *Manager) getBucketCollections(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	uuid, ok := m.convertAliasToUUID(vars["uuid"], w)
+	if !ok {
+		return
+	}
+
+	cluster, err := m.store.GetCluster(uuid, true)
+	if err != nil {
+		if errors.Is(err, values.ErrNotFound) {
+			restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+				Status: http.StatusNotFound,
+				Msg:    fmt.Sprintf("cluster with UUID '%s' not found", uuid),
+			}, w, nil)
+			return
+		}
+
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusInternalServerError,
+			Msg:    "could not get cluster details",
+			Extras: err.Error(),
+		}, w, nil)
+		return
+	}
+
+	bucket := vars["bucket"]
+	if !hasBucket(cluster, bucket) {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusNotFound,
+			Msg:    fmt.Sprintf("bucket '%s' not found", bucket),
+		}, w, nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), cluster.GetRequestTimeout())
+	defer cancel()
+
+	client, err := m.newMemcachedClient(ctx, cluster)
+	if err != nil {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusInternalServerError,
+			Msg:    "could not connect to the data nodes",
+			Extras: err.Error(),
+		}, w, nil)
+		return
+	}
+	defer client.Close()
+
+	stats, err := client.CollectionStats(bucket)
+	var nodesErr *memcached.NodesError
+	if err != nil && !errors.As(err, &nodesErr) {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusInternalServerError,
+			Msg:    "could not get collection stats",
+			Extras: err.Error(),
+		}, w, nil)
+		return
+	}
+
+	collections := &bucketCollections{
+		ClusterUUID: uuid,
+		Bucket:      bucket,
+		Collections: memcached.AggregateCollectionStats(stats),
+	}
+
+	if nodesErr != nil {
+		for _, host := range nodesErr.Hosts() {
+			collections.Errors = append(collections.Errors, nodeError{Host: host, Error: nodesErr.Errors[host].Error()})
+		}
+	}
+
+	restutil.MarshalAndSend(http.StatusOK, collections, w, nil)
+}
+
+func hasBucket(cluster *values.CouchbaseCluster, name string) bool {
+	for _, bucket := range cluster.BucketsSummary {
+		if bucket.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,122 @@
+// Copyright (C) 2022 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/memcached"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/memcached/mocks"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBucketCollections(t *testing.T) {
+	mgr := createTestManager(t)
+	require.NoError(t, mgr.store.AddCluster(&values.CouchbaseCluster{
+		UUID:           "uuid-0",
+		Enterprise:     true,
+		User:           "user",
+		Password:       "password",
+		NodesSummary:   values.NodesSummary{{NodeUUID: "N0", Host: "http://localhost:9000"}},
+		BucketsSummary: values.BucketsSummary{{Name: "B0"}, {Name: "B1"}, {Name: "B2"}},
+	}))
+
+	client := &mocks.ConnIFace{}
+	client.On("CollectionStats", "B0").Return([]*memcached.CollectionStats{
+		{ScopeID: "0x0", Scope: "_default", ID: "0x0", Name: "_default", Items: 1, MemUsed: 10, Host: "h0"},
+		{ScopeID: "0x8", Scope: "shop", ID: "0x9", Name: "orders", Items: 5, MemUsed: 100, Host: "h0"},
+		{ScopeID: "0x8", Scope: "shop", ID: "0x9", Name: "orders", Items: 6, MemUsed: 200, Host: "h1"},
+	}, nil)
+	client.On("CollectionStats", "B1").Return(nil, fmt.Errorf("connection refused"))
+	client.On("CollectionStats", "B2").Return([]*memcached.CollectionStats{
+		{ScopeID: "0x0", Scope: "_default", ID: "0x0", Name: "_default", Items: 2, MemUsed: 20, Host: "h0"},
+	}, &memcached.NodesError{Errors: map[string]error{"h1": fmt.Errorf("connection refused")}})
+	client.On("Close").Return(nil)
+
+	mgr.newMemcachedClient = func(_ context.Context, cluster *values.CouchbaseCluster) (memcached.ConnIFace, error) {
+		require.Equal(t, "uuid-0", cluster.UUID)
+		return client, nil
+	}
+
+	mgr.setupKeys()
+	mgr.startRESTServers()
+	defer mgr.stopRESTServers()
+
+	time.Sleep(100 * time.Millisecond)
+
+	cases := []struct {
+		name           string
+		cluster        string
+		bucket         string
+		expectedStatus int
+		expected       *bucketCollections
+	}{
+		{
+			name:           "OK",
+			cluster:        "uuid-0",
+			bucket:         "B0",
+			expectedStatus: http.StatusOK,
+			expected: &bucketCollections{
+				ClusterUUID: "uuid-0",
+				Bucket:      "B0",
+				Collections: []*memcached.CollectionStats{
+					{ScopeID: "0x8", Scope: "shop", ID: "0x9", Name: "orders", Items: 11, MemUsed: 300},
+					{ScopeID: "0x0", Scope: "_default", ID: "0x0", Name: "_default", Items: 1, MemUsed: 10},
+				},
+			},
+		},
+		{
+			name:           "partial",
+			cluster:        "uuid-0",
+			bucket:         "B2",
+			expectedStatus: http.StatusOK,
+			expected: &bucketCollections{
+				ClusterUUID: "uuid-0",
+				Bucket:      "B2",
+				Collections: []*memcached.CollectionStats{
+					{ScopeID: "0x0", Scope: "_default", ID: "0x0", Name: "_default", Items: 2, MemUsed: 20},
+				},
+				Errors: []nodeError{{Host: "h1", Error: "connection refused"}},
+			},
+		},
+		{name: "clusterNotFound", cluster: "uuid-9", bucket: "B0", expectedStatus: http.StatusNotFound},
+		{name: "bucketNotFound", cluster: "uuid-0", bucket: "B9", expectedStatus: http.StatusNotFound},
+		{name: "statsError", cluster: "uuid-0", bucket: "B1", expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet,
+				fmt.Sprintf("http://127.0.0.1:%d/api/v1/clusters/%s/buckets/%s/collections", mgr.config.HTTPPort,
+					tc.cluster, tc.bucket), nil)
+			require.NoError(t, err)
+
+			req.SetBasicAuth("user", "password")
+
+			res, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+
+			require.Equal(t, tc.expectedStatus, res.StatusCode)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var collections bucketCollections
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&collections))
+			require.Equal(t, *tc.expected, collections)
+		})
+	}
+
+	client.AssertNumberOfCalls(t, "Close", 3)
+}
@@ -0,0 +1,277 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/couchbase"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/couchbase/tools-common/restutil"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// maxBulkClusters is the maximum number of clusters that can be added in a single request.
+	maxBulkClusters = 500
+	// maxBulkBodySize is the maximum size of a bulk request body, enough for every cluster to have a CA certificate.
+	maxBulkBodySize = 4 * 1024 * 1024
+)
+
+// errBulkBodyTooLarge is returned when the bulk request body is larger than maxBulkBodySize.
+var errBulkBodyTooLarge = fmt.Errorf("body is larger than %d bytes", maxBulkBodySize)
+
+type bulkAddResult string
+
+const (
+	bulkAdded         bulkAddResult = "added"
+	bulkReachable     bulkAddResult = "reachable"
+	bulkAlreadyExists bulkAddResult = "already_exists"
+	bulkInvalid       bulkAddResult = "invalid"
+	bulkAuthFailed    bulkAddResult = "auth_failed"
+	bulkUnreachable   bulkAddResult = "unreachable"
+	bulkFailed        bulkAddResult = "failed"
+)
+
+// bulkYAMLEntry is a cluster in a YAML manifest. Unlike in JSON the CA certificate is given as the PEM text rather than
+// base64 encoded.
+type bulkYAMLEntry struct {
+	Host           string `yaml:"host"`
+	User           string `yaml:"user"`
+	Password       string `yaml:"password"`
+	Alias          string `yaml:"alias"`
+	CaCert         string `yaml:"ca_cert"`
+	RequestTimeout string `yaml:"request_timeout"`
+}
+
+type bulkAddClusterResult struct {
+	Host        string        `json:"host"`
+	Alias       string        `json:"alias,omitempty"`
+	ClusterUUID string        `json:"cluster_uuid,omitempty"`
+	ClusterName string        `json:"cluster_name,omitempty"`
+	Result      bulkAddResult `json:"result"`
+	Error       string        `json:"error,omitempty"`
+}
+
+type bulkAddClustersRes struct {
+	DryRun  bool                    `json:"dry_run"`
+	Results []*bulkAddClusterResult `json:"results"`
+}
+
+// addClusters adds a list of clusters given either as JSON or YAML. The clusters are connected to concurrently and a
+// result is returned for each of them, in the same order, so a failure does not stop the others from being added. When
+// the dry_run query parameter is true the clusters are only connected to.
+func (m *Manager) addClusters(w http.ResponseWriter, r *http.Request) {
+	var dryRun bool
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+				Status: http.StatusBadRequest,
+				Msg:    "dry_run must be a boolean",
+			}, w, nil)
+			return
+		}
+	}
+
+	reqs, err := decodeBulkAddClustersReq(w, r)
+	if err != nil {
+		if errors.Is(err, errBulkBodyTooLarge) {
+			restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+				Status: http.StatusRequestEntityTooLarge,
+				Msg:    "request body is too large",
+				Extras: err.Error(),
+			}, w, nil)
+			return
+		}
+
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusBadRequest,
+			Msg:    "could not decode clusters",
+			Extras: err.Error(),
+		}, w, nil)
+		return
+	}
+
+	if len(reqs) == 0 || len(reqs) > maxBulkClusters {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusBadRequest,
+			Msg:    fmt.Sprintf("between 1 and %d clusters must be given", maxBulkClusters),
+		}, w, nil)
+		return
+	}
+
+	workers := m.config.MaxWorkers
+	if workers < 1 {
+		workers = 1
+	}
+
+	if workers > len(reqs) {
+		workers = len(reqs)
+	}
+
+	var (
+		results = make([]*bulkAddClusterResult, len(reqs))
+		indexes = make(chan int, len(reqs))
+		// the store checks and writes are serialized so duplicated entries are reported as already existing
+		storeLock sync.Mutex
+		wg        sync.WaitGroup
+	)
+
+	for i := range reqs {
+		indexes <- i
+	}
+	close(indexes)
+
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				results[index] = m.addClusterFromBulk(r.Context(), reqs[index], dryRun, &storeLock)
+			}
+		}()
+	}
+	wg.Wait()
+
+	zap.S().Infow("(Manager) Bulk cluster add finished", "clusters", len(reqs), "dryRun", dryRun)
+	restutil.MarshalAndSend(http.StatusOK, &bulkAddClustersRes{DryRun: dryRun, Results: results}, w, nil)
+}
+
+func (m *Manager) addClusterFromBulk(ctx context.Context, req *addClusterReq, dryRun bool,
+	storeLock *sync.Mutex) *bulkAddClusterResult {
+	result := &bulkAddClusterResult{Host: req.Host, Alias: req.Alias}
+	fail := func(res bulkAddResult, err error) *bulkAddClusterResult {
+		result.Result = res
+		result.Error = err.Error()
+		return result
+	}
+
+	if err := req.validate(); err != nil {
+		return fail(bulkInvalid, err)
+	}
+
+	cluster, err := req.connect(ctx)
+	if err != nil {
+		var authErr couchbase.AuthError
+		if errors.As(err, &authErr) {
+			return fail(bulkAuthFailed, err)
+		}
+
+		return fail(bulkUnreachable, err)
+	}
+
+	result.ClusterUUID = cluster.UUID
+	result.ClusterName = cluster.Name
+
+	storeLock.Lock()
+	defer storeLock.Unlock()
+
+	_, err = m.store.GetCluster(cluster.UUID, false)
+	if err == nil {
+		result.Result = bulkAlreadyExists
+		return result
+	}
+
+	if !errors.Is(err, values.ErrNotFound) {
+		return fail(bulkFailed, fmt.Errorf("could not check if cluster exists: %w", err))
+	}
+
+	if dryRun {
+		result.Result = bulkReachable
+		return result
+	}
+
+	if err = m.store.AddCluster(cluster); err != nil {
+		return fail(bulkFailed, fmt.Errorf("could not save cluster: %w", err))
+	}
+
+	m.events.Publish(values.Event{
+		Type:        values.ClusterAddedEvent,
+		ClusterUUID: cluster.UUID,
+		ClusterName: cluster.Name,
+		Time:        time.Now().UTC(),
+	})
+	zap.S().Infow("(Manager) Cluster added", "cluster", cluster.UUID)
+
+	// CE clusters don't run checkers so skip triggering API check
+	if cluster.Enterprise {
+		go m.checkCluster(cluster)
+	}
+
+	result.Result = bulkAdded
+	return result
+}
+
+// decodeBulkAddClustersReq decodes the list of clusters as YAML if the request content type says so and as JSON
+// otherwise. Bodies larger than maxBulkBodySize are rejected without being read in full.
+func decodeBulkAddClustersReq(w http.ResponseWriter, r *http.Request) ([]*addClusterReq, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBulkBodySize))
+	if err != nil {
+		// MaxBytesReader returns everything up to the limit before failing
+		if len(body) >= maxBulkBodySize {
+			return nil, errBulkBodyTooLarge
+		}
+
+		return nil, fmt.Errorf("could not read body: %w", err)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		var entries []*bulkYAMLEntry
+		if err = yaml.UnmarshalStrict(body, &entries); err != nil {
+			return nil, err
+		}
+
+		reqs := make([]*addClusterReq, 0, len(entries))
+		for _, entry := range entries {
+			if entry == nil {
+				entry = &bulkYAMLEntry{}
+			}
+
+			req := &addClusterReq{
+				Host:           entry.Host,
+				User:           entry.User,
+				Password:       entry.Password,
+				Alias:          entry.Alias,
+				RequestTimeout: entry.RequestTimeout,
+			}
+			if entry.CaCert != "" {
+				req.CaCert = []byte(entry.CaCert)
+			}
+
+			reqs = append(reqs, req)
+		}
+
+		return reqs, nil
+	default:
+		var reqs []*addClusterReq
+		if err = json.Unmarshal(body, &reqs); err != nil {
+			return nil, err
+		}
+
+		// null entries are reported as invalid with the rest
+		for i, req := range reqs {
+			if req == nil {
+				reqs[i] = &addClusterReq{}
+			}
+		}
+
+		return reqs, nil
+	}
+}
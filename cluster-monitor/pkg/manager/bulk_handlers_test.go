@@ -0,0 +1,177 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package manager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/couchbase"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/stretchr/testify/require"
+)
+
+func startBulkTestCluster(t *testing.T, uuid string, nodesReturnCode int) *couchbase.TestHandler {
+	handler := &couchbase.TestHandler{
+		ClusterUUID:  uuid,
+		PoolsDefault: couchbase.TestPoolsDefaultData{ClusterName: "c-" + uuid},
+		Nodes: []couchbase.TestNode{
+			{
+				NodeUUID:          "node-0",
+				Hostname:          "127.0.0.1:9000",
+				Services:          []string{"kv"},
+				Version:           "7.0.0-0000-community",
+				Status:            "healthy",
+				ClusterMembership: "active",
+				Ports:             map[string]uint16{"httpsMgmt": 19000},
+			},
+		},
+		Buckets:          []couchbase.BucketsEndpointData{},
+		NodesReturnCode:  nodesReturnCode,
+		BucketReturnCode: http.StatusOK,
+	}
+
+	handler.Start(t, false, false)
+	t.Cleanup(handler.Close)
+	return handler
+}
+
+func TestAddClusters(t *testing.T) {
+	mgr, router := createTestRoleManager(t)
+
+	good := startBulkTestCluster(t, "uuid-10", http.StatusOK)
+	badAuth := startBulkTestCluster(t, "uuid-11", http.StatusUnauthorized)
+
+	entries := []map[string]string{
+		{"host": good.URL(), "user": "user", "password": "pass", "alias": "a-10"},
+		{"host": good.URL(), "user": "user", "password": "pass"},
+		{"host": badAuth.URL(), "user": "user", "password": "pass"},
+		{"host": "http://127.0.0.1:1", "user": "user", "password": "pass"},
+		{"host": good.URL(), "password": "pass"},
+		{"host": good.URL(), "user": "user", "password": "pass", "alias": "bad"},
+	}
+
+	addClusters := func(t *testing.T, dryRun bool) []*bulkAddClusterResult {
+		res := doRoleRequest(router, "operator", http.MethodPost,
+			fmt.Sprintf("/api/v1/clusters/bulk?dry_run=%t", dryRun), entries)
+		require.Equal(t, http.StatusOK, res.Code)
+
+		var body bulkAddClustersRes
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+		require.Equal(t, dryRun, body.DryRun)
+		require.Len(t, body.Results, len(entries))
+
+		for i, result := range body.Results {
+			require.Equal(t, entries[i]["host"], result.Host)
+		}
+
+		return body.Results
+	}
+
+	results := func(results []*bulkAddClusterResult) []bulkAddResult {
+		out := make([]bulkAddResult, 0, len(results))
+		for _, result := range results {
+			out = append(out, result.Result)
+		}
+
+		return out
+	}
+
+	t.Run("dry-run", func(t *testing.T) {
+		got := addClusters(t, true)
+		require.Equal(t, []bulkAddResult{bulkReachable, bulkReachable, bulkAuthFailed, bulkUnreachable, bulkInvalid,
+			bulkInvalid}, results(got))
+		require.Equal(t, "uuid-10", got[0].ClusterUUID)
+		require.Equal(t, "c-uuid-10", got[0].ClusterName)
+
+		_, err := mgr.store.GetCluster("uuid-10", false)
+		require.ErrorIs(t, err, values.ErrNotFound)
+	})
+
+	t.Run("add", func(t *testing.T) {
+		got := addClusters(t, false)
+
+		// the two entries for the same cluster are connected to concurrently so either can be the one added
+		require.ElementsMatch(t, []bulkAddResult{bulkAdded, bulkAlreadyExists}, results(got[:2]))
+		require.Equal(t, []bulkAddResult{bulkAuthFailed, bulkUnreachable, bulkInvalid, bulkInvalid},
+			results(got[2:]))
+
+		cluster, err := mgr.store.GetCluster("uuid-10", false)
+		require.NoError(t, err)
+		require.Equal(t, "c-uuid-10", cluster.Name)
+	})
+
+	t.Run("already-exists", func(t *testing.T) {
+		got := addClusters(t, false)
+		require.Equal(t, []bulkAddResult{bulkAlreadyExists, bulkAlreadyExists}, results(got[:2]))
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		manifest := fmt.Sprintf("- host: %s\n  user: user\n  password: pass\n- host: %s\n  user: user\n",
+			good.URL(), good.URL())
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/clusters/bulk?dry_run=true",
+			bytes.NewReader([]byte(manifest)))
+		req.Header.Set("Content-Type", "application/yaml")
+		req.SetBasicAuth("user", "password")
+
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+		require.Equal(t, http.StatusOK, res.Code)
+
+		var body bulkAddClustersRes
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+		require.Equal(t, []bulkAddResult{bulkAlreadyExists, bulkInvalid}, results(body.Results))
+	})
+
+	t.Run("yaml-request-timeout", func(t *testing.T) {
+		other := startBulkTestCluster(t, "uuid-12", http.StatusOK)
+		manifest := fmt.Sprintf("- host: %s\n  user: user\n  password: pass\n  request_timeout: 1m\n"+
+			"- host: %s\n  user: user\n  password: pass\n  request_timeout: soon\n", other.URL(), other.URL())
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/clusters/bulk", bytes.NewReader([]byte(manifest)))
+		req.Header.Set("Content-Type", "application/yaml")
+		req.SetBasicAuth("user", "password")
+
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+		require.Equal(t, http.StatusOK, res.Code)
+
+		var body bulkAddClustersRes
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+		require.Equal(t, []bulkAddResult{bulkAdded, bulkInvalid}, results(body.Results))
+
+		cluster, err := mgr.store.GetCluster("uuid-12", false)
+		require.NoError(t, err)
+		require.Equal(t, time.Minute, cluster.RequestTimeout)
+	})
+
+	t.Run("invalid-request", func(t *testing.T) {
+		for _, path := range []string{"/api/v1/clusters/bulk?dry_run=maybe", "/api/v1/clusters/bulk"} {
+			res := doRoleRequest(router, "operator", http.MethodPost, path, []map[string]string{})
+			require.Equal(t, http.StatusBadRequest, res.Code)
+		}
+
+		res := doRoleRequest(router, "viewer", http.MethodPost, "/api/v1/clusters/bulk", entries)
+		require.Equal(t, http.StatusForbidden, res.Code)
+	})
+
+	t.Run("too-large", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/clusters/bulk",
+			bytes.NewReader(bytes.Repeat([]byte(" "), maxBulkBodySize+1)))
+		req.SetBasicAuth("user", "password")
+
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+		require.Equal(t, http.StatusRequestEntityTooLarge, res.Code)
+	})
+}
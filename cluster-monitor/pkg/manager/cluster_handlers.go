@@ -6,12 +6,14 @@
 package manager
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/couchbase"
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
@@ -33,6 +35,23 @@ func (m *Manager) getClusters(w http.ResponseWriter, _ *http.Request) {
 		return
 	}
 
+	summaries, err := m.getStatusSummaries(nil)
+	if err != nil {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusInternalServerError,
+			Msg:    "could not get clusters status summary",
+			Extras: err.Error(),
+		}, w, nil)
+		return
+	}
+
+	for _, cluster := range clusters {
+		// CE clusters don't run checkers so they don't get a summary
+		if cluster.Enterprise {
+			cluster.StatusSummary = summaries.get(cluster.UUID)
+		}
+	}
+
 	restutil.MarshalAndSend(http.StatusOK, clusters, w, nil)
 }
 
@@ -66,6 +85,17 @@ func (m *Manager) getCluster(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	summaries, err := m.getStatusSummaries(&uuid)
+	if err != nil {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusInternalServerError,
+			Msg:    "could not get cluster status summary",
+			Extras: err.Error(),
+		}, w, nil)
+		return
+	}
+
+	cluster.StatusSummary = summaries.get(uuid)
 	restutil.MarshalAndSend(http.StatusOK, cluster, w, nil)
 }
 
@@ -75,7 +105,18 @@ func (m *Manager) deleteCluster(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := m.store.DeleteCluster(uuid); err != nil {
+	// get the cluster first so the removal is only published for clusters that existed
+	cluster, err := m.store.GetCluster(uuid, false)
+	if err != nil && !errors.Is(err, values.ErrNotFound) {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusInternalServerError,
+			Msg:    "could not retrieve cluster",
+			Extras: err.Error(),
+		}, w, nil)
+		return
+	}
+
+	if err = m.store.DeleteCluster(uuid); err != nil {
 		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
 			Status: http.StatusInternalServerError,
 			Msg:    "could not delete cluster",
@@ -84,6 +125,15 @@ func (m *Manager) deleteCluster(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	m.clients.Invalidate(uuid)
+	if cluster != nil {
+		m.events.Publish(values.Event{
+			Type:        values.ClusterRemovedEvent,
+			ClusterUUID: uuid,
+			ClusterName: cluster.Name,
+			Time:        time.Now().UTC(),
+		})
+	}
 	zap.S().Infow("(Manager) Cluster deleted", "cluster", uuid)
 	restutil.SendJSONResponse(http.StatusOK, []byte{}, w, nil)
 }
@@ -95,119 +145,115 @@ type addClusterReq struct {
 	Alias    string `json:"alias"`
 
 	CaCert []byte `json:"ca_cert"`
+
+	// RequestTimeout is a duration such as "30s" that bounds each request made to the cluster, it is optional.
+	RequestTimeout string `json:"request_timeout"`
 }
 
-func (m *Manager) addNewCluster(w http.ResponseWriter, r *http.Request) {
-	var req addClusterReq
-	if !restutil.DecodeJSONRequestBody(r.Body, &req, w) {
-		return
+// requestTimeout parses the request timeout, zero is returned when none was given.
+func (req *addClusterReq) requestTimeout() (time.Duration, error) {
+	if req.RequestTimeout == "" {
+		return 0, nil
+	}
+
+	timeout, err := time.ParseDuration(req.RequestTimeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid request timeout: %w", err)
+	}
+
+	if timeout <= 0 {
+		return 0, fmt.Errorf("request timeout must be positive")
 	}
 
-	// validate all mandatory fields are provided
+	return timeout, nil
+}
+
+// validate checks that all the mandatory fields are provided and that the alias, certificate and host are valid.
+func (req *addClusterReq) validate() error {
 	if len(req.Host) == 0 {
-		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
-			Status: http.StatusBadRequest,
-			Msg:    "host is required",
-		}, w, nil)
-		return
+		return fmt.Errorf("host is required")
 	}
 
 	if len(req.User) == 0 {
-		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
-			Status: http.StatusBadRequest,
-			Msg:    "user is required",
-		}, w, nil)
-		return
+		return fmt.Errorf("user is required")
 	}
 
 	if len(req.Password) == 0 {
-		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
-			Status: http.StatusBadRequest,
-			Msg:    "password is required",
-		}, w, nil)
-		return
+		return fmt.Errorf("password is required")
 	}
 
 	if len(req.Alias) > 100 {
-		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
-			Status: http.StatusBadRequest,
-			Msg:    "Maximum alias length is 100 characters",
-		}, w, nil)
-		return
+		return fmt.Errorf("maximum alias length is 100 characters")
 	}
 
 	if len(req.Alias) > 0 && !strings.HasPrefix(req.Alias, aliasPrefix) {
-		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
-			Status: http.StatusBadRequest,
-			Msg:    "Aliases must start with " + aliasPrefix,
-		}, w, nil)
-		return
+		return fmt.Errorf("aliases must start with %s", aliasPrefix)
+	}
+
+	// the AppendCertsFromPEM function checks that the bytes are a valid cert
+	if req.CaCert != nil && !x509.NewCertPool().AppendCertsFromPEM(req.CaCert) {
+		return fmt.Errorf("invalid certificate")
+	}
+
+	if _, err := connstr.Parse(req.Host); err != nil {
+		return fmt.Errorf("invalid host: %w", err)
+	}
+
+	if _, err := req.requestTimeout(); err != nil {
+		return err
 	}
 
+	return nil
+}
+
+// connect communicates with the cluster and returns it ready to be stored. The request must have been validated.
+func (req *addClusterReq) connect(ctx context.Context) (*values.CouchbaseCluster, error) {
+	timeout, err := req.requestTimeout()
+	if err != nil {
+		return nil, err
+	}
+
+	cluster := &values.CouchbaseCluster{RequestTimeout: timeout}
+	ctx, cancel := context.WithTimeout(ctx, cluster.GetRequestTimeout())
+	defer cancel()
+
 	// Get the SystemCertPool, continue with an empty pool on error
 	rootCAs, _ := x509.SystemCertPool()
 	if rootCAs == nil {
 		rootCAs = x509.NewCertPool()
 	}
 
-	// the AppendCertsFromPEM function checks that the bytes are a valid cert
 	if req.CaCert != nil {
-		if ok := rootCAs.AppendCertsFromPEM(req.CaCert); !ok {
-			restutil.HandleErrorWithExtras(restutil.ErrorResponse{
-				Status: http.StatusBadRequest,
-				Msg:    "invalid certificate",
-			}, w, nil)
-			return
-		}
+		rootCAs.AppendCertsFromPEM(req.CaCert)
 	}
 
-	// parse host
 	hosts, err := connstr.Parse(req.Host)
 	if err != nil {
-		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
-			Status: http.StatusBadRequest,
-			Msg:    "invalid host",
-			Extras: err.Error(),
-		}, w, nil)
-		return
+		return nil, fmt.Errorf("invalid host: %w", err)
 	}
 
 	resolvedHosts, err := hosts.Resolve()
 	if err != nil {
-		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
-			Status: http.StatusInternalServerError,
-			Msg:    "could not resolve hosts",
-			Extras: err.Error(),
-		}, w, nil)
-		return
+		return nil, fmt.Errorf("could not resolve hosts: %w", err)
 	}
 
 	// create client to communicate with cluster
 	// skip cacert verify if none given
-	client, err := couchbase.NewClient(resolveAddressesToSlice(resolvedHosts), req.User, req.Password,
+	client, err := couchbase.NewClient(ctx, resolveAddressesToSlice(resolvedHosts), req.User, req.Password,
 		&tls.Config{InsecureSkipVerify: req.CaCert == nil, RootCAs: rootCAs}, false)
 	if err != nil {
-		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
-			Status: http.StatusInternalServerError,
-			Msg:    "could not establish connection with remote cluster",
-			Extras: err.Error(),
-		}, w, nil)
-		return
+		return nil, err
 	}
+	defer client.Close()
 
 	// if the client was created then we could communicate with the cluster and got the UUID as well as the nodes so we
 	// also want to get the buckets summary at the start
-	buckets, err := client.GetBucketsSummary()
+	buckets, err := client.GetBucketsSummary(ctx)
 	if err != nil {
-		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
-			Status: http.StatusInternalServerError,
-			Msg:    "could not get bucket summary from cluster",
-			Extras: err.Error(),
-		}, w, nil)
-		return
+		return nil, fmt.Errorf("could not get bucket summary from cluster: %w", err)
 	}
 
-	cluster := &values.CouchbaseCluster{
+	return &values.CouchbaseCluster{
 		UUID:           client.ClusterInfo.ClusterUUID,
 		Enterprise:     client.GetClusterInfo().Enterprise,
 		Name:           client.ClusterInfo.ClusterName,
@@ -219,6 +265,32 @@ func (m *Manager) addNewCluster(w http.ResponseWriter, r *http.Request) {
 		CaCert:         req.CaCert,
 		BucketsSummary: buckets,
 		Alias:          req.Alias,
+		RequestTimeout: timeout,
+	}, nil
+}
+
+func (m *Manager) addNewCluster(w http.ResponseWriter, r *http.Request) {
+	var req addClusterReq
+	if !restutil.DecodeJSONRequestBody(r.Body, &req, w) {
+		return
+	}
+
+	if err := req.validate(); err != nil {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusBadRequest,
+			Msg:    err.Error(),
+		}, w, nil)
+		return
+	}
+
+	cluster, err := req.connect(r.Context())
+	if err != nil {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusInternalServerError,
+			Msg:    "could not establish connection with remote cluster",
+			Extras: err.Error(),
+		}, w, nil)
+		return
 	}
 
 	if err = m.store.AddCluster(cluster); err != nil {
@@ -230,13 +302,21 @@ func (m *Manager) addNewCluster(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	zap.S().Infow("(Manager) Cluster added", "cluster", client.ClusterInfo.ClusterUUID)
+	m.events.Publish(values.Event{
+		Type:        values.ClusterAddedEvent,
+		ClusterUUID: cluster.UUID,
+		ClusterName: cluster.Name,
+		Time:        time.Now().UTC(),
+	})
+	zap.S().Infow("(Manager) Cluster added", "cluster", cluster.UUID)
 	restutil.SendJSONResponse(http.StatusOK, []byte{}, w, nil)
 
 	// CE clusters don't run checkers so skip triggering API check
 	if !cluster.Enterprise {
 		return
 	}
+
+	go m.checkCluster(cluster)
 }
 
 func (m *Manager) updateClusterInfo(w http.ResponseWriter, r *http.Request) {
@@ -252,11 +332,20 @@ func (m *Manager) updateClusterInfo(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// TODO: add max length constraints to the user and password
-	// the request must have at least one of host, user, password or cacert
-	if req.CaCert == nil && req.User == "" && req.Password == "" && req.Host == "" {
+	// the request must have at least one of host, user, password, cacert or request timeout
+	if req.CaCert == nil && req.User == "" && req.Password == "" && req.Host == "" && req.RequestTimeout == "" {
 		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
 			Status: http.StatusBadRequest,
-			Msg:    "at least one of [host, user, password, cacert] is required",
+			Msg:    "at least one of [host, user, password, cacert, request_timeout] is required",
+		}, w, nil)
+		return
+	}
+
+	requestTimeout, err := req.requestTimeout()
+	if err != nil {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusBadRequest,
+			Msg:    err.Error(),
 		}, w, nil)
 		return
 	}
@@ -341,8 +430,16 @@ func (m *Manager) updateClusterInfo(w http.ResponseWriter, r *http.Request) {
 		rootCAs.AppendCertsFromPEM(cluster.CaCert)
 	}
 
+	timeout := cluster.GetRequestTimeout()
+	if requestTimeout != 0 {
+		timeout = requestTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
 	// confirm we can communicate with the cluster with the new information
-	client, err := couchbase.NewClient(hosts, user, password, &tls.Config{
+	client, err := couchbase.NewClient(ctx, hosts, user, password, &tls.Config{
 		InsecureSkipVerify: !useCert,
 		RootCAs:            rootCAs,
 	}, false)
@@ -354,6 +451,7 @@ func (m *Manager) updateClusterInfo(w http.ResponseWriter, r *http.Request) {
 		}, w, nil)
 		return
 	}
+	defer client.Close()
 
 	// check that the cluster is still the same cluster, we can do this by checking the cluster UUID
 	if client.ClusterInfo.ClusterUUID != cluster.UUID {
@@ -366,16 +464,17 @@ func (m *Manager) updateClusterInfo(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// once all check pass do the update
-	err = m.store.UpdateCluster(&values.CouchbaseCluster{
-		UUID:         cluster.UUID,
-		Name:         client.ClusterInfo.ClusterName,
-		NodesSummary: client.ClusterInfo.NodesSummary,
-		Enterprise:   client.GetClusterInfo().Enterprise,
-		User:         req.User,
-		Password:     req.Password,
-		CaCert:       req.CaCert,
-	})
-	if err != nil {
+	update := &values.CouchbaseCluster{
+		UUID:           cluster.UUID,
+		Name:           client.ClusterInfo.ClusterName,
+		NodesSummary:   client.ClusterInfo.NodesSummary,
+		Enterprise:     client.GetClusterInfo().Enterprise,
+		User:           req.User,
+		Password:       req.Password,
+		CaCert:         req.CaCert,
+		RequestTimeout: requestTimeout,
+	}
+	if err = m.store.UpdateCluster(update); err != nil {
 		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
 			Status: http.StatusInternalServerError,
 			Msg:    "cluster update failed",
@@ -384,6 +483,9 @@ func (m *Manager) updateClusterInfo(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// the cached clients use the old connection details
+	m.clients.Invalidate(cluster.UUID)
+	m.events.Publish(values.ClusterChangeEvents(cluster, update, time.Now().UTC())...)
 	zap.S().Infow("(Manager) Cluster updated", "cluster", client.ClusterInfo.ClusterUUID)
 	restutil.SendJSONResponse(http.StatusOK, []byte{}, w, nil)
 }
@@ -50,6 +50,7 @@ func createTestManager(t *testing.T) *Manager {
 		EnableAdminAPI:    true,
 		EnableClusterAPI:  true,
 		EnableExtendedAPI: true,
+		CheckerThresholds: configuration.DefaultCheckerThresholds,
 	})
 	require.NoError(t, err)
 
@@ -299,6 +300,9 @@ func TestGetCluster(t *testing.T) {
 
 	loadTestData(t, mgr.store)
 
+	sub := mgr.events.Subscribe()
+	defer sub.Close()
+
 	time.Sleep(100 * time.Millisecond)
 
 	baseURL := fmt.Sprintf("http://127.0.0.1:%d/api/v1/clusters/", mgr.config.HTTPPort)
@@ -447,6 +451,9 @@ func TestDeleteCluster(t *testing.T) {
 
 	loadTestData(t, mgr.store)
 
+	sub := mgr.events.Subscribe()
+	defer sub.Close()
+
 	time.Sleep(100 * time.Millisecond)
 
 	baseURL := fmt.Sprintf("http://127.0.0.1:%d/api/v1/clusters/", mgr.config.HTTPPort)
@@ -465,6 +472,7 @@ func TestDeleteCluster(t *testing.T) {
 		clusters, err := mgr.store.GetClusters(false, false)
 		require.NoError(t, err)
 		require.Len(t, clusters, 3)
+		require.Len(t, sub.Events, 0)
 	})
 
 	t.Run("delete", func(t *testing.T) {
@@ -484,6 +492,11 @@ func TestDeleteCluster(t *testing.T) {
 		require.Len(t, clusters, 2)
 		require.Equal(t, "uuid-0", clusters[0].UUID)
 
+		require.Len(t, sub.Events, 1)
+		event := <-sub.Events
+		require.Equal(t, values.ClusterRemovedEvent, event.Type)
+		require.Equal(t, "uuid-1", event.ClusterUUID)
+
 		// confirm no results for deleted cluster
 		results, err := mgr.store.GetCheckerResult(values.CheckerSearch{})
 		require.NoError(t, err)
@@ -719,6 +732,12 @@ func TestAddNewCluster(t *testing.T) {
 			requestBody:    []byte(`{"password":"pass","host":"file:///default.com:8091:40"}`),
 			expectedStatus: http.StatusBadRequest,
 		},
+		{
+			name: "invalidRequestTimeout",
+			requestBody: []byte(fmt.Sprintf(`{"password":"pass","user":"user","host":"%s","request_timeout":"soon"}`,
+				testHandler.URL())),
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name:           "client401",
 			requestBody:    []byte(fmt.Sprintf(`{"password":"pass","user":"user","host":"%s"}`, testHandler.URL())),
@@ -726,8 +745,9 @@ func TestAddNewCluster(t *testing.T) {
 			clientFail:     true,
 		},
 		{
-			name:           "OK",
-			requestBody:    []byte(fmt.Sprintf(`{"password":"pass","user":"user","host":"%s"}`, testHandler.URL())),
+			name: "OK",
+			requestBody: []byte(fmt.Sprintf(`{"password":"pass","user":"user","host":"%s","request_timeout":"10s"}`,
+				testHandler.URL())),
 			expectedStatus: http.StatusOK,
 			expectedCluster: &values.CouchbaseCluster{
 				UUID:       "uuid-0",
@@ -747,6 +767,7 @@ func TestAddNewCluster(t *testing.T) {
 				BucketsSummary: values.BucketsSummary{},
 				ClusterInfo:    &values.ClusterInfo{},
 				CaCert:         []byte{},
+				RequestTimeout: 10 * time.Second,
 			},
 		},
 		{
@@ -831,6 +852,11 @@ func TestUpdateClusterInfo(t *testing.T) {
 			requestBody:    []byte(`{"password":"pass","host":"file:///default.com:8091:40"}`),
 			expectedStatus: http.StatusBadRequest,
 		},
+		{
+			name:           "invalidRequestTimeout",
+			requestBody:    []byte(`{"request_timeout":"-1s"}`),
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name:           "client401",
 			requestBody:    []byte(fmt.Sprintf(`{"password":"pass","user":"user","host":"%s"}`, testHandler.URL())),
@@ -851,8 +877,9 @@ func TestUpdateClusterInfo(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 		},
 		{
-			name:           "OK",
-			requestBody:    []byte(fmt.Sprintf(`{"password":"pass1","user":"user1","host":"%s"}`, testHandler.URL())),
+			name: "OK",
+			requestBody: []byte(fmt.Sprintf(`{"password":"pass1","user":"user1","host":"%s","request_timeout":"20s"}`,
+				testHandler.URL())),
 			expectedStatus: http.StatusOK,
 			expectedCluster: &values.CouchbaseCluster{
 				UUID:       "uuid-0",
@@ -871,7 +898,8 @@ func TestUpdateClusterInfo(t *testing.T) {
 						Version:           "7.0.0-0000-enterprise",
 					},
 				},
-				CaCert: []byte{},
+				CaCert:         []byte{},
+				RequestTimeout: 20 * time.Second,
 			},
 		},
 	}
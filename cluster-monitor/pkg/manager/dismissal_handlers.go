@@ -0,0 +1,183 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/couchbase/tools-common/restutil"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+type dismissalRequest struct {
+	// Level is required, as defaulting to all would silence the checker in every cluster.
+	Level       *values.DismissLevel `json:"level"`
+	CheckerName string               `json:"checker_name"`
+	ClusterUUID string               `json:"cluster_uuid"`
+	BucketName  string               `json:"bucket_name"`
+	NodeUUID    string               `json:"node_uuid"`
+	LogFile     string               `json:"log_file"`
+	Forever     bool                 `json:"forever"`
+	// DismissFor is a duration such as 24h, it is required unless Forever is set.
+	DismissFor string `json:"dismiss_for"`
+}
+
+func (m *Manager) addDismissal(w http.ResponseWriter, r *http.Request) {
+	var body dismissalRequest
+	if !restutil.DecodeJSONRequestBody(r.Body, &body, w) {
+		return
+	}
+
+	if body.Level == nil {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusBadRequest,
+			Msg:    "level is required",
+		}, w, nil)
+		return
+	}
+
+	if _, ok := values.AllCheckerDefs[body.CheckerName]; !ok {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusBadRequest,
+			Msg:    fmt.Sprintf("unknown checker '%s'", body.CheckerName),
+		}, w, nil)
+		return
+	}
+
+	dismissal := values.Dismissal{
+		ID:          uuid.New().String(),
+		Level:       *body.Level,
+		CheckerName: body.CheckerName,
+		BucketName:  body.BucketName,
+		NodeUUID:    body.NodeUUID,
+		LogFile:     body.LogFile,
+		Forever:     body.Forever,
+	}
+
+	if !body.Forever {
+		dismissFor, err := time.ParseDuration(body.DismissFor)
+		if err != nil || dismissFor <= 0 {
+			restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+				Status: http.StatusBadRequest,
+				Msg:    "dismiss_for must be a positive duration unless forever is set",
+			}, w, nil)
+			return
+		}
+
+		dismissal.Until = time.Now().UTC().Add(dismissFor)
+	}
+
+	if body.ClusterUUID != "" {
+		clusterUUID, ok := m.convertAliasToUUID(body.ClusterUUID, w)
+		if !ok {
+			return
+		}
+
+		if _, err := m.store.GetCluster(clusterUUID, false); err != nil {
+			if errors.Is(err, values.ErrNotFound) {
+				restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+					Status: http.StatusNotFound,
+					Msg:    fmt.Sprintf("cluster with UUID '%s' not found", clusterUUID),
+				}, w, nil)
+				return
+			}
+
+			restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+				Status: http.StatusInternalServerError,
+				Msg:    "could not get cluster details",
+				Extras: err.Error(),
+			}, w, nil)
+			return
+		}
+
+		dismissal.ClusterUUID = clusterUUID
+	}
+
+	if err := dismissal.Validate(); err != nil {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusBadRequest,
+			Msg:    "invalid dismissal",
+			Extras: err.Error(),
+		}, w, nil)
+		return
+	}
+
+	if err := m.store.AddDismissal(dismissal); err != nil {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusInternalServerError,
+			Msg:    "could not add dismissal",
+			Extras: err.Error(),
+		}, w, nil)
+		return
+	}
+
+	zap.S().Infow("(Manager) Added dismissal", "id", dismissal.ID, "checker", dismissal.CheckerName,
+		"level", dismissal.Level, "cluster", dismissal.ClusterUUID)
+	restutil.MarshalAndSend(http.StatusOK, map[string]string{"id": dismissal.ID}, w, nil)
+}
+
+// getDismissals returns the active dismissals. They can be filtered using the cluster and checker query parameters,
+// filtering by cluster also returns the global dismissals as they apply to it.
+func (m *Manager) getDismissals(w http.ResponseWriter, r *http.Request) {
+	var search values.DismissalSearchSpace
+	query := r.URL.Query()
+
+	if cluster := query.Get("cluster"); cluster != "" {
+		clusterUUID, ok := m.convertAliasToUUID(cluster, w)
+		if !ok {
+			return
+		}
+
+		search.ClusterUUID = &clusterUUID
+	}
+
+	if checker := query.Get("checker"); checker != "" {
+		search.CheckerName = &checker
+	}
+
+	dismissals, err := m.store.GetDismissals(search)
+	if err != nil {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusInternalServerError,
+			Msg:    "could not get dismissals",
+			Extras: err.Error(),
+		}, w, nil)
+		return
+	}
+
+	restutil.MarshalAndSend(http.StatusOK, dismissals, w, nil)
+}
+
+func (m *Manager) deleteDismissal(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	if err := m.store.DeleteDismissal(id); err != nil {
+		if errors.Is(err, values.ErrNotFound) {
+			restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+				Status: http.StatusNotFound,
+				Msg:    fmt.Sprintf("dismissal '%s' not found", id),
+			}, w, nil)
+			return
+		}
+
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusInternalServerError,
+			Msg:    "could not delete dismissal",
+			Extras: err.Error(),
+		}, w, nil)
+		return
+	}
+
+	zap.S().Infow("(Manager) Deleted dismissal", "id", id)
+	restutil.SendJSONResponse(http.StatusOK, []byte{}, w, nil)
+}
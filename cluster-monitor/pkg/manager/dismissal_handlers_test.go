@@ -0,0 +1,220 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package manager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddDismissal(t *testing.T) {
+	mgr := createTestManager(t)
+	loadTestData(t, mgr.store)
+
+	mgr.setupKeys()
+	mgr.startRESTServers()
+	defer mgr.stopRESTServers()
+
+	time.Sleep(100 * time.Millisecond)
+
+	type testCase struct {
+		name           string
+		body           string
+		expectedStatus int
+		expected       *values.Dismissal
+	}
+
+	cases := []testCase{
+		{
+			name:           "unknownChecker",
+			body:           `{"level":"all","checker_name":"checker-0","forever":true}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknownLevel",
+			body:           `{"level":"rack","checker_name":"mixedMode","forever":true}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missingLevel",
+			body:           `{"checker_name":"mixedMode","cluster_uuid":"uuid-0","dismiss_for":"1h"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "globalWithCluster",
+			body:           `{"level":"all","checker_name":"mixedMode","cluster_uuid":"uuid-0","forever":true}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "noDuration",
+			body:           `{"level":"all","checker_name":"mixedMode"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missingBucket",
+			body:           `{"level":"bucket","checker_name":"residentRatio","cluster_uuid":"uuid-0","forever":true}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "clusterNotFound",
+			body:           `{"level":"cluster","checker_name":"mixedMode","cluster_uuid":"uuid-9","forever":true}`,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "global",
+			body:           `{"level":"all","checker_name":"mixedMode","forever":true}`,
+			expectedStatus: http.StatusOK,
+			expected: &values.Dismissal{
+				Level:       values.AllDismissLevel,
+				CheckerName: values.CheckMixedMode,
+				Forever:     true,
+			},
+		},
+		{
+			name: "nodeWithAlias",
+			body: `{"level":"node","checker_name":"unhealthyNode","cluster_uuid":"a-0","node_uuid":"Node-0",
+				"dismiss_for":"24h"}`,
+			expectedStatus: http.StatusOK,
+			expected: &values.Dismissal{
+				Level:       values.NodeDismissLevel,
+				CheckerName: values.CheckUnhealthyNode,
+				ClusterUUID: "uuid-0",
+				NodeUUID:    "Node-0",
+			},
+		},
+	}
+
+	url := fmt.Sprintf("http://127.0.0.1:%d/api/v1/dismissals", mgr.config.HTTPPort)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader([]byte(tc.body)))
+			require.NoError(t, err)
+
+			req.SetBasicAuth("user", "password")
+
+			res, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+
+			require.Equal(t, tc.expectedStatus, res.StatusCode)
+			if tc.expected == nil {
+				return
+			}
+
+			var out struct {
+				ID string `json:"id"`
+			}
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&out))
+
+			dismissals, err := mgr.store.GetDismissals(values.DismissalSearchSpace{ID: &out.ID})
+			require.NoError(t, err)
+			require.Len(t, dismissals, 1)
+
+			got := dismissals[0]
+			if !tc.expected.Forever {
+				require.WithinDuration(t, time.Now().Add(24*time.Hour), got.Until, time.Minute)
+				got.Until = time.Time{}
+			}
+
+			tc.expected.ID = out.ID
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestGetDismissals(t *testing.T) {
+	mgr := createTestManager(t)
+	loadTestData(t, mgr.store)
+
+	require.NoError(t, mgr.store.AddDismissal(values.Dismissal{
+		ID:          "dismissal-0",
+		Level:       values.AllDismissLevel,
+		CheckerName: "checker-1",
+		Until:       time.Now().Add(time.Hour),
+	}))
+
+	mgr.setupKeys()
+	mgr.startRESTServers()
+	defer mgr.stopRESTServers()
+
+	time.Sleep(100 * time.Millisecond)
+
+	type testCase struct {
+		name        string
+		query       string
+		expectedIDs []string
+	}
+
+	cases := []testCase{
+		{name: "all", expectedIDs: []string{"dismissal-0", "dismissal-1"}},
+		{name: "cluster", query: "?cluster=uuid-0", expectedIDs: []string{"dismissal-0"}},
+		{name: "checker", query: "?checker=checker-0", expectedIDs: []string{"dismissal-1"}},
+		{name: "none", query: "?cluster=uuid-0&checker=checker-0", expectedIDs: []string{}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet,
+				fmt.Sprintf("http://127.0.0.1:%d/api/v1/dismissals%s", mgr.config.HTTPPort, tc.query), nil)
+			require.NoError(t, err)
+
+			req.SetBasicAuth("user", "password")
+
+			res, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+
+			require.Equal(t, http.StatusOK, res.StatusCode)
+
+			var dismissals []*values.Dismissal
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&dismissals))
+
+			ids := make([]string, 0, len(dismissals))
+			for _, dismissal := range dismissals {
+				ids = append(ids, dismissal.ID)
+			}
+
+			require.Equal(t, tc.expectedIDs, ids)
+		})
+	}
+}
+
+func TestDeleteDismissal(t *testing.T) {
+	mgr := createTestManager(t)
+	loadTestData(t, mgr.store)
+
+	mgr.setupKeys()
+	mgr.startRESTServers()
+	defer mgr.stopRESTServers()
+
+	time.Sleep(100 * time.Millisecond)
+
+	url := fmt.Sprintf("http://127.0.0.1:%d/api/v1/dismissals/dismissal-1", mgr.config.HTTPPort)
+	for _, expectedStatus := range []int{http.StatusOK, http.StatusNotFound} {
+		req, err := http.NewRequest(http.MethodDelete, url, nil)
+		require.NoError(t, err)
+
+		req.SetBasicAuth("user", "password")
+
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = res.Body.Close()
+
+		require.Equal(t, expectedStatus, res.StatusCode)
+	}
+
+	dismissals, err := mgr.store.GetDismissals(values.DismissalSearchSpace{})
+	require.NoError(t, err)
+	require.Empty(t, dismissals)
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/couchbase/tools-common/restutil"
+	"go.uber.org/zap"
+)
+
+// eventKeepAliveInterval is how often a comment is sent on idle event streams so proxies do not close them.
+const eventKeepAliveInterval = 30 * time.Second
+
+// streamEvents sends the cluster events as Server-Sent Events until the client disconnects. The events can be limited
+// to some clusters by giving their UUIDs or aliases in the cluster query parameter, which can be repeated.
+func (m *Manager) streamEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusInternalServerError,
+			Msg:    "streaming is not supported",
+		}, w, nil)
+		return
+	}
+
+	var clusters []string
+	for _, cluster := range r.URL.Query()["cluster"] {
+		uuid, ok := m.convertAliasToUUID(cluster, w)
+		if !ok {
+			return
+		}
+
+		clusters = append(clusters, uuid)
+	}
+
+	sub := m.events.Subscribe(clusters...)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(eventKeepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-sub.Events:
+			if !ok {
+				return
+			}
+
+			data, err := json.Marshal(event)
+			if err != nil {
+				zap.S().Warnw("(Manager) Could not marshal event", "type", event.Type, "err", err)
+				continue
+			}
+
+			if _, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return
+			}
+		}
+
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package manager
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamEvents(t *testing.T) {
+	mgr := createTestManager(t)
+	loadTestData(t, mgr.store)
+
+	mgr.setupKeys()
+	mgr.startRESTServers()
+	defer mgr.stopRESTServers()
+
+	time.Sleep(100 * time.Millisecond)
+
+	baseURL := fmt.Sprintf("http://127.0.0.1:%d/api/v1/events", mgr.config.HTTPPort)
+
+	t.Run("aliasNotFound", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, baseURL+"?cluster=a-9", nil)
+		require.NoError(t, err)
+
+		req.SetBasicAuth("user", "password")
+
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("filterByAlias", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, baseURL+"?cluster=a-0", nil)
+		require.NoError(t, err)
+
+		req.SetBasicAuth("user", "password")
+
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+		mgr.events.Publish(
+			values.Event{Type: values.BucketAddedEvent, ClusterUUID: "uuid-1", Bucket: "other"},
+			values.Event{Type: values.BucketAddedEvent, ClusterUUID: "uuid-0", Bucket: "default"},
+		)
+
+		reader := bufio.NewReader(res.Body)
+		eventLine, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		require.Equal(t, "event: bucket_added\n", eventLine)
+
+		dataLine, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(dataLine, "data: "))
+
+		var event values.Event
+		require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(dataLine, "data: ")), &event))
+		require.Equal(t, "uuid-0", event.ClusterUUID)
+		require.Equal(t, "default", event.Bucket)
+	})
+}
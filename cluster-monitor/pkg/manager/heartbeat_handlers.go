@@ -0,0 +1,115 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/couchbase/tools-common/restutil"
+	"github.com/gorilla/mux"
+)
+
+const defaultHeartbeatWindow = 24 * time.Hour
+
+type heartbeatTimeline struct {
+	ClusterUUID string    `json:"cluster_uuid"`
+	Since       time.Time `json:"since"`
+	Until       time.Time `json:"until"`
+	// Availability is the percentage of the known time the cluster had no heartbeat issues. It is not set if there is
+	// no history for the period.
+	Availability *float64                  `json:"availability,omitempty"`
+	Heartbeats   []*values.HeartbeatRecord `json:"heartbeats"`
+}
+
+// getHeartbeats returns the heartbeat history of the cluster since the time given by the since query parameter.
+func (m *Manager) getHeartbeats(w http.ResponseWriter, r *http.Request) {
+	uuid, ok := m.convertAliasToUUID(mux.Vars(r)["uuid"], w)
+	if !ok {
+		return
+	}
+
+	until := time.Now().UTC()
+	since, err := parseSince(r.URL.Query().Get("since"), until)
+	if err != nil {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusBadRequest,
+			Msg:    "invalid since query parameter",
+			Extras: err.Error(),
+		}, w, nil)
+		return
+	}
+
+	if _, err = m.store.GetCluster(uuid, false); err != nil {
+		if errors.Is(err, values.ErrNotFound) {
+			restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+				Status: http.StatusNotFound,
+				Msg:    fmt.Sprintf("cluster with UUID '%s' not found", uuid),
+			}, w, nil)
+			return
+		}
+
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusInternalServerError,
+			Msg:    "could not get cluster details",
+			Extras: err.Error(),
+		}, w, nil)
+		return
+	}
+
+	records, err := m.store.GetHeartbeats(uuid, since)
+	if err != nil {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusInternalServerError,
+			Msg:    "could not get heartbeat history",
+			Extras: err.Error(),
+		}, w, nil)
+		return
+	}
+
+	timeline := &heartbeatTimeline{
+		ClusterUUID: uuid,
+		Since:       since,
+		Until:       until,
+		Heartbeats:  records,
+	}
+
+	if availability, ok := values.Availability(records, since, until); ok {
+		timeline.Availability = &availability
+	}
+
+	restutil.MarshalAndSend(http.StatusOK, timeline, w, nil)
+}
+
+// parseSince parses either an RFC 3339 time or a duration before now. If empty it defaults to 24 hours before now.
+func parseSince(since string, now time.Time) (time.Time, error) {
+	if since == "" {
+		return now.Add(-defaultHeartbeatWindow), nil
+	}
+
+	if duration, err := time.ParseDuration(since); err == nil {
+		if duration <= 0 {
+			return time.Time{}, fmt.Errorf("duration must be positive")
+		}
+
+		return now.Add(-duration), nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("'%s' is not an RFC 3339 time or a duration", since)
+	}
+
+	if parsed.After(now) {
+		return time.Time{}, fmt.Errorf("since cannot be in the future")
+	}
+
+	return parsed.UTC(), nil
+}
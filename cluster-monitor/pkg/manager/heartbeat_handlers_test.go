@@ -0,0 +1,84 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetHeartbeats(t *testing.T) {
+	mgr := createTestManager(t)
+	loadTestData(t, mgr.store)
+
+	now := time.Now().UTC()
+	for _, record := range []*values.HeartbeatRecord{
+		{ClusterUUID: "uuid-0", Issue: values.NoHeartIssue, Time: now.Add(-3 * time.Hour)},
+		{ClusterUUID: "uuid-0", Issue: values.NoConnectionHeartIssue, Error: "refused", Time: now.Add(-2 * time.Hour)},
+		{ClusterUUID: "uuid-0", Issue: values.NoHeartIssue, Time: now.Add(-time.Hour)},
+	} {
+		require.NoError(t, mgr.store.AddHeartbeat(record))
+	}
+
+	mgr.setupKeys()
+	mgr.startRESTServers()
+	defer mgr.stopRESTServers()
+
+	time.Sleep(100 * time.Millisecond)
+
+	type testCase struct {
+		name               string
+		cluster            string
+		query              string
+		expectedStatus     int
+		expectedHeartbeats int
+		expectAvailability bool
+	}
+
+	cases := []testCase{
+		{name: "default", cluster: "uuid-0", expectedStatus: http.StatusOK, expectedHeartbeats: 3,
+			expectAvailability: true},
+		{name: "alias", cluster: "a-0", query: "?since=90m", expectedStatus: http.StatusOK, expectedHeartbeats: 2,
+			expectAvailability: true},
+		{name: "rfc3339", cluster: "uuid-0", query: "?since=" + now.Add(-30*time.Minute).Format(time.RFC3339),
+			expectedStatus: http.StatusOK, expectedHeartbeats: 1, expectAvailability: true},
+		{name: "noHistory", cluster: "uuid-1", expectedStatus: http.StatusOK},
+		{name: "invalidSince", cluster: "uuid-0", query: "?since=yesterday", expectedStatus: http.StatusBadRequest},
+		{name: "negativeSince", cluster: "uuid-0", query: "?since=-1h", expectedStatus: http.StatusBadRequest},
+		{name: "clusterNotFound", cluster: "uuid-9", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://127.0.0.1:%d/api/v1/clusters/%s/heartbeats%s",
+				mgr.config.HTTPPort, tc.cluster, tc.query), nil)
+			require.NoError(t, err)
+
+			req.SetBasicAuth("user", "password")
+
+			res, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+
+			require.Equal(t, tc.expectedStatus, res.StatusCode)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var timeline heartbeatTimeline
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&timeline))
+			require.Len(t, timeline.Heartbeats, tc.expectedHeartbeats)
+			require.Equal(t, tc.expectAvailability, timeline.Availability != nil)
+		})
+	}
+}
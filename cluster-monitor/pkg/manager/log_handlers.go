@@ -14,7 +14,6 @@ import (
 	"net/url"
 	"time"
 
-	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/couchbase"
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
 
 	"github.com/couchbase/tools-common/restutil"
@@ -83,7 +82,7 @@ func (m *Manager) getLogs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	client, err := couchbase.NewClient([]string{nodeHost}, cluster.User, cluster.Password, cluster.GetTLSConfig(), false)
+	client, err := m.clients.GetNodeClient(r.Context(), cluster, nodeHost)
 	if err != nil {
 		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
 			Status: http.StatusInternalServerError,
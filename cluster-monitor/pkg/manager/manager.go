@@ -10,16 +10,24 @@ import (
 	"crypto/rand"
 	"crypto/sha512"
 	"fmt"
+	"net"
 	"net/http"
+	"sync"
 	"time"
 
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/alertmanager"
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/auth"
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/configuration"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/couchbase"
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/discovery"
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/discovery/prometheus"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/events"
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/heart"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/memcached"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/status"
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/storage"
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/storage/sqlite"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -28,11 +36,7 @@ import (
 
 // FrequencyConfiguration is just a convenient grouping of all the frequencies for the different monitors the manger
 // runs.
-type FrequencyConfiguration struct {
-	Heart     time.Duration
-	Status    time.Duration
-	Discovery time.Duration
-}
+type FrequencyConfiguration = values.Frequencies
 
 // DefaultFrequencyConfiguration is the default frequencies used.
 var DefaultFrequencyConfiguration = FrequencyConfiguration{
@@ -41,16 +45,31 @@ var DefaultFrequencyConfiguration = FrequencyConfiguration{
 	Discovery: time.Minute,
 }
 
+// clientIdleTimeout is how long the cluster REST clients are kept without being used, it is longer than the default
+// heartbeat frequency so clients are normally reused between heartbeats.
+const clientIdleTimeout = 15 * time.Minute
+
 // Manager is the struct in charge of running the various monitors as well as the REST endpoints.
 type Manager struct {
 	config *configuration.Config
 
 	store            storage.Store
 	heartMonitor     heart.MonitorIFace
+	statusMonitor    status.MonitorIFace
 	discoveryManager discovery.Manager
+	alertDispatcher  *alertmanager.Dispatcher
+	events           *events.Bus
+	// clients are the cluster REST clients shared by the monitors and the REST handlers.
+	clients *couchbase.ClientCache
+	// newMemcachedClient creates the client for the Data Service nodes, it is only swapped during testing.
+	newMemcachedClient func(ctx context.Context, cluster *values.CouchbaseCluster) (memcached.ConnIFace, error)
 
 	initialized bool
 
+	// frequencies are the frequencies the monitors are currently using.
+	frequencies     FrequencyConfiguration
+	frequenciesLock sync.Mutex
+
 	ctx    context.Context
 	cancel context.CancelFunc
 
@@ -70,11 +89,23 @@ func NewManager(config *configuration.Config) (*Manager, error) {
 		return nil, fmt.Errorf("could not determine state of store: %w", err)
 	}
 
+	bus := events.NewBus()
+	clients := couchbase.NewClientCache(clientIdleTimeout)
 	manager := Manager{
 		config:       config,
 		store:        store,
 		initialized:  initialized,
-		heartMonitor: heart.NewMonitor(store, config.MaxWorkers),
+		heartMonitor: heart.NewMonitor(store, config.MaxWorkers, config.HeartbeatHistoryRetention, bus, clients),
+		statusMonitor: status.NewMonitor(store, config.MaxWorkers, config.CheckerThresholds, config.LogCheckLifetime,
+			clients),
+		events:      bus,
+		clients:     clients,
+		frequencies: DefaultFrequencyConfiguration,
+	}
+
+	manager.newMemcachedClient = func(ctx context.Context,
+		cluster *values.CouchbaseCluster) (memcached.ConnIFace, error) {
+		return memcached.NewMemcachedClient(ctx, cluster, clients)
 	}
 
 	if config.AdminPassword != "" {
@@ -95,7 +126,7 @@ func NewManager(config *configuration.Config) (*Manager, error) {
 
 	if config.PrometheusBaseURL != "" && config.PrometheusLabelSelector != nil {
 		// TODO (CMOS-58) make this more generic
-		prom, err := prometheus.NewPrometheusCouchbaseClusterDiscovery(config, store)
+		prom, err := prometheus.NewPrometheusCouchbaseClusterDiscovery(config, store, bus, clients)
 		if err != nil {
 			return nil, fmt.Errorf("could not create Prometheus discovery: %w", err)
 		}
@@ -105,6 +136,11 @@ func NewManager(config *configuration.Config) (*Manager, error) {
 		}
 	}
 
+	if len(config.AlertmanagerURLs) > 0 {
+		manager.alertDispatcher = alertmanager.NewDispatcher(store, config.AlertmanagerURLs,
+			config.AlertmanagerResendDelay)
+	}
+
 	return &manager, nil
 }
 
@@ -114,6 +150,18 @@ func (m *Manager) Start(config FrequencyConfiguration) {
 		return
 	}
 
+	// the frequencies changed through the REST API take precedence over the ones given at start up
+	stored, err := m.store.GetFrequencies()
+	if err != nil {
+		zap.S().Warnw("(Manager) Could not get stored frequencies, using the given ones", "err", err)
+	} else {
+		config = stored.Merge(config)
+	}
+
+	m.frequenciesLock.Lock()
+	m.frequencies = config
+	m.frequenciesLock.Unlock()
+
 	zap.S().Infow("(Manager) Starting", "frequencies", config)
 	m.ctx, m.cancel = context.WithCancel(context.Background())
 
@@ -121,10 +169,15 @@ func (m *Manager) Start(config FrequencyConfiguration) {
 
 	m.startRESTServers()
 	m.heartMonitor.Start(config.Heart)
+	m.statusMonitor.Start(config.Status)
 	if m.discoveryManager != nil {
 		m.discoveryManager.Start(config.Discovery)
 	}
 
+	if m.alertDispatcher != nil {
+		m.alertDispatcher.Start()
+	}
+
 	zap.S().Info("(Manager) Started")
 	<-m.ctx.Done()
 	zap.S().Info("(Manger) Stopped")
@@ -138,11 +191,17 @@ func (m *Manager) Stop() {
 
 	zap.S().Info("(Manger) Stopping")
 	m.heartMonitor.Stop()
+	m.statusMonitor.Stop()
 	if m.discoveryManager != nil {
 		m.discoveryManager.Stop()
 	}
 
+	if m.alertDispatcher != nil {
+		m.alertDispatcher.Stop()
+	}
+
 	m.stopRESTServers()
+	m.clients.Close()
 
 	m.cancel()
 	m.ctx, m.cancel = nil, nil
@@ -150,6 +209,9 @@ func (m *Manager) Stop() {
 
 func (m *Manager) stopRESTServers() {
 	zap.S().Infow("(Manager) Stopping REST servers")
+	// event streams never finish on their own so they have to be closed for the servers to shut down
+	m.events.CloseSubscriptions()
+
 	if m.httpServer != nil {
 		_ = m.httpServer.Shutdown(context.Background())
 	}
@@ -161,35 +223,40 @@ func (m *Manager) stopRESTServers() {
 	m.httpServer, m.httpsServer = nil, nil
 }
 
+// startRESTServers starts listening before returning so the servers can be used, and shut down, straight away.
 func (m *Manager) startRESTServers() {
 	r := NewRouter(m)
 
 	if !m.config.DisableHTTP {
-		go func() {
-			m.httpServer = &http.Server{
-				Addr:    fmt.Sprintf(":%d", m.config.HTTPPort),
-				Handler: r,
-			}
+		m.httpServer = &http.Server{Handler: r}
 
-			zap.S().Infow("(Manager) (HTTP) Starting HTTP server", "port", m.config.HTTPPort)
-			if err := m.httpServer.ListenAndServe(); err != nil {
-				zap.S().Warnw("(Manager) (HTTP) Server stopped", "err", err)
-			}
-		}()
+		zap.S().Infow("(Manager) (HTTP) Starting HTTP server", "port", m.config.HTTPPort)
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", m.config.HTTPPort))
+		if err != nil {
+			zap.S().Warnw("(Manager) (HTTP) Server stopped", "err", err)
+		} else {
+			go func(server *http.Server) {
+				if err := server.Serve(listener); err != nil {
+					zap.S().Warnw("(Manager) (HTTP) Server stopped", "err", err)
+				}
+			}(m.httpServer)
+		}
 	}
 
 	if !m.config.DisableHTTPS {
-		go func() {
-			m.httpsServer = &http.Server{
-				Addr:    fmt.Sprintf(":%d", m.config.HTTPSPort),
-				Handler: r,
-			}
+		m.httpsServer = &http.Server{Handler: r}
 
-			zap.S().Infow("(Manager) (HTTPS) Starting HTTPS server", "port", m.config.HTTPSPort)
-			if err := m.httpsServer.ListenAndServeTLS(m.config.CertPath, m.config.KeyPath); err != nil {
-				zap.S().Warnw("(Manager) (HTTPS) Server stopped", "err", err)
-			}
-		}()
+		zap.S().Infow("(Manager) (HTTPS) Starting HTTPS server", "port", m.config.HTTPSPort)
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", m.config.HTTPSPort))
+		if err != nil {
+			zap.S().Warnw("(Manager) (HTTPS) Server stopped", "err", err)
+		} else {
+			go func(server *http.Server) {
+				if err := server.ServeTLS(listener, m.config.CertPath, m.config.KeyPath); err != nil {
+					zap.S().Warnw("(Manager) (HTTPS) Server stopped", "err", err)
+				}
+			}(m.httpsServer)
+		}
 	}
 }
 
@@ -0,0 +1,178 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package manager
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const metricsNamespace = "cbmultimanager"
+
+var (
+	clusterLabels = []string{"cluster", "cluster_name"}
+	bucketLabels  = append(append([]string(nil), clusterLabels...), "bucket")
+
+	heartbeatIssueDesc = prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "cluster", "heartbeat_issue"),
+		"Whether the last heartbeat of the cluster had the issue, exactly one issue is 1 for each cluster.",
+		append(append([]string(nil), clusterLabels...), "issue"), nil)
+	lastUpdateDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "cluster", "seconds_since_last_update"),
+		"Seconds since the cluster was last updated by the heartbeat monitor.", clusterLabels, nil)
+	nodesDesc = prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "cluster", "nodes"),
+		"Number of nodes in the cluster by status, cluster membership and version.",
+		append(append([]string(nil), clusterLabels...), "status", "membership", "version"), nil)
+
+	ramQuotaDesc = prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "cluster", "ram_quota_bytes"),
+		"Total memory quota of the cluster.", clusterLabels, nil)
+	ramUsedDesc = prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "cluster", "ram_quota_used_bytes"),
+		"Memory quota of the cluster used by the buckets.", clusterLabels, nil)
+	diskTotalDesc = prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "cluster", "disk_total_bytes"),
+		"Total disk space of the cluster.", clusterLabels, nil)
+	diskUsedDesc = prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "cluster", "disk_used_bytes"),
+		"Disk space used on the cluster.", clusterLabels, nil)
+	diskUsedByDataDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "cluster", "disk_used_by_data_bytes"),
+		"Disk space used by Couchbase Server data on the cluster.", clusterLabels, nil)
+
+	bucketQuotaDesc = prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "bucket", "quota_bytes"),
+		"Memory quota of the bucket.", bucketLabels, nil)
+	bucketQuotaUsedDesc = prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "bucket", "quota_used_percent"),
+		"Percentage of the bucket's memory quota used.", bucketLabels, nil)
+	bucketItemsDesc = prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "bucket", "items"),
+		"Number of items in the bucket.", bucketLabels, nil)
+
+	checkerResultsDesc = prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "cluster", "checker_results"),
+		"Number of checker results for the cluster by status, results silenced by a dismissal are only counted as "+
+			"dismissed. Only given for Enterprise Edition clusters as they are the only ones checked.",
+		append(append([]string(nil), clusterLabels...), "status"), nil)
+)
+
+// heartIssues are all the heartbeat issues, so the ones a cluster does not have are exported as 0.
+var heartIssues = []values.HeartIssue{
+	values.NoHeartIssue, values.BadAuthHeartIssue, values.NoConnectionHeartIssue, values.UUIDMismatchHeartIssue,
+	values.TimeoutHeartIssue,
+}
+
+// fleetCollector exports the state of every cluster in the store as Prometheus metrics. The metrics are read from the
+// store on every scrape so they are always as up to date as the monitors keep the store.
+type fleetCollector struct {
+	manager *Manager
+	// now is used to work out how long ago the clusters were updated.
+	now func() time.Time
+}
+
+func newFleetCollector(m *Manager) *fleetCollector {
+	return &fleetCollector{manager: m, now: time.Now}
+}
+
+func (c *fleetCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		heartbeatIssueDesc, lastUpdateDesc, nodesDesc, ramQuotaDesc, ramUsedDesc, diskTotalDesc, diskUsedDesc,
+		diskUsedByDataDesc, bucketQuotaDesc, bucketQuotaUsedDesc, bucketItemsDesc, checkerResultsDesc,
+	} {
+		ch <- desc
+	}
+}
+
+func (c *fleetCollector) Collect(ch chan<- prometheus.Metric) {
+	clusters, err := c.manager.store.GetClusters(false, false)
+	if err != nil {
+		zap.S().Errorw("(Metrics) Could not get clusters", "err", err)
+		ch <- prometheus.NewInvalidMetric(heartbeatIssueDesc, fmt.Errorf("could not get clusters: %w", err))
+		return
+	}
+
+	summaries, err := c.manager.getStatusSummaries(nil)
+	if err != nil {
+		zap.S().Errorw("(Metrics) Could not get checker results", "err", err)
+		ch <- prometheus.NewInvalidMetric(checkerResultsDesc, err)
+		return
+	}
+
+	for _, cluster := range clusters {
+		c.collectCluster(ch, cluster)
+
+		// CE clusters don't run checkers so they don't get a summary
+		if cluster.Enterprise {
+			collectStatusSummary(ch, cluster, summaries.get(cluster.UUID))
+		}
+	}
+}
+
+func (c *fleetCollector) collectCluster(ch chan<- prometheus.Metric, cluster *values.CouchbaseCluster) {
+	labels := []string{cluster.UUID, cluster.Name}
+
+	for _, issue := range heartIssues {
+		var value float64
+		if cluster.HeartBeatIssue == issue {
+			value = 1
+		}
+
+		ch <- prometheus.MustNewConstMetric(heartbeatIssueDesc, prometheus.GaugeValue, value,
+			append(labels, issue.String())...)
+	}
+
+	if !cluster.LastUpdate.IsZero() {
+		ch <- prometheus.MustNewConstMetric(lastUpdateDesc, prometheus.GaugeValue,
+			c.now().Sub(cluster.LastUpdate).Seconds(), labels...)
+	}
+
+	type nodeKey struct {
+		status, membership, version string
+	}
+
+	nodes := make(map[nodeKey]int)
+	for _, node := range cluster.NodesSummary {
+		nodes[nodeKey{status: node.Status, membership: node.ClusterMembership, version: node.Version}]++
+	}
+
+	for key, count := range nodes {
+		ch <- prometheus.MustNewConstMetric(nodesDesc, prometheus.GaugeValue, float64(count),
+			append(labels, key.status, key.membership, key.version)...)
+	}
+
+	if info := cluster.ClusterInfo; info != nil {
+		for desc, value := range map[*prometheus.Desc]uint64{
+			ramQuotaDesc:       info.RAMQuota,
+			ramUsedDesc:        info.RAMUsed,
+			diskTotalDesc:      info.DiskTotal,
+			diskUsedDesc:       info.DiskUsed,
+			diskUsedByDataDesc: info.DiskUsedByData,
+		} {
+			ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(value), labels...)
+		}
+	}
+
+	for _, bucket := range cluster.BucketsSummary {
+		bucketValues := append(append([]string(nil), labels...), bucket.Name)
+		ch <- prometheus.MustNewConstMetric(bucketQuotaDesc, prometheus.GaugeValue, float64(bucket.Quota),
+			bucketValues...)
+		ch <- prometheus.MustNewConstMetric(bucketQuotaUsedDesc, prometheus.GaugeValue, bucket.QuotaUsed,
+			bucketValues...)
+		ch <- prometheus.MustNewConstMetric(bucketItemsDesc, prometheus.GaugeValue, float64(bucket.Items),
+			bucketValues...)
+	}
+}
+
+func collectStatusSummary(ch chan<- prometheus.Metric, cluster *values.CouchbaseCluster,
+	summary *values.ClusterStatusSummary) {
+	for status, count := range map[string]int{
+		string(values.GoodCheckerStatus):  summary.Good,
+		string(values.WarnCheckerStatus):  summary.Warnings,
+		string(values.AlertCheckerStatus): summary.Alerts,
+		string(values.InfoCheckerStatus):  summary.Info,
+		"dismissed":                       summary.Dismissed,
+	} {
+		ch <- prometheus.MustNewConstMetric(checkerResultsDesc, prometheus.GaugeValue, float64(count), cluster.UUID,
+			cluster.Name, status)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package manager
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFleetCollector(t *testing.T) {
+	mgr := createTestManager(t)
+
+	for _, cluster := range []*values.CouchbaseCluster{
+		{
+			UUID:           "uuid-0",
+			Name:           "Cluster-0",
+			Enterprise:     true,
+			HeartBeatIssue: values.NoConnectionHeartIssue,
+			NodesSummary: values.NodesSummary{
+				{NodeUUID: "N0", Version: "7.0.3-7031-enterprise", Status: "healthy", ClusterMembership: "active"},
+				{NodeUUID: "N1", Version: "7.0.3-7031-enterprise", Status: "healthy", ClusterMembership: "active"},
+				{NodeUUID: "N2", Version: "6.6.5-10080-enterprise", Status: "unhealthy", ClusterMembership: "active"},
+			},
+			BucketsSummary: values.BucketsSummary{{Name: "B0", Quota: 1024, QuotaUsed: 12.5, Items: 100}},
+			ClusterInfo:    &values.ClusterInfo{RAMQuota: 4096, RAMUsed: 1024, DiskTotal: 8192, DiskUsed: 2048},
+		},
+		{
+			UUID: "uuid-1",
+			Name: "CE-cluster",
+			NodesSummary: values.NodesSummary{
+				{NodeUUID: "N3", Version: "7.0.2-6703-community", Status: "healthy", ClusterMembership: "active"},
+			},
+		},
+	} {
+		require.NoError(t, mgr.store.AddCluster(cluster))
+	}
+
+	for _, status := range []values.CheckerStatus{values.GoodCheckerStatus, values.AlertCheckerStatus} {
+		require.NoError(t, mgr.store.SetCheckerResult(&values.WrappedCheckerResult{
+			Cluster: "uuid-0",
+			Result:  &values.CheckerResult{Name: "checker-" + string(status), Status: status, Time: time.Now()},
+		}))
+	}
+
+	cluster, err := mgr.store.GetCluster("uuid-0", false)
+	require.NoError(t, err)
+
+	collector := newFleetCollector(mgr)
+	collector.now = func() time.Time { return cluster.LastUpdate.Add(90 * time.Second) }
+
+	expected := `
+# HELP cbmultimanager_bucket_items Number of items in the bucket.
+# TYPE cbmultimanager_bucket_items gauge
+cbmultimanager_bucket_items{bucket="B0",cluster="uuid-0",cluster_name="Cluster-0"} 100
+# HELP cbmultimanager_bucket_quota_used_percent Percentage of the bucket's memory quota used.
+# TYPE cbmultimanager_bucket_quota_used_percent gauge
+cbmultimanager_bucket_quota_used_percent{bucket="B0",cluster="uuid-0",cluster_name="Cluster-0"} 12.5
+# HELP cbmultimanager_cluster_checker_results Number of checker results for the cluster by status, results ` +
+		`silenced by a dismissal are only counted as dismissed. Only given for Enterprise Edition clusters as ` +
+		`they are the only ones checked.
+# TYPE cbmultimanager_cluster_checker_results gauge
+cbmultimanager_cluster_checker_results{cluster="uuid-0",cluster_name="Cluster-0",status="alert"} 1
+cbmultimanager_cluster_checker_results{cluster="uuid-0",cluster_name="Cluster-0",status="dismissed"} 0
+cbmultimanager_cluster_checker_results{cluster="uuid-0",cluster_name="Cluster-0",status="good"} 1
+cbmultimanager_cluster_checker_results{cluster="uuid-0",cluster_name="Cluster-0",status="info"} 0
+cbmultimanager_cluster_checker_results{cluster="uuid-0",cluster_name="Cluster-0",status="warn"} 0
+# HELP cbmultimanager_cluster_heartbeat_issue Whether the last heartbeat of the cluster had the issue, exactly ` +
+		`one issue is 1 for each cluster.
+# TYPE cbmultimanager_cluster_heartbeat_issue gauge
+cbmultimanager_cluster_heartbeat_issue{cluster="uuid-0",cluster_name="Cluster-0",issue="UUID mismatch"} 0
+cbmultimanager_cluster_heartbeat_issue{cluster="uuid-0",cluster_name="Cluster-0",issue="bad authentication"} 0
+cbmultimanager_cluster_heartbeat_issue{cluster="uuid-0",cluster_name="Cluster-0",issue="no connection"} 1
+cbmultimanager_cluster_heartbeat_issue{cluster="uuid-0",cluster_name="Cluster-0",issue="no issue"} 0
+cbmultimanager_cluster_heartbeat_issue{cluster="uuid-0",cluster_name="Cluster-0",issue="timeout"} 0
+cbmultimanager_cluster_heartbeat_issue{cluster="uuid-1",cluster_name="CE-cluster",issue="UUID mismatch"} 0
+cbmultimanager_cluster_heartbeat_issue{cluster="uuid-1",cluster_name="CE-cluster",issue="bad authentication"} 0
+cbmultimanager_cluster_heartbeat_issue{cluster="uuid-1",cluster_name="CE-cluster",issue="no connection"} 0
+cbmultimanager_cluster_heartbeat_issue{cluster="uuid-1",cluster_name="CE-cluster",issue="no issue"} 1
+cbmultimanager_cluster_heartbeat_issue{cluster="uuid-1",cluster_name="CE-cluster",issue="timeout"} 0
+# HELP cbmultimanager_cluster_nodes Number of nodes in the cluster by status, cluster membership and version.
+# TYPE cbmultimanager_cluster_nodes gauge
+cbmultimanager_cluster_nodes{cluster="uuid-0",cluster_name="Cluster-0",membership="active",status="healthy",` +
+		`version="7.0.3-7031-enterprise"} 2
+cbmultimanager_cluster_nodes{cluster="uuid-0",cluster_name="Cluster-0",membership="active",status="unhealthy",` +
+		`version="6.6.5-10080-enterprise"} 1
+cbmultimanager_cluster_nodes{cluster="uuid-1",cluster_name="CE-cluster",membership="active",status="healthy",` +
+		`version="7.0.2-6703-community"} 1
+# HELP cbmultimanager_cluster_ram_quota_bytes Total memory quota of the cluster.
+# TYPE cbmultimanager_cluster_ram_quota_bytes gauge
+cbmultimanager_cluster_ram_quota_bytes{cluster="uuid-0",cluster_name="Cluster-0"} 4096
+`
+
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"cbmultimanager_bucket_items", "cbmultimanager_bucket_quota_used_percent",
+		"cbmultimanager_cluster_checker_results", "cbmultimanager_cluster_heartbeat_issue",
+		"cbmultimanager_cluster_nodes", "cbmultimanager_cluster_ram_quota_bytes"))
+
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, registry.Register(collector))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	sinceUpdate := make(map[string]float64)
+	for _, family := range families {
+		if family.GetName() != "cbmultimanager_cluster_seconds_since_last_update" {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			sinceUpdate[metric.GetLabel()[0].GetValue()] = metric.GetGauge().GetValue()
+		}
+	}
+
+	require.Equal(t, 90.0, sinceUpdate["uuid-0"])
+	require.Contains(t, sinceUpdate, "uuid-1")
+
+	// every metric is valid for the exposition format
+	problems, err := testutil.CollectAndLint(collector)
+	require.NoError(t, err)
+	require.Empty(t, problems)
+}
@@ -6,6 +6,7 @@
 package manager
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"net/http"
@@ -13,6 +14,7 @@ import (
 	"time"
 
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/auth"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
 
 	"github.com/couchbase/tools-common/restutil"
 	"github.com/couchbase/tools-common/slice"
@@ -20,6 +22,11 @@ import (
 	"gopkg.in/square/go-jose.v2/jwt"
 )
 
+type contextKey int
+
+// userContextKey is the request context key for the authenticated user.
+const userContextKey contextKey = iota
+
 var unauthedEndpoints = []string{
 	"/api/v1/self",
 	"/api/v1/self/token",
@@ -27,6 +34,7 @@ var unauthedEndpoints = []string{
 }
 
 // authMiddleware will check the headers for auth information. Current supported systems are Basic and JWT Bearer token.
+// The authenticated user is added to the request context so the routes can check its role.
 func (m *Manager) authMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 		// the /self endpoint is exempt of auth constraints
@@ -42,12 +50,16 @@ func (m *Manager) authMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		var err error
+		var (
+			user *values.User
+			err  error
+		)
+
 		switch authParts[0] {
 		case "Basic":
-			err = m.doBasicAuth(authParts[1])
+			user, err = m.doBasicAuth(authParts[1])
 		case "Bearer":
-			err = m.doBearerJWTAuth(authParts[1])
+			user, err = m.doBearerJWTAuth(authParts[1])
 		default:
 			sendUnauthorized(writer, true)
 			return
@@ -62,71 +74,89 @@ func (m *Manager) authMiddleware(next http.Handler) http.Handler {
 		}
 
 		// otherwise we are good to go
-		next.ServeHTTP(writer, request)
+		next.ServeHTTP(writer, request.WithContext(context.WithValue(request.Context(), userContextKey, user)))
 	})
 }
 
+// requireRole only lets through users with at least the given role. It relies on authMiddleware having added the user
+// to the request context.
+func requireRole(role values.Role, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := r.Context().Value(userContextKey).(*values.User)
+		if !ok || !user.Role.Allows(role) {
+			restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+				Status: http.StatusForbidden,
+				Msg:    fmt.Sprintf("the %s role is required", role),
+			}, w, nil)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
 // doBearerJWTAuth parse and decrypt the token and verify the user.
-func (m *Manager) doBearerJWTAuth(token string) error {
+func (m *Manager) doBearerJWTAuth(token string) (*values.User, error) {
 	tok, err := jwt.ParseSignedAndEncrypted(token)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	nested, err := tok.Decrypt(m.config.EncryptKey)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var claims jwt.Claims
 	if err := nested.Claims(m.config.SignKey, &claims); err != nil {
-		return err
+		return nil, err
 	}
 
 	// check if claim expired
 	if claims.Expiry.Time().Before(time.Now()) {
-		return fmt.Errorf("cliam expired")
+		return nil, fmt.Errorf("cliam expired")
 	}
 
 	// check that claim is active
 	if claims.NotBefore.Time().After(time.Now()) {
-		return fmt.Errorf("claim used before active")
+		return nil, fmt.Errorf("claim used before active")
 	}
 
-	if _, err = m.store.GetUser(claims.Subject); err != nil {
-		return fmt.Errorf("error getting subject '%s': %w", claims.Subject, err)
+	user, err := m.store.GetUser(claims.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("error getting subject '%s': %w", claims.Subject, err)
 	}
 
 	// otherwise assume the subject is correct
-	return nil
+	return user, nil
 }
 
 // doBasicAuth will decode the user and password and verify them against the store.
-func (m *Manager) doBasicAuth(encodedUserPass string) error {
+func (m *Manager) doBasicAuth(encodedUserPass string) (*values.User, error) {
 	// badly encoded
 	userPassString, err := base64.StdEncoding.DecodeString(encodedUserPass)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// to many parts
 	parts := strings.SplitN(string(userPassString), ":", 2)
 	if len(parts) != 2 {
-		return fmt.Errorf("invalid user password string in basic auth")
+		return nil, fmt.Errorf("invalid user password string in basic auth")
 	}
 
 	// if we have basic auth then verify user/password. If this errors then the user does not exist
 	userStruct, err := m.store.GetUser(parts[0])
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// check password
 	if !auth.CheckPassword(parts[1], userStruct.Password) {
-		return fmt.Errorf("invalid credentials")
+		return nil, fmt.Errorf("invalid credentials")
 	}
 
-	return nil
+	return userStruct, nil
 }
 
 // loggingMiddleware logs all requests.
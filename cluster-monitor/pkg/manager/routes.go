@@ -11,12 +11,17 @@ import (
 	"os"
 	"path"
 
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// NewRouter creates the router for all the enabled APIs. Every authenticated route requires a minimum user role:
+// viewers can only read, operators can also manage clusters, aliases and dismissals, and admins can do everything.
 func NewRouter(m *Manager) *mux.Router {
 	r := mux.NewRouter()
 
@@ -24,7 +29,7 @@ func NewRouter(m *Manager) *mux.Router {
 	r.Use(m.authMiddleware)
 	r.Use(loggingMiddleware)
 
-	metricsAPI(r)
+	metricsAPI(r, m)
 
 	if m.config.EnableAdminAPI {
 		adminAPI(r, m)
@@ -56,16 +61,46 @@ func adminAPI(r *mux.Router, m *Manager) {
 	// Create JWT token endpoint.
 	v1.HandleFunc("/self/token", m.tokenLogin).Methods("POST")
 
+	// User management endpoints, only available to admins.
+	// Gets all the users.
+	v1.HandleFunc("/users", requireRole(values.AdminRole, m.getUsers)).Methods("GET")
+	// Adds a new user with the given role.
+	v1.HandleFunc("/users", requireRole(values.AdminRole, m.addUser)).Methods("POST")
+	// Get a single user.
+	v1.HandleFunc("/users/{user}", requireRole(values.AdminRole, m.getUser)).Methods("GET")
+	// Change the password or role of a user.
+	v1.HandleFunc("/users/{user}", requireRole(values.AdminRole, m.updateUser)).Methods("PATCH")
+	// Deletes a user.
+	v1.HandleFunc("/users/{user}", requireRole(values.AdminRole, m.deleteUser)).Methods("DELETE")
+
+	// Monitor settings endpoints, only available to admins.
+	// Gets the monitor frequencies and the per-cluster overrides.
+	v1.HandleFunc("/settings/frequencies", requireRole(values.AdminRole, m.getFrequencies)).Methods("GET")
+	// Changes the monitor frequencies.
+	v1.HandleFunc("/settings/frequencies", requireRole(values.AdminRole, m.setFrequencies)).Methods("PUT")
+	// Overrides the heartbeat and status check frequencies for a cluster.
+	v1.HandleFunc("/settings/frequencies/clusters/{uuid}",
+		requireRole(values.AdminRole, m.setClusterFrequencies)).Methods("PUT")
+	// Removes the frequency overrides for a cluster.
+	v1.HandleFunc("/settings/frequencies/clusters/{uuid}",
+		requireRole(values.AdminRole, m.deleteClusterFrequencies)).Methods("DELETE")
+
 	zap.S().Info("(Routes) Set up Admin API")
 }
 
-func metricsAPI(r *mux.Router) {
+func metricsAPI(r *mux.Router, m *Manager) {
 	v1 := r.PathPrefix("/api/v1").Subrouter()
 
+	// The fleet metrics are kept in their own registry, as the default one is global and the router can be created
+	// more than once, and served together with the process metrics.
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(newFleetCollector(m))
+	handler := promhttp.HandlerFor(prometheus.Gatherers{prometheus.DefaultGatherer, registry}, promhttp.HandlerOpts{})
+
 	// Collects prometheus metrics.
-	v1.Handle("/_prometheus", promhttp.Handler()).Methods("GET")
+	v1.HandleFunc("/_prometheus", requireRole(values.ViewerRole, handler.ServeHTTP)).Methods("GET")
 	// Provide standard endpoint to simplify configuration.
-	v1.Handle("/metrics", promhttp.Handler()).Methods("GET")
+	v1.HandleFunc("/metrics", requireRole(values.ViewerRole, handler.ServeHTTP)).Methods("GET")
 
 	zap.S().Info("(Routes) Set up Metrics API")
 }
@@ -75,16 +110,23 @@ func clusterAPI(r *mux.Router, m *Manager) {
 
 	// Cluster management related endpoints.
 	// Gets all the clusters.
-	v1.HandleFunc("/clusters", m.getClusters).Methods("GET")
+	v1.HandleFunc("/clusters", requireRole(values.ViewerRole, m.getClusters)).Methods("GET")
 	// Adds a new cluster.
-	v1.HandleFunc("/clusters", m.addNewCluster).Methods("POST")
+	v1.HandleFunc("/clusters", requireRole(values.OperatorRole, m.addNewCluster)).Methods("POST")
+	// Adds a list of clusters given as JSON or YAML, reporting the result for each of them. With dry_run=true the
+	// clusters are only connected to.
+	v1.HandleFunc("/clusters/bulk", requireRole(values.OperatorRole, m.addClusters)).Methods("POST")
 
 	// Get only one specific cluster.
-	v1.HandleFunc("/clusters/{uuid}", m.getCluster).Methods("GET")
+	v1.HandleFunc("/clusters/{uuid}", requireRole(values.ViewerRole, m.getCluster)).Methods("GET")
 	// Used to update the user, password, certificate or give a new bootstrap host.
-	v1.HandleFunc("/clusters/{uuid}", m.updateClusterInfo).Methods("PATCH")
+	v1.HandleFunc("/clusters/{uuid}", requireRole(values.OperatorRole, m.updateClusterInfo)).Methods("PATCH")
 	// Stops tracking the cluster.
-	v1.HandleFunc("/clusters/{uuid}", m.deleteCluster).Methods("DELETE")
+	v1.HandleFunc("/clusters/{uuid}", requireRole(values.AdminRole, m.deleteCluster)).Methods("DELETE")
+
+	// Streams the changes to the clusters as Server-Sent Events. They can be limited to some clusters by giving their
+	// UUIDs or aliases in the cluster query parameter.
+	v1.HandleFunc("/events", requireRole(values.ViewerRole, m.streamEvents)).Methods("GET")
 
 	zap.S().Info("(Routes) Set up Cluster Management API")
 }
@@ -92,27 +134,56 @@ func clusterAPI(r *mux.Router, m *Manager) {
 func extendedAPI(r *mux.Router, m *Manager) {
 	v1 := r.PathPrefix("/api/v1").Subrouter()
 
+	// Checker results related endpoints.
+	// Get all the checker results for the cluster grouped by cluster, node and bucket scope. They can be filtered by
+	// status, node, bucket and checker using query parameters.
+	v1.HandleFunc("/clusters/{uuid}/status", requireRole(values.ViewerRole, m.getClusterStatusReport)).Methods("GET")
+	// Get the results for a single checker, including dismissed ones. They can be further filtered by the node
 	// UUID or bucket name using query parameters (bucket, node) respectively.
-	v1.HandleFunc("/clusters/{uuid}/status/{name}", m.getClusterStatusCheckerResult).Methods("GET")
+	v1.HandleFunc("/clusters/{uuid}/status/{name}",
+		requireRole(values.ViewerRole, m.getClusterStatusCheckerResult)).Methods("GET")
+	// Get the heartbeat history of the cluster together with its availability. The since query parameter can be an
+	// RFC 3339 time or a duration before now and defaults to 24 hours.
+	v1.HandleFunc("/clusters/{uuid}/heartbeats", requireRole(values.ViewerRole, m.getHeartbeats)).Methods("GET")
+	// Triggers a heartbeat and status check for the cluster.
+	v1.HandleFunc("/clusters/{uuid}/refresh", requireRole(values.OperatorRole, m.refreshCluster)).Methods("POST")
+	// Get the statistics of every collection in the bucket summed across the data nodes, largest memory usage first.
+	v1.HandleFunc("/clusters/{uuid}/buckets/{bucket}/collections",
+		requireRole(values.ViewerRole, m.getBucketCollections)).Methods("GET")
+
+	// Get the definitions of all the checkers.
+	v1.HandleFunc("/checkers", requireRole(values.ViewerRole, m.getCheckerDefinitions)).Methods("GET")
+	// Get the definition of a single checker.
+	v1.HandleFunc("/checkers/{name}", requireRole(values.ViewerRole, m.getCheckerDefinition)).Methods("GET")
+
+	// Dismissal related endpoints.
+	// Get the active dismissals. They can be filtered by the cluster and checker query parameters.
+	v1.HandleFunc("/dismissals", requireRole(values.ViewerRole, m.getDismissals)).Methods("GET")
+	// Dismiss a checker globally, for a cluster, a bucket, a node or a log file either for a duration or forever.
+	v1.HandleFunc("/dismissals", requireRole(values.OperatorRole, m.addDismissal)).Methods("POST")
+	// Removes a dismissal.
+	v1.HandleFunc("/dismissals/{id}", requireRole(values.OperatorRole, m.deleteDismissal)).Methods("DELETE")
 
 	// Get a single node's details (unblocker for https://issues.couchbase.com/browse/CMOS-188)
-	v1.HandleFunc("/clusters/{uuid}/node/{node_uuid}", m.getClusterNodeDetails).Methods("GET")
+	v1.HandleFunc("/clusters/{uuid}/node/{node_uuid}",
+		requireRole(values.ViewerRole, m.getClusterNodeDetails)).Methods("GET")
 
 	// Endpoints to manage cluster aliases.
 	// Add alias endpoint.
-	v1.HandleFunc("/aliases/{alias}", m.AddAlias).Methods("POST")
+	v1.HandleFunc("/aliases/{alias}", requireRole(values.OperatorRole, m.AddAlias)).Methods("POST")
 	// Delete alias endpoint.
-	v1.HandleFunc("/aliases/{alias}", m.DeleteAlias).Methods("DELETE")
+	v1.HandleFunc("/aliases/{alias}", requireRole(values.OperatorRole, m.DeleteAlias)).Methods("DELETE")
 
 	// Endpoint to retrieve logs from the cluster.
-	v1.HandleFunc("/clusters/{uuid}/nodes/{nodeUUID}/logs/{logName}", m.getLogs).Methods("GET")
+	v1.HandleFunc("/clusters/{uuid}/nodes/{nodeUUID}/logs/{logName}",
+		requireRole(values.OperatorRole, m.getLogs)).Methods("GET")
 
 	// Couchbase Cloud Endpoints
-	v1.HandleFunc("/cloud/credentials", m.listCloudCreds).Methods("GET")
-	v1.HandleFunc("/cloud/credentials", m.addCloudCreds).Methods("POST")
+	v1.HandleFunc("/cloud/credentials", requireRole(values.AdminRole, m.listCloudCreds)).Methods("GET")
+	v1.HandleFunc("/cloud/credentials", requireRole(values.AdminRole, m.addCloudCreds)).Methods("POST")
 
-	v1.HandleFunc("/cloud/clusters", m.getCloudClusters).Methods("GET")
-	v1.HandleFunc("/cloud/clusters/{id}", m.getCloudClusterStatus).Methods("GET")
+	v1.HandleFunc("/cloud/clusters", requireRole(values.ViewerRole, m.getCloudClusters)).Methods("GET")
+	v1.HandleFunc("/cloud/clusters/{id}", requireRole(values.ViewerRole, m.getCloudClusterStatus)).Methods("GET")
 
 	zap.S().Info("(Routes) Set up Extended API")
 }
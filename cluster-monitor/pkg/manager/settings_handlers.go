@@ -0,0 +1,279 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/couchbase/tools-common/restutil"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// minFrequency is the shortest frequency that can be set for any of the monitors.
+const minFrequency = time.Second
+
+// frequencySettings are the frequencies as given and returned by the REST API. The frequencies are durations such as
+// "30s" or "5m", empty values are left unchanged when updating.
+type frequencySettings struct {
+	Heart     string `json:"heart,omitempty"`
+	Status    string `json:"status,omitempty"`
+	Discovery string `json:"discovery,omitempty"`
+
+	// Clusters are the frequencies that override the global ones for some clusters, they are only returned.
+	Clusters []*clusterFrequencySettings `json:"clusters,omitempty"`
+}
+
+type clusterFrequencySettings struct {
+	ClusterUUID string `json:"cluster_uuid,omitempty"`
+	Heart       string `json:"heart,omitempty"`
+	Status      string `json:"status,omitempty"`
+}
+
+// getFrequencies returns the frequencies currently used by the monitors as well as the cluster overrides.
+func (m *Manager) getFrequencies(w http.ResponseWriter, _ *http.Request) {
+	m.frequenciesLock.Lock()
+	frequencies := m.frequencies
+	m.frequenciesLock.Unlock()
+
+	overrides, err := m.store.GetClusterFrequencies()
+	if err != nil {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusInternalServerError,
+			Msg:    "could not get cluster frequencies",
+			Extras: err.Error(),
+		}, w, nil)
+		return
+	}
+
+	settings := &frequencySettings{
+		Heart:     frequencies.Heart.String(),
+		Status:    frequencies.Status.String(),
+		Discovery: frequencies.Discovery.String(),
+		Clusters:  make([]*clusterFrequencySettings, 0, len(overrides)),
+	}
+
+	for _, override := range overrides {
+		settings.Clusters = append(settings.Clusters, &clusterFrequencySettings{
+			ClusterUUID: override.ClusterUUID,
+			Heart:       formatFrequency(override.Heart),
+			Status:      formatFrequency(override.Status),
+		})
+	}
+
+	restutil.MarshalAndSend(http.StatusOK, settings, w, nil)
+}
+
+// setFrequencies changes the frequencies of the monitors, the ones not given are left as they are. The new frequencies
+// are persisted and the running monitors are reconfigured without interrupting any work in progress.
+func (m *Manager) setFrequencies(w http.ResponseWriter, r *http.Request) {
+	var req frequencySettings
+	if !restutil.DecodeJSONRequestBody(r.Body, &req, w) {
+		return
+	}
+
+	if req.Clusters != nil {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusBadRequest,
+			Msg:    "cluster frequencies have to be set for each cluster",
+		}, w, nil)
+		return
+	}
+
+	var (
+		update values.Frequencies
+		err    error
+	)
+
+	for _, frequency := range []struct {
+		name  string
+		value string
+		out   *time.Duration
+	}{
+		{name: "heart", value: req.Heart, out: &update.Heart},
+		{name: "status", value: req.Status, out: &update.Status},
+		{name: "discovery", value: req.Discovery, out: &update.Discovery},
+	} {
+		if *frequency.out, err = parseFrequency(frequency.name, frequency.value); err != nil {
+			restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+				Status: http.StatusBadRequest,
+				Msg:    err.Error(),
+			}, w, nil)
+			return
+		}
+	}
+
+	m.frequenciesLock.Lock()
+	defer m.frequenciesLock.Unlock()
+
+	frequencies := update.Merge(m.frequencies)
+	if err = m.store.SetFrequencies(&frequencies); err != nil {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusInternalServerError,
+			Msg:    "could not store frequencies",
+			Extras: err.Error(),
+		}, w, nil)
+		return
+	}
+
+	m.frequencies = frequencies
+	m.reconfigureMonitors()
+
+	zap.S().Infow("(Manager) Frequencies updated", "frequencies", frequencies)
+	restutil.SendJSONResponse(http.StatusOK, []byte{}, w, nil)
+}
+
+// setClusterFrequencies overrides the heartbeat and/or status check frequencies for a single cluster.
+func (m *Manager) setClusterFrequencies(w http.ResponseWriter, r *http.Request) {
+	uuid, ok := m.convertAliasToUUID(mux.Vars(r)["uuid"], w)
+	if !ok {
+		return
+	}
+
+	var req clusterFrequencySettings
+	if !restutil.DecodeJSONRequestBody(r.Body, &req, w) {
+		return
+	}
+
+	heart, err := parseFrequency("heart", req.Heart)
+	if err != nil {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusBadRequest,
+			Msg:    err.Error(),
+		}, w, nil)
+		return
+	}
+
+	statusFrequency, err := parseFrequency("status", req.Status)
+	if err != nil {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusBadRequest,
+			Msg:    err.Error(),
+		}, w, nil)
+		return
+	}
+
+	if heart == 0 && statusFrequency == 0 {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusBadRequest,
+			Msg:    "at least one of [heart, status] is required",
+		}, w, nil)
+		return
+	}
+
+	if _, err = m.store.GetCluster(uuid, false); err != nil {
+		if errors.Is(err, values.ErrNotFound) {
+			restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+				Status: http.StatusNotFound,
+				Msg:    fmt.Sprintf("cluster with UUID '%s' not found", uuid),
+			}, w, nil)
+			return
+		}
+
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusInternalServerError,
+			Msg:    "could not get cluster details",
+			Extras: err.Error(),
+		}, w, nil)
+		return
+	}
+
+	err = m.store.SetClusterFrequencies(&values.ClusterFrequencies{
+		ClusterUUID: uuid,
+		Heart:       heart,
+		Status:      statusFrequency,
+	})
+	if err != nil {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusInternalServerError,
+			Msg:    "could not store cluster frequencies",
+			Extras: err.Error(),
+		}, w, nil)
+		return
+	}
+
+	m.frequenciesLock.Lock()
+	defer m.frequenciesLock.Unlock()
+	m.reconfigureMonitors()
+
+	zap.S().Infow("(Manager) Cluster frequencies updated", "cluster", uuid, "heart", heart, "status",
+		statusFrequency)
+	restutil.SendJSONResponse(http.StatusOK, []byte{}, w, nil)
+}
+
+// deleteClusterFrequencies removes the cluster overrides so it uses the global frequencies again.
+func (m *Manager) deleteClusterFrequencies(w http.ResponseWriter, r *http.Request) {
+	uuid, ok := m.convertAliasToUUID(mux.Vars(r)["uuid"], w)
+	if !ok {
+		return
+	}
+
+	if err := m.store.DeleteClusterFrequencies(uuid); err != nil {
+		if errors.Is(err, values.ErrNotFound) {
+			restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+				Status: http.StatusNotFound,
+				Msg:    fmt.Sprintf("cluster with UUID '%s' does not have its own frequencies", uuid),
+			}, w, nil)
+			return
+		}
+
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusInternalServerError,
+			Msg:    "could not delete cluster frequencies",
+			Extras: err.Error(),
+		}, w, nil)
+		return
+	}
+
+	m.frequenciesLock.Lock()
+	defer m.frequenciesLock.Unlock()
+	m.reconfigureMonitors()
+
+	zap.S().Infow("(Manager) Cluster frequencies removed", "cluster", uuid)
+	restutil.SendJSONResponse(http.StatusOK, []byte{}, w, nil)
+}
+
+// reconfigureMonitors passes the current frequencies to the monitors so they also pick up any changes to the cluster
+// frequencies. The caller must hold frequenciesLock.
+func (m *Manager) reconfigureMonitors() {
+	m.heartMonitor.Reconfigure(m.frequencies.Heart)
+	m.statusMonitor.Reconfigure(m.frequencies.Status)
+	if m.discoveryManager != nil {
+		m.discoveryManager.Reconfigure(m.frequencies.Discovery)
+	}
+}
+
+// parseFrequency parses the frequency, empty values are returned as 0.
+func parseFrequency(name, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	frequency, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s frequency '%s'", name, value)
+	}
+
+	if frequency < minFrequency {
+		return 0, fmt.Errorf("the %s frequency must be at least %s", name, minFrequency)
+	}
+
+	return frequency, nil
+}
+
+// formatFrequency formats the frequency leaving unset ones empty.
+func formatFrequency(frequency time.Duration) string {
+	if frequency == 0 {
+		return ""
+	}
+
+	return frequency.String()
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package manager
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrequencyHandlers(t *testing.T) {
+	mgr, router := createTestRoleManager(t)
+
+	getFrequencies := func(t *testing.T) *frequencySettings {
+		res := doRoleRequest(router, "user", http.MethodGet, "/api/v1/settings/frequencies", nil)
+		require.Equal(t, http.StatusOK, res.Code)
+
+		var settings frequencySettings
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &settings))
+		return &settings
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		require.Equal(t, &frequencySettings{
+			Heart:     DefaultFrequencyConfiguration.Heart.String(),
+			Status:    DefaultFrequencyConfiguration.Status.String(),
+			Discovery: DefaultFrequencyConfiguration.Discovery.String(),
+		}, getFrequencies(t))
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		for _, user := range []string{"viewer", "operator"} {
+			res := doRoleRequest(router, user, http.MethodPut, "/api/v1/settings/frequencies",
+				map[string]string{"heart": "10s"})
+			require.Equal(t, http.StatusForbidden, res.Code)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		res := doRoleRequest(router, "user", http.MethodPut, "/api/v1/settings/frequencies",
+			map[string]string{"heart": "10s"})
+		require.Equal(t, http.StatusOK, res.Code)
+
+		require.Equal(t, &frequencySettings{
+			Heart:     "10s",
+			Status:    DefaultFrequencyConfiguration.Status.String(),
+			Discovery: DefaultFrequencyConfiguration.Discovery.String(),
+		}, getFrequencies(t))
+
+		stored, err := mgr.store.GetFrequencies()
+		require.NoError(t, err)
+		require.Equal(t, 10*time.Second, stored.Heart)
+		require.Equal(t, DefaultFrequencyConfiguration.Status, stored.Status)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, body := range []map[string]string{{"heart": "often"}, {"status": "10ms"}} {
+			res := doRoleRequest(router, "user", http.MethodPut, "/api/v1/settings/frequencies", body)
+			require.Equal(t, http.StatusBadRequest, res.Code)
+		}
+	})
+
+	t.Run("cluster", func(t *testing.T) {
+		res := doRoleRequest(router, "user", http.MethodPut, "/api/v1/settings/frequencies/clusters/a-0",
+			map[string]string{"heart": "10s"})
+		require.Equal(t, http.StatusOK, res.Code)
+
+		require.Equal(t, []*clusterFrequencySettings{{ClusterUUID: "uuid-0", Heart: "10s"}},
+			getFrequencies(t).Clusters)
+
+		overrides, err := mgr.store.GetClusterFrequencies()
+		require.NoError(t, err)
+		require.Equal(t, []*values.ClusterFrequencies{{ClusterUUID: "uuid-0", Heart: 10 * time.Second}}, overrides)
+
+		res = doRoleRequest(router, "user", http.MethodPut, "/api/v1/settings/frequencies/clusters/uuid-1",
+			map[string]string{})
+		require.Equal(t, http.StatusBadRequest, res.Code)
+
+		res = doRoleRequest(router, "user", http.MethodPut, "/api/v1/settings/frequencies/clusters/uuid-9",
+			map[string]string{"status": "10m"})
+		require.Equal(t, http.StatusNotFound, res.Code)
+
+		res = doRoleRequest(router, "user", http.MethodDelete, "/api/v1/settings/frequencies/clusters/uuid-0", nil)
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Empty(t, getFrequencies(t).Clusters)
+
+		res = doRoleRequest(router, "user", http.MethodDelete, "/api/v1/settings/frequencies/clusters/uuid-0", nil)
+		require.Equal(t, http.StatusNotFound, res.Code)
+	})
+}
@@ -6,6 +6,7 @@
 package manager
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/couchbase/tools-common/restutil"
 	"github.com/gorilla/mux"
+	"go.uber.org/zap"
 )
 
 type resultCluster struct {
@@ -24,6 +26,16 @@ type resultCluster struct {
 	BucketsSummary values.BucketsSummary `json:"buckets_summary"`
 	HeartBeatIssue values.HeartIssue     `json:"heart_beat_issue,omitempty"`
 	LastUpdate     time.Time             `json:"last_update"`
+	StatusResults  *groupedResults       `json:"status_results"`
+	Dismissed      int                   `json:"dismissed"`
+}
+
+// groupedResults are the checker results of a cluster grouped by their scope. Node results are keyed by node UUID and
+// bucket results by bucket name.
+type groupedResults struct {
+	Cluster []*values.WrappedCheckerResult            `json:"cluster"`
+	Nodes   map[string][]*values.WrappedCheckerResult `json:"nodes"`
+	Buckets map[string][]*values.WrappedCheckerResult `json:"buckets"`
 }
 
 func (m *Manager) getClusterStatusReport(w http.ResponseWriter, r *http.Request) {
@@ -34,6 +46,9 @@ func (m *Manager) getClusterStatusCheckerResult(w http.ResponseWriter, r *http.R
 	m.getCheckerResultCommon(w, r, false)
 }
 
+// getCheckerResultCommon returns the cluster together with its checker results. The results can be filtered using the
+// status, node, bucket and checker query parameters, the filters apply within each group. If filterDismissed is set the
+// results silenced by an active dismissal are removed and only counted.
 func (m *Manager) getCheckerResultCommon(w http.ResponseWriter, r *http.Request, filterDismissed bool) {
 	vars := mux.Vars(r)
 	uuid, ok := m.convertAliasToUUID(vars["uuid"], w)
@@ -41,6 +56,21 @@ func (m *Manager) getCheckerResultCommon(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
+	search, err := parseCheckerSearch(r)
+	if err != nil {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusBadRequest,
+			Msg:    "invalid query parameters",
+			Extras: err.Error(),
+		}, w, nil)
+		return
+	}
+
+	search.Cluster = &uuid
+	if name, ok := vars["name"]; ok {
+		search.Name = &name
+	}
+
 	cluster, err := m.store.GetCluster(uuid, false)
 	if err != nil {
 		if errors.Is(err, values.ErrNotFound) {
@@ -59,6 +89,31 @@ func (m *Manager) getCheckerResultCommon(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
+	results, err := m.store.GetCheckerResult(search)
+	if err != nil {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusInternalServerError,
+			Msg:    "could not get checker results",
+			Extras: err.Error(),
+		}, w, nil)
+		return
+	}
+
+	var dismissed int
+	if filterDismissed {
+		dismissals, err := m.store.GetDismissals(values.DismissalSearchSpace{ClusterUUID: &uuid})
+		if err != nil {
+			restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+				Status: http.StatusInternalServerError,
+				Msg:    "could not get dismissals",
+				Extras: err.Error(),
+			}, w, nil)
+			return
+		}
+
+		results, dismissed = values.DismissResults(results, dismissals)
+	}
+
 	clusterOut := &resultCluster{
 		UUID:           cluster.UUID,
 		Name:           cluster.Name,
@@ -66,12 +121,186 @@ func (m *Manager) getCheckerResultCommon(w http.ResponseWriter, r *http.Request,
 		NodesSummary:   cluster.NodesSummary,
 		HeartBeatIssue: cluster.HeartBeatIssue,
 		LastUpdate:     cluster.LastUpdate,
+		StatusResults:  groupResults(results),
+		Dismissed:      dismissed,
 	}
 
+	restutil.MarshalAndSend(http.StatusOK, clusterOut, w, nil)
+}
+
+// parseCheckerSearch builds a search from the status, node, bucket and checker query parameters.
+func parseCheckerSearch(r *http.Request) (values.CheckerSearch, error) {
+	var search values.CheckerSearch
+	query := r.URL.Query()
+
+	if status := query.Get("status"); status != "" {
+		checkerStatus := values.CheckerStatus(status)
+		switch checkerStatus {
+		case values.GoodCheckerStatus, values.WarnCheckerStatus, values.AlertCheckerStatus, values.InfoCheckerStatus:
+		default:
+			return search, fmt.Errorf("invalid status '%s'", status)
+		}
+
+		search.Status = &checkerStatus
+	}
+
+	if node := query.Get("node"); node != "" {
+		search.Node = &node
+	}
+
+	if bucket := query.Get("bucket"); bucket != "" {
+		search.Bucket = &bucket
+	}
+
+	if checker := query.Get("checker"); checker != "" {
+		search.Name = &checker
+	}
+
+	return search, nil
+}
+
+// groupResults groups the results by scope. A result for a bucket is grouped under the bucket even if it is also for a
+// single node, as is the case for the KV checkers that run against each node.
+func groupResults(results []*values.WrappedCheckerResult) *groupedResults {
+	grouped := &groupedResults{
+		Cluster: make([]*values.WrappedCheckerResult, 0),
+		Nodes:   make(map[string][]*values.WrappedCheckerResult),
+		Buckets: make(map[string][]*values.WrappedCheckerResult),
+	}
+
+	for _, result := range results {
+		switch {
+		case result.Bucket != "":
+			grouped.Buckets[result.Bucket] = append(grouped.Buckets[result.Bucket], result)
+		case result.Node != "":
+			grouped.Nodes[result.Node] = append(grouped.Nodes[result.Node], result)
+		default:
+			grouped.Cluster = append(grouped.Cluster, result)
+		}
+	}
+
+	return grouped
+}
+
+// statusSummaries are the status summaries keyed by cluster UUID.
+type statusSummaries map[string]*values.ClusterStatusSummary
+
+// get returns the summary for the cluster, clusters without results get an empty summary.
+func (s statusSummaries) get(uuid string) *values.ClusterStatusSummary {
+	if summary, ok := s[uuid]; ok {
+		return summary
+	}
+
+	return &values.ClusterStatusSummary{}
+}
+
+// getStatusSummaries counts the checker results for the given cluster, or for all clusters if no UUID is given. Results
+// silenced by an active dismissal are only counted as dismissed.
+func (m *Manager) getStatusSummaries(uuid *string) (statusSummaries, error) {
+	results, err := m.store.GetCheckerResult(values.CheckerSearch{Cluster: uuid})
 	if err != nil {
+		return nil, fmt.Errorf("could not get checker results: %w", err)
+	}
+
+	dismissals, err := m.store.GetDismissals(values.DismissalSearchSpace{ClusterUUID: uuid})
+	if err != nil {
+		return nil, fmt.Errorf("could not get dismissals: %w", err)
+	}
+
+	summaries := make(statusSummaries)
+	for _, result := range results {
+		summary, ok := summaries[result.Cluster]
+		if !ok {
+			summary = &values.ClusterStatusSummary{}
+			summaries[result.Cluster] = summary
+		}
+
+		if values.IsDismissed(result, dismissals) {
+			summary.Dismissed++
+			continue
+		}
+
+		summary.Add(result.Result)
+	}
+
+	return summaries, nil
+}
+
+// getCheckerDefinitions returns the definitions of all the checkers keyed by name.
+func (m *Manager) getCheckerDefinitions(w http.ResponseWriter, _ *http.Request) {
+	restutil.MarshalAndSend(http.StatusOK, values.AllCheckerDefs, w, nil)
+}
+
+func (m *Manager) getCheckerDefinition(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	definition, ok := values.AllCheckerDefs[name]
+	if !ok {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusNotFound,
+			Msg:    fmt.Sprintf("checker '%s' not found", name),
+		}, w, nil)
 		return
 	}
 
+	restutil.MarshalAndSend(http.StatusOK, definition, w, nil)
+}
 
-	restutil.MarshalAndSend(http.StatusOK, clusterOut, w, nil)
+// refreshCluster triggers a heartbeat and a status check for the cluster without waiting for the monitors. Both are
+// done in the background after responding.
+func (m *Manager) refreshCluster(w http.ResponseWriter, r *http.Request) {
+	uuid, ok := m.convertAliasToUUID(mux.Vars(r)["uuid"], w)
+	if !ok {
+		return
+	}
+
+	cluster, err := m.store.GetCluster(uuid, true)
+	if err != nil {
+		if errors.Is(err, values.ErrNotFound) {
+			restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+				Status: http.StatusNotFound,
+				Msg:    fmt.Sprintf("cluster with UUID '%s' not found", uuid),
+			}, w, nil)
+			return
+		}
+
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusInternalServerError,
+			Msg:    "could not get cluster details",
+			Extras: err.Error(),
+		}, w, nil)
+		return
+	}
+
+	if !cluster.Enterprise {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusBadRequest,
+			Msg:    "status checks are only supported for enterprise clusters",
+		}, w, nil)
+		return
+	}
+
+	restutil.SendJSONResponse(http.StatusOK, []byte{}, w, nil)
+
+	go func() {
+		if err := m.heartMonitor.HeartBeatCluster(context.Background(), cluster); err != nil {
+			zap.S().Warnw("(Manager) Could not refresh cluster heartbeat", "cluster", uuid, "err", err)
+		}
+
+		// get the cluster again so the checkers see what the heartbeat found
+		cluster, err := m.store.GetCluster(uuid, true)
+		if err != nil {
+			zap.S().Warnw("(Manager) Could not get cluster after heartbeat", "cluster", uuid, "err", err)
+			return
+		}
+
+		m.checkCluster(cluster)
+	}()
+}
+
+// checkCluster runs the status checkers against the cluster. It is meant to be run in the background so any failure
+// is only logged.
+func (m *Manager) checkCluster(cluster *values.CouchbaseCluster) {
+	if err := m.statusMonitor.CheckCluster(context.Background(), cluster); err != nil {
+		zap.S().Warnw("(Manager) Could not check cluster status", "cluster", cluster.UUID, "err", err)
+	}
 }
@@ -16,7 +16,6 @@ import (
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/heart/mocks"
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
 
-	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -33,6 +32,16 @@ type statusCheckerTestCase struct {
 func TestGetClusterStatusReport(t *testing.T) {
 	mgr := createTestManager(t)
 	loadTestData(t, mgr.store)
+	require.NoError(t, mgr.store.SetCheckerResult(&values.WrappedCheckerResult{
+		Cluster: "uuid-0",
+		Node:    "Node-0",
+		Bucket:  "bucket-0",
+		Result: &values.CheckerResult{
+			Name:   "checker-4",
+			Status: values.AlertCheckerStatus,
+			Time:   time.Time{}.UTC(),
+		},
+	}))
 
 	mgr.setupKeys()
 	mgr.startRESTServers()
@@ -63,38 +72,58 @@ func TestGetClusterStatusReport(t *testing.T) {
 						Services:          []string{"kv"},
 					},
 				},
-				StatusResults: []*values.WrappedCheckerResult{
-					{
-						Cluster: "uuid-0",
-						Result: &values.CheckerResult{
-							Name:   "checker-0",
-							Status: values.GoodCheckerStatus,
-							Time:   time.Time{}.UTC(),
+				StatusResults: &groupedResults{
+					Cluster: []*values.WrappedCheckerResult{
+						{
+							Cluster: "uuid-0",
+							Result: &values.CheckerResult{
+								Name:   "checker-0",
+								Status: values.GoodCheckerStatus,
+								Time:   time.Time{}.UTC(),
+							},
 						},
-					},
-					{
-						Cluster: "uuid-0",
-						Node:    "Node-1",
-						Result: &values.CheckerResult{
-							Name:   "checker-1",
-							Status: values.WarnCheckerStatus,
-							Time:   time.Time{}.UTC(),
+						{
+							Cluster: "uuid-0",
+							Result: &values.CheckerResult{
+								Name:   "checker-2",
+								Status: values.AlertCheckerStatus,
+								Time:   time.Time{}.UTC(),
+							},
+						},
+						{
+							Cluster: "uuid-0",
+							Result: &values.CheckerResult{
+								Name:   "checker-3",
+								Status: values.InfoCheckerStatus,
+								Time:   time.Time{}.UTC(),
+							},
 						},
 					},
-					{
-						Cluster: "uuid-0",
-						Result: &values.CheckerResult{
-							Name:   "checker-2",
-							Status: values.AlertCheckerStatus,
-							Time:   time.Time{}.UTC(),
+					Nodes: map[string][]*values.WrappedCheckerResult{
+						"Node-1": {
+							{
+								Cluster: "uuid-0",
+								Node:    "Node-1",
+								Result: &values.CheckerResult{
+									Name:   "checker-1",
+									Status: values.WarnCheckerStatus,
+									Time:   time.Time{}.UTC(),
+								},
+							},
 						},
 					},
-					{
-						Cluster: "uuid-0",
-						Result: &values.CheckerResult{
-							Name:   "checker-3",
-							Status: values.InfoCheckerStatus,
-							Time:   time.Time{}.UTC(),
+					Buckets: map[string][]*values.WrappedCheckerResult{
+						"bucket-0": {
+							{
+								Cluster: "uuid-0",
+								Node:    "Node-0",
+								Bucket:  "bucket-0",
+								Result: &values.CheckerResult{
+									Name:   "checker-4",
+									Status: values.AlertCheckerStatus,
+									Time:   time.Time{}.UTC(),
+								},
+							},
 						},
 					},
 				},
@@ -117,8 +146,12 @@ func TestGetClusterStatusReport(t *testing.T) {
 						Services:          []string{"kv"},
 					},
 				},
-				StatusResults: []*values.WrappedCheckerResult{},
-				Dismissed:     1,
+				StatusResults: &groupedResults{
+					Cluster: []*values.WrappedCheckerResult{},
+					Nodes:   map[string][]*values.WrappedCheckerResult{},
+					Buckets: map[string][]*values.WrappedCheckerResult{},
+				},
+				Dismissed: 1,
 			},
 		},
 		{
@@ -139,19 +172,153 @@ func TestGetClusterStatusReport(t *testing.T) {
 						Services:          []string{"kv"},
 					},
 				},
-				StatusResults: []*values.WrappedCheckerResult{
+				StatusResults: &groupedResults{
+					Cluster: []*values.WrappedCheckerResult{},
+					Nodes: map[string][]*values.WrappedCheckerResult{
+						"Node-1": {
+							{
+								Cluster: "uuid-0",
+								Node:    "Node-1",
+								Result: &values.CheckerResult{
+									Name:   "checker-1",
+									Status: values.WarnCheckerStatus,
+									Time:   time.Time{}.UTC(),
+								},
+							},
+						},
+					},
+					Buckets: map[string][]*values.WrappedCheckerResult{},
+				},
+			},
+		},
+		{
+			name:               "bucketFilter",
+			query:              url.Values{"bucket": []string{"bucket-0"}},
+			clusterUUID:        "uuid-0",
+			expectedStatusCode: http.StatusOK,
+			expectedCluster: &resultCluster{
+				UUID: "uuid-0",
+				Name: "Cluster-0",
+				NodesSummary: values.NodesSummary{
+					{
+						NodeUUID:          "Node-0",
+						Version:           "7.0.0-0000-enterprise",
+						Host:              "http://localhost:9000",
+						ClusterMembership: "active",
+						Status:            "status",
+						Services:          []string{"kv"},
+					},
+				},
+				StatusResults: &groupedResults{
+					Cluster: []*values.WrappedCheckerResult{},
+					Nodes:   map[string][]*values.WrappedCheckerResult{},
+					Buckets: map[string][]*values.WrappedCheckerResult{
+						"bucket-0": {
+							{
+								Cluster: "uuid-0",
+								Node:    "Node-0",
+								Bucket:  "bucket-0",
+								Result: &values.CheckerResult{
+									Name:   "checker-4",
+									Status: values.AlertCheckerStatus,
+									Time:   time.Time{}.UTC(),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:               "statusFilter",
+			query:              url.Values{"status": []string{"alert"}},
+			clusterUUID:        "uuid-0",
+			expectedStatusCode: http.StatusOK,
+			expectedCluster: &resultCluster{
+				UUID: "uuid-0",
+				Name: "Cluster-0",
+				NodesSummary: values.NodesSummary{
+					{
+						NodeUUID:          "Node-0",
+						Version:           "7.0.0-0000-enterprise",
+						Host:              "http://localhost:9000",
+						ClusterMembership: "active",
+						Status:            "status",
+						Services:          []string{"kv"},
+					},
+				},
+				StatusResults: &groupedResults{
+					Cluster: []*values.WrappedCheckerResult{
+						{
+							Cluster: "uuid-0",
+							Result: &values.CheckerResult{
+								Name:   "checker-2",
+								Status: values.AlertCheckerStatus,
+								Time:   time.Time{}.UTC(),
+							},
+						},
+					},
+					Nodes: map[string][]*values.WrappedCheckerResult{},
+					Buckets: map[string][]*values.WrappedCheckerResult{
+						"bucket-0": {
+							{
+								Cluster: "uuid-0",
+								Node:    "Node-0",
+								Bucket:  "bucket-0",
+								Result: &values.CheckerResult{
+									Name:   "checker-4",
+									Status: values.AlertCheckerStatus,
+									Time:   time.Time{}.UTC(),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:               "checkerFilter",
+			query:              url.Values{"checker": []string{"checker-1"}},
+			clusterUUID:        "uuid-0",
+			expectedStatusCode: http.StatusOK,
+			expectedCluster: &resultCluster{
+				UUID: "uuid-0",
+				Name: "Cluster-0",
+				NodesSummary: values.NodesSummary{
 					{
-						Cluster: "uuid-0",
-						Node:    "Node-1",
-						Result: &values.CheckerResult{
-							Name:   "checker-1",
-							Status: values.WarnCheckerStatus,
-							Time:   time.Time{}.UTC(),
+						NodeUUID:          "Node-0",
+						Version:           "7.0.0-0000-enterprise",
+						Host:              "http://localhost:9000",
+						ClusterMembership: "active",
+						Status:            "status",
+						Services:          []string{"kv"},
+					},
+				},
+				StatusResults: &groupedResults{
+					Cluster: []*values.WrappedCheckerResult{},
+					Nodes: map[string][]*values.WrappedCheckerResult{
+						"Node-1": {
+							{
+								Cluster: "uuid-0",
+								Node:    "Node-1",
+								Result: &values.CheckerResult{
+									Name:   "checker-1",
+									Status: values.WarnCheckerStatus,
+									Time:   time.Time{}.UTC(),
+								},
+							},
 						},
 					},
+					Buckets: map[string][]*values.WrappedCheckerResult{},
 				},
 			},
 		},
+		{
+			name:               "invalidStatus",
+			query:              url.Values{"status": []string{"bad"}},
+			clusterUUID:        "uuid-0",
+			expectedStatusCode: http.StatusBadRequest,
+		},
 	}
 
 	for _, tc := range cases {
@@ -202,15 +369,19 @@ func TestGetClusterStatusCheckerResult(t *testing.T) {
 						Services:          []string{"kv"},
 					},
 				},
-				StatusResults: []*values.WrappedCheckerResult{
-					{
-						Cluster: "uuid-0",
-						Result: &values.CheckerResult{
-							Name:   "checker-0",
-							Status: values.GoodCheckerStatus,
-							Time:   time.Time{}.UTC(),
+				StatusResults: &groupedResults{
+					Cluster: []*values.WrappedCheckerResult{
+						{
+							Cluster: "uuid-0",
+							Result: &values.CheckerResult{
+								Name:   "checker-0",
+								Status: values.GoodCheckerStatus,
+								Time:   time.Time{}.UTC(),
+							},
 						},
 					},
+					Nodes:   map[string][]*values.WrappedCheckerResult{},
+					Buckets: map[string][]*values.WrappedCheckerResult{},
 				},
 			},
 		},
@@ -232,15 +403,19 @@ func TestGetClusterStatusCheckerResult(t *testing.T) {
 						Services:          []string{"kv"},
 					},
 				},
-				StatusResults: []*values.WrappedCheckerResult{
-					{
-						Cluster: "uuid-1",
-						Result: &values.CheckerResult{
-							Name:   "checker-0",
-							Status: values.AlertCheckerStatus,
-							Time:   time.Time{}.UTC(),
+				StatusResults: &groupedResults{
+					Cluster: []*values.WrappedCheckerResult{
+						{
+							Cluster: "uuid-1",
+							Result: &values.CheckerResult{
+								Name:   "checker-0",
+								Status: values.AlertCheckerStatus,
+								Time:   time.Time{}.UTC(),
+							},
 						},
 					},
+					Nodes:   map[string][]*values.WrappedCheckerResult{},
+					Buckets: map[string][]*values.WrappedCheckerResult{},
 				},
 			},
 		},
@@ -263,7 +438,11 @@ func TestGetClusterStatusCheckerResult(t *testing.T) {
 						Services:          []string{"kv"},
 					},
 				},
-				StatusResults: []*values.WrappedCheckerResult{},
+				StatusResults: &groupedResults{
+					Cluster: []*values.WrappedCheckerResult{},
+					Nodes:   map[string][]*values.WrappedCheckerResult{},
+					Buckets: map[string][]*values.WrappedCheckerResult{},
+				},
 			},
 		},
 	}
@@ -379,7 +558,7 @@ func TestRefreshCluster(t *testing.T) {
 
 	mockMonitor := new(mocks.MonitorIFace)
 	mockMonitor.On("Start")
-	mockMonitor.On("HeartBeatCluster", mock.Anything).Run(func(args mock.Arguments) {
+	mockMonitor.On("HeartBeatCluster", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
 		callNum++
 	}).Return(nil)
 
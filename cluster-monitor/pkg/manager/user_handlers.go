@@ -0,0 +1,228 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/auth"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/storage"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/couchbase/tools-common/restutil"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+const maxCredentialLength = 64
+
+type userRequest struct {
+	User     string      `json:"user"`
+	Password string      `json:"password"`
+	Role     values.Role `json:"role"`
+}
+
+func (m *Manager) getUsers(w http.ResponseWriter, _ *http.Request) {
+	users, err := m.store.GetUsers()
+	if err != nil {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusInternalServerError,
+			Msg:    "could not get users",
+			Extras: err.Error(),
+		}, w, nil)
+		return
+	}
+
+	restutil.MarshalAndSend(http.StatusOK, users, w, nil)
+}
+
+func (m *Manager) getUser(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["user"]
+	user, err := m.store.GetUser(name)
+	if err != nil {
+		handleUserError(w, name, "could not get user", err)
+		return
+	}
+
+	restutil.MarshalAndSend(http.StatusOK, user, w, nil)
+}
+
+// addUser creates a new user, if no role is given it will be a viewer.
+func (m *Manager) addUser(w http.ResponseWriter, r *http.Request) {
+	var body userRequest
+	if !restutil.DecodeJSONRequestBody(r.Body, &body, w) {
+		return
+	}
+
+	if body.Role == "" {
+		body.Role = values.ViewerRole
+	}
+
+	if err := validateUserRequest(body, true); err != nil {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusBadRequest,
+			Msg:    err.Error(),
+		}, w, nil)
+		return
+	}
+
+	hashedPassword, err := auth.HashPassword(body.Password)
+	if err != nil {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusInternalServerError,
+			Msg:    "could not hash password",
+			Extras: err.Error(),
+		}, w, nil)
+		return
+	}
+
+	err = m.store.AddUser(&values.User{User: body.User, Password: hashedPassword, Role: body.Role})
+	if err != nil {
+		if errors.Is(err, storage.ErrUserAlreadyExists) {
+			restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+				Status: http.StatusConflict,
+				Msg:    fmt.Sprintf("user '%s' already exists", body.User),
+			}, w, nil)
+			return
+		}
+
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusInternalServerError,
+			Msg:    "could not add user",
+			Extras: err.Error(),
+		}, w, nil)
+		return
+	}
+
+	zap.S().Infow("(Manager) Added user", "user", body.User, "role", body.Role)
+	restutil.SendJSONResponse(http.StatusOK, []byte{}, w, nil)
+}
+
+// updateUser changes the password and/or role of a user. Users cannot change their own role so there is always at
+// least one admin.
+func (m *Manager) updateUser(w http.ResponseWriter, r *http.Request) {
+	var body userRequest
+	if !restutil.DecodeJSONRequestBody(r.Body, &body, w) {
+		return
+	}
+
+	body.User = mux.Vars(r)["user"]
+	if body.Password == "" && body.Role == "" {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusBadRequest,
+			Msg:    "password or role is required",
+		}, w, nil)
+		return
+	}
+
+	if err := validateUserRequest(body, false); err != nil {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusBadRequest,
+			Msg:    err.Error(),
+		}, w, nil)
+		return
+	}
+
+	if body.Role != "" && isRequestUser(r, body.User) {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusBadRequest,
+			Msg:    "users cannot change their own role",
+		}, w, nil)
+		return
+	}
+
+	update := &values.User{User: body.User, Role: body.Role}
+	if body.Password != "" {
+		hashedPassword, err := auth.HashPassword(body.Password)
+		if err != nil {
+			restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+				Status: http.StatusInternalServerError,
+				Msg:    "could not hash password",
+				Extras: err.Error(),
+			}, w, nil)
+			return
+		}
+
+		update.Password = hashedPassword
+	}
+
+	if err := m.store.UpdateUser(update); err != nil {
+		handleUserError(w, body.User, "could not update user", err)
+		return
+	}
+
+	zap.S().Infow("(Manager) Updated user", "user", body.User, "role", body.Role)
+	restutil.SendJSONResponse(http.StatusOK, []byte{}, w, nil)
+}
+
+// deleteUser removes a user. Users cannot delete themselves so there is always at least one admin.
+func (m *Manager) deleteUser(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["user"]
+	if isRequestUser(r, name) {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusBadRequest,
+			Msg:    "users cannot delete themselves",
+		}, w, nil)
+		return
+	}
+
+	if err := m.store.DeleteUser(name); err != nil {
+		handleUserError(w, name, "could not delete user", err)
+		return
+	}
+
+	zap.S().Infow("(Manager) Deleted user", "user", name)
+	restutil.SendJSONResponse(http.StatusOK, []byte{}, w, nil)
+}
+
+// validateUserRequest checks the user request fields, the password is only required for new users.
+func validateUserRequest(req userRequest, newUser bool) error {
+	if len(req.User) == 0 {
+		return fmt.Errorf("user is required")
+	}
+
+	if len(req.User) > maxCredentialLength {
+		return fmt.Errorf("max user length is %d characters", maxCredentialLength)
+	}
+
+	if newUser && len(req.Password) == 0 {
+		return fmt.Errorf("password is required")
+	}
+
+	if len(req.Password) > maxCredentialLength {
+		return fmt.Errorf("max password length is %d characters", maxCredentialLength)
+	}
+
+	if req.Role != "" {
+		return req.Role.Validate()
+	}
+
+	return nil
+}
+
+// isRequestUser returns true if the authenticated user making the request is the given user.
+func isRequestUser(r *http.Request, name string) bool {
+	user, ok := r.Context().Value(userContextKey).(*values.User)
+	return ok && user.User == name
+}
+
+func handleUserError(w http.ResponseWriter, name, msg string, err error) {
+	if errors.Is(err, values.ErrNotFound) {
+		restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+			Status: http.StatusNotFound,
+			Msg:    fmt.Sprintf("user '%s' not found", name),
+		}, w, nil)
+		return
+	}
+
+	restutil.HandleErrorWithExtras(restutil.ErrorResponse{
+		Status: http.StatusInternalServerError,
+		Msg:    msg,
+		Extras: err.Error(),
+	}, w, nil)
+}
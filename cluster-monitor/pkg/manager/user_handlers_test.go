@@ -0,0 +1,173 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package manager
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/auth"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+// createTestRoleManager creates a manager with an admin (user), an operator and a viewer. All users have the password
+// "password".
+func createTestRoleManager(t *testing.T) (*Manager, *mux.Router) {
+	mgr := createTestManager(t)
+	loadTestData(t, mgr.store)
+
+	password, err := auth.HashPassword("password")
+	require.NoError(t, err)
+
+	require.NoError(t, mgr.store.AddUser(&values.User{User: "operator", Password: password,
+		Role: values.OperatorRole}))
+	require.NoError(t, mgr.store.AddUser(&values.User{User: "viewer", Password: password, Role: values.ViewerRole}))
+
+	mgr.setupKeys()
+	return mgr, NewRouter(mgr)
+}
+
+func doRoleRequest(router *mux.Router, user, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != nil {
+		data, _ := json.Marshal(body)
+		reader = bytes.NewReader(data)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.SetBasicAuth(user, "password")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestRouteRoles(t *testing.T) {
+	_, router := createTestRoleManager(t)
+
+	type testCase struct {
+		method    string
+		path      string
+		body      interface{}
+		forbidden []string
+	}
+
+	cases := []testCase{
+		{method: http.MethodGet, path: "/api/v1/clusters"},
+		{method: http.MethodGet, path: "/api/v1/clusters/uuid-0/status"},
+		{method: http.MethodGet, path: "/api/v1/dismissals"},
+		{
+			method:    http.MethodPost,
+			path:      "/api/v1/dismissals",
+			body:      map[string]interface{}{"level": "all", "checker_name": values.CheckMixedMode, "forever": true},
+			forbidden: []string{"viewer"},
+		},
+		{method: http.MethodPost, path: "/api/v1/aliases/a-1", body: map[string]string{"cluster_uuid": "uuid-1"},
+			forbidden: []string{"viewer"}},
+		{method: http.MethodGet, path: "/api/v1/cloud/credentials", forbidden: []string{"viewer", "operator"}},
+		{method: http.MethodGet, path: "/api/v1/users", forbidden: []string{"viewer", "operator"}},
+		{method: http.MethodDelete, path: "/api/v1/clusters/uuid-1", forbidden: []string{"viewer", "operator"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.method+tc.path, func(t *testing.T) {
+			for _, user := range []string{"viewer", "operator", "user"} {
+				res := doRoleRequest(router, user, tc.method, tc.path, tc.body)
+
+				forbidden := false
+				for _, forbiddenUser := range tc.forbidden {
+					forbidden = forbidden || forbiddenUser == user
+				}
+
+				if forbidden {
+					require.Equal(t, http.StatusForbidden, res.Code, "expected %s to be forbidden", user)
+				} else {
+					require.NotEqual(t, http.StatusForbidden, res.Code, "expected %s to be allowed", user)
+					require.NotEqual(t, http.StatusUnauthorized, res.Code, "expected %s to be authenticated", user)
+				}
+			}
+		})
+	}
+}
+
+func TestUserHandlers(t *testing.T) {
+	mgr, router := createTestRoleManager(t)
+
+	t.Run("list", func(t *testing.T) {
+		res := doRoleRequest(router, "user", http.MethodGet, "/api/v1/users", nil)
+		require.Equal(t, http.StatusOK, res.Code)
+		require.NotContains(t, res.Body.String(), "password")
+
+		var users []*values.User
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &users))
+		require.Equal(t, []*values.User{
+			{User: "operator", Role: values.OperatorRole},
+			{User: "user", Admin: true, Role: values.AdminRole},
+			{User: "viewer", Role: values.ViewerRole},
+		}, users)
+	})
+
+	t.Run("add", func(t *testing.T) {
+		res := doRoleRequest(router, "user", http.MethodPost, "/api/v1/users",
+			map[string]string{"user": "support", "password": "password"})
+		require.Equal(t, http.StatusOK, res.Code)
+
+		user, err := mgr.store.GetUser("support")
+		require.NoError(t, err)
+		require.Equal(t, values.ViewerRole, user.Role)
+		require.True(t, auth.CheckPassword("password", user.Password))
+
+		res = doRoleRequest(router, "user", http.MethodPost, "/api/v1/users",
+			map[string]string{"user": "support", "password": "password"})
+		require.Equal(t, http.StatusConflict, res.Code)
+
+		res = doRoleRequest(router, "user", http.MethodPost, "/api/v1/users",
+			map[string]string{"user": "owner", "password": "password", "role": "owner"})
+		require.Equal(t, http.StatusBadRequest, res.Code)
+
+		res = doRoleRequest(router, "user", http.MethodPost, "/api/v1/users", map[string]string{"user": "owner"})
+		require.Equal(t, http.StatusBadRequest, res.Code)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		res := doRoleRequest(router, "user", http.MethodPatch, "/api/v1/users/support",
+			map[string]string{"role": "operator"})
+		require.Equal(t, http.StatusOK, res.Code)
+
+		res = doRoleRequest(router, "support", http.MethodPost, "/api/v1/dismissals",
+			map[string]interface{}{"level": "all", "checker_name": values.CheckMixedMode, "forever": true})
+		require.Equal(t, http.StatusOK, res.Code)
+
+		res = doRoleRequest(router, "user", http.MethodPatch, "/api/v1/users/user",
+			map[string]string{"role": "viewer"})
+		require.Equal(t, http.StatusBadRequest, res.Code)
+
+		res = doRoleRequest(router, "user", http.MethodPatch, "/api/v1/users/nobody",
+			map[string]string{"role": "viewer"})
+		require.Equal(t, http.StatusNotFound, res.Code)
+
+		res = doRoleRequest(router, "user", http.MethodPatch, "/api/v1/users/nobody", map[string]string{})
+		require.Equal(t, http.StatusBadRequest, res.Code)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		res := doRoleRequest(router, "user", http.MethodDelete, "/api/v1/users/user", nil)
+		require.Equal(t, http.StatusBadRequest, res.Code)
+
+		res = doRoleRequest(router, "user", http.MethodDelete, "/api/v1/users/support", nil)
+		require.Equal(t, http.StatusOK, res.Code)
+
+		res = doRoleRequest(router, "user", http.MethodDelete, "/api/v1/users/support", nil)
+		require.Equal(t, http.StatusNotFound, res.Code)
+	})
+}
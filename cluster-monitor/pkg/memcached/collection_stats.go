@@ -0,0 +1,260 @@
+// Copyright (C) 2022 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package memcached
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	memcached "github.com/couchbase/gomemcached/client"
+)
+
+// CollectionStats are the statistics of a collection in a bucket on a single node, or on all of them once aggregated.
+type CollectionStats struct {
+	ScopeID   string `json:"scope_id"`
+	Scope     string `json:"scope"`
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Items     uint64 `json:"items"`
+	MemUsed   uint64 `json:"mem_used"`
+	DiskSize  uint64 `json:"disk_size"`
+	OpsGet    uint64 `json:"ops_get"`
+	OpsStore  uint64 `json:"ops_store"`
+	OpsDelete uint64 `json:"ops_delete"`
+	Host      string `json:"host,omitempty"`
+}
+
+// ScopeStats are the statistics of a scope in a bucket on a single node.
+type ScopeStats struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Collections uint64 `json:"collections"`
+	Host        string `json:"host"`
+}
+
+// CollectionStats collects the statistics of every collection in the given bucket from all nodes in the cluster. It is
+// the equivalent of running `cbstats collections` and is only supported by 7.0 and later. If some of the nodes fail a
+// *NodesError is returned together with the statistics of the others.
+func (m *MemDClient) CollectionStats(bucket string) ([]*CollectionStats, error) {
+	statsRaw, errs, err := m.getPartialStats("collections", bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]*CollectionStats, 0)
+	for host, stat := range statsRaw {
+		collections, err := parseCollectionStats(stat)
+		if err != nil {
+			errs = errs.add(host, fmt.Errorf("could not parse collection stats: %w", err))
+			continue
+		}
+
+		for _, collection := range collections {
+			collection.Host = host
+		}
+
+		stats = append(stats, collections...)
+	}
+
+	if errs != nil {
+		return stats, errs
+	}
+
+	return stats, nil
+}
+
+// ScopeStats collects the statistics of every scope in the given bucket from all nodes in the cluster. It is the
+// equivalent of running `cbstats scopes` and is only supported by 7.0 and later. If some of the nodes fail a
+// *NodesError is returned together with the statistics of the others.
+func (m *MemDClient) ScopeStats(bucket string) ([]*ScopeStats, error) {
+	statsRaw, errs, err := m.getPartialStats("scopes", bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]*ScopeStats, 0)
+	for host, stat := range statsRaw {
+		scopes, err := parseScopeStats(stat)
+		if err != nil {
+			errs = errs.add(host, fmt.Errorf("could not parse scope stats: %w", err))
+			continue
+		}
+
+		for _, scope := range scopes {
+			scope.Host = host
+		}
+
+		stats = append(stats, scopes...)
+	}
+
+	if errs != nil {
+		return stats, errs
+	}
+
+	return stats, nil
+}
+
+// getPartialStats gets the stats from the nodes that can give them, together with the errors of the ones that cannot.
+// An error is only returned if none of the nodes gave their stats.
+func (m *MemDClient) getPartialStats(key, bucket string) (map[string][]memcached.StatValue, *NodesError, error) {
+	statsRaw, err := m.getStats(key, bucket)
+	if err == nil {
+		return statsRaw, nil, nil
+	}
+
+	var errs *NodesError
+	if !errors.As(err, &errs) || len(statsRaw) == 0 {
+		return nil, nil, fmt.Errorf("could not collect memcached stats: %w", err)
+	}
+
+	return statsRaw, errs, nil
+}
+
+// AggregateCollectionStats sums the statistics of each collection across the nodes. The result is sorted by memory
+// used, largest first, so the collections taking up most of the bucket quota come first.
+func AggregateCollectionStats(stats []*CollectionStats) []*CollectionStats {
+	type collectionKey struct {
+		scope      string
+		collection string
+	}
+
+	aggregated := make(map[collectionKey]*CollectionStats)
+	for _, stat := range stats {
+		key := collectionKey{scope: stat.ScopeID, collection: stat.ID}
+		total, ok := aggregated[key]
+		if !ok {
+			total = &CollectionStats{ScopeID: stat.ScopeID, Scope: stat.Scope, ID: stat.ID, Name: stat.Name}
+			aggregated[key] = total
+		}
+
+		total.Items += stat.Items
+		total.MemUsed += stat.MemUsed
+		total.DiskSize += stat.DiskSize
+		total.OpsGet += stat.OpsGet
+		total.OpsStore += stat.OpsStore
+		total.OpsDelete += stat.OpsDelete
+	}
+
+	result := make([]*CollectionStats, 0, len(aggregated))
+	for _, total := range aggregated {
+		result = append(result, total)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].MemUsed != result[j].MemUsed {
+			return result[i].MemUsed > result[j].MemUsed
+		}
+
+		if result[i].Scope != result[j].Scope {
+			return result[i].Scope < result[j].Scope
+		}
+
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// parseCollectionStats parses the collection stats, whose keys are in the format <scope id>:<collection id>:<stat>
+// with hexadecimal IDs. Other stats, such as the manifest UID, are ignored.
+func parseCollectionStats(in []memcached.StatValue) ([]*CollectionStats, error) {
+	collections := make(map[string]*CollectionStats)
+	order := make([]string, 0)
+	for _, item := range in {
+		parts := strings.SplitN(item.Key, ":", 3)
+		if len(parts) != 3 || !strings.HasPrefix(parts[0], "0x") {
+			continue
+		}
+
+		id := parts[0] + ":" + parts[1]
+		collection, ok := collections[id]
+		if !ok {
+			collection = &CollectionStats{ScopeID: parts[0], ID: parts[1]}
+			collections[id] = collection
+			order = append(order, id)
+		}
+
+		var field *uint64
+		switch parts[2] {
+		case "name":
+			collection.Name = item.Val
+		case "scope_name":
+			collection.Scope = item.Val
+		case "items":
+			field = &collection.Items
+		case "mem_used":
+			field = &collection.MemUsed
+		case "disk_size":
+			field = &collection.DiskSize
+		case "ops_get":
+			field = &collection.OpsGet
+		case "ops_store":
+			field = &collection.OpsStore
+		case "ops_delete":
+			field = &collection.OpsDelete
+		}
+
+		if field == nil {
+			continue
+		}
+
+		value, err := strconv.ParseUint(item.Val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", item.Key, err)
+		}
+
+		*field = value
+	}
+
+	result := make([]*CollectionStats, 0, len(order))
+	for _, id := range order {
+		result = append(result, collections[id])
+	}
+
+	return result, nil
+}
+
+// parseScopeStats parses the scope stats, whose keys are in the format <scope id>:<stat> with a hexadecimal ID. Other
+// stats, such as the manifest UID, are ignored.
+func parseScopeStats(in []memcached.StatValue) ([]*ScopeStats, error) {
+	scopes := make(map[string]*ScopeStats)
+	order := make([]string, 0)
+	for _, item := range in {
+		parts := strings.SplitN(item.Key, ":", 2)
+		if len(parts) != 2 || !strings.HasPrefix(parts[0], "0x") {
+			continue
+		}
+
+		scope, ok := scopes[parts[0]]
+		if !ok {
+			scope = &ScopeStats{ID: parts[0]}
+			scopes[parts[0]] = scope
+			order = append(order, parts[0])
+		}
+
+		switch parts[1] {
+		case "name":
+			scope.Name = item.Val
+		case "collections":
+			count, err := strconv.ParseUint(item.Val, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for %s: %w", item.Key, err)
+			}
+
+			scope.Collections = count
+		}
+	}
+
+	result := make([]*ScopeStats, 0, len(order))
+	for _, id := range order {
+		result = append(result, scopes[id])
+	}
+
+	return result, nil
+}
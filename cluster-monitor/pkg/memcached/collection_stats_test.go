@@ -0,0 +1,90 @@
+// Copyright (C) 2022 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package memcached
+
+import (
+	"testing"
+
+	memcached "github.com/couchbase/gomemcached/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCollectionStats(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		stats, err := parseCollectionStats([]memcached.StatValue{
+			{Key: "manifest_uid", Val: "2"},
+			{Key: "0x0:0x0:name", Val: "_default"},
+			{Key: "0x0:0x0:scope_name", Val: "_default"},
+			{Key: "0x0:0x0:items", Val: "10"},
+			{Key: "0x0:0x0:mem_used", Val: "2048"},
+			{Key: "0x8:0x9:name", Val: "orders"},
+			{Key: "0x8:0x9:scope_name", Val: "shop"},
+			{Key: "0x8:0x9:items", Val: "500"},
+			{Key: "0x8:0x9:mem_used", Val: "65536"},
+			{Key: "0x8:0x9:disk_size", Val: "131072"},
+			{Key: "0x8:0x9:ops_get", Val: "7"},
+			{Key: "0x8:0x9:ops_store", Val: "6"},
+			{Key: "0x8:0x9:ops_delete", Val: "5"},
+			{Key: "0x8:0x9:maxTTL", Val: "0"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*CollectionStats{
+			{ScopeID: "0x0", Scope: "_default", ID: "0x0", Name: "_default", Items: 10, MemUsed: 2048},
+			{
+				ScopeID:   "0x8",
+				Scope:     "shop",
+				ID:        "0x9",
+				Name:      "orders",
+				Items:     500,
+				MemUsed:   65536,
+				DiskSize:  131072,
+				OpsGet:    7,
+				OpsStore:  6,
+				OpsDelete: 5,
+			},
+		}, stats)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := parseCollectionStats([]memcached.StatValue{{Key: "0x0:0x0:items", Val: "many"}})
+		require.Error(t, err)
+	})
+}
+
+func TestParseScopeStats(t *testing.T) {
+	stats, err := parseScopeStats([]memcached.StatValue{
+		{Key: "manifest_uid", Val: "2"},
+		{Key: "0x0:name", Val: "_default"},
+		{Key: "0x0:collections", Val: "1"},
+		{Key: "0x8:name", Val: "shop"},
+		{Key: "0x8:collections", Val: "3"},
+		{Key: "0x8:0x9:name", Val: "orders"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []*ScopeStats{
+		{ID: "0x0", Name: "_default", Collections: 1},
+		{ID: "0x8", Name: "shop", Collections: 3},
+	}, stats)
+
+	_, err = parseScopeStats([]memcached.StatValue{{Key: "0x0:collections", Val: "-1"}})
+	require.Error(t, err)
+}
+
+func TestAggregateCollectionStats(t *testing.T) {
+	stats := AggregateCollectionStats([]*CollectionStats{
+		{ScopeID: "0x0", Scope: "_default", ID: "0x0", Name: "_default", Items: 1, MemUsed: 10, Host: "h0"},
+		{ScopeID: "0x8", Scope: "shop", ID: "0x9", Name: "orders", Items: 5, MemUsed: 100, OpsGet: 1, Host: "h0"},
+		{ScopeID: "0x0", Scope: "_default", ID: "0x0", Name: "_default", Items: 2, MemUsed: 20, Host: "h1"},
+		{ScopeID: "0x8", Scope: "shop", ID: "0x9", Name: "orders", Items: 6, MemUsed: 200, OpsGet: 2, Host: "h1"},
+		{ScopeID: "0x8", Scope: "shop", ID: "0xa", Name: "users", Items: 3, MemUsed: 30, Host: "h1"},
+	})
+
+	require.Equal(t, []*CollectionStats{
+		{ScopeID: "0x8", Scope: "shop", ID: "0x9", Name: "orders", Items: 11, MemUsed: 300, OpsGet: 3},
+		{ScopeID: "0x0", Scope: "_default", ID: "0x0", Name: "_default", Items: 3, MemUsed: 30},
+		{ScopeID: "0x8", Scope: "shop", ID: "0xa", Name: "users", Items: 3, MemUsed: 30},
+	}, stats)
+}
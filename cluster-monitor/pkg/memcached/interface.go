@@ -17,6 +17,8 @@ type ConnIFace interface {
 	MemStats(bucket string) ([]*MemoryStats, error)
 	DefaultStats(bucket string) ([]*DefStats, error)
 	CheckpointStats(host, bucket string) (BucketCheckpointStats, error)
+	CollectionStats(bucket string) ([]*CollectionStats, error)
+	ScopeStats(bucket string) ([]*ScopeStats, error)
 	Hosts() []string
 	Close() error
 }
@@ -7,36 +7,77 @@ package internal
 
 import (
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	memcached "github.com/couchbase/gomemcached/client"
 	"github.com/couchbase/tools-common/errdefs"
 	"go.uber.org/zap"
 )
 
-type mcClientMap map[string]*memcached.Client
+const (
+	// DefaultMaxConnsPerHost is how many connections to a single node can be in use at once.
+	DefaultMaxConnsPerHost = 4
+	// DefaultIdleTimeout is how long a connection can go unused before it is closed rather than reused.
+	DefaultIdleTimeout = time.Minute
+	// DefaultIOTimeout bounds each use of a connection so a node that stops responding does not block callers forever.
+	DefaultIOTimeout = 30 * time.Second
+)
+
+// ErrClosed is returned when a client is requested from a ClientManager that has been closed.
+var ErrClosed = errors.New("client manager is closed")
 
+// ClientManager is a pool of memcached connections to the KV nodes of a cluster. Each node has its own set of
+// connections so nodes can be talked to concurrently, connections that are broken by an I/O error are replaced and
+// connections that are left idle are closed.
 type ClientManager struct {
 	hosts     []string
 	user      string
-	pasword   string
+	password  string
 	tlsConfig *tls.Config
 
+	maxConnsPerHost int
+	idleTimeout     time.Duration
+	ioTimeout       time.Duration
+
+	// factory and now are replaced in the tests.
 	factory mcClientFactory
+	now     func() time.Time
 
-	clients    mcClientMap
-	clientsMux sync.Mutex
+	poolsMux sync.Mutex
+	pools    map[string]*hostPool
+	closed   bool
+}
+
+// hostPool holds the connections to a single node.
+type hostPool struct {
+	// slots limits the number of connections to the node that are in use at once.
+	slots chan struct{}
+
+	lock sync.Mutex
+	// idle are the connections that are not in use, the most recently used is last.
+	idle []idleClient
+}
+
+type idleClient struct {
+	client *memcached.Client
+	since  time.Time
 }
 
 func NewClientManager(hosts []string, user, password string, cfg *tls.Config) *ClientManager {
 	return &ClientManager{
-		hosts:     hosts,
-		user:      user,
-		pasword:   password,
-		tlsConfig: cfg,
-		factory:   defaultMCClientFactory{},
-		clients:   make(mcClientMap),
+		hosts:           hosts,
+		user:            user,
+		password:        password,
+		tlsConfig:       cfg,
+		maxConnsPerHost: DefaultMaxConnsPerHost,
+		idleTimeout:     DefaultIdleTimeout,
+		ioTimeout:       DefaultIOTimeout,
+		factory:         defaultMCClientFactory{},
+		now:             time.Now,
+		pools:           make(map[string]*hostPool),
 	}
 }
 
@@ -49,38 +90,155 @@ func (m *ClientManager) Hosts() []string {
 //
 // fn is guaranteed to be executed synchronously (i.e. ClientForNode will not return until fn does), and ClientForNode
 // will ensure that no other caller can use the client until fn returns. However, it is not safe to continue using the
-// client after fn returns.
+// client after fn returns. Callers for different nodes, and up to the per node connection limit for the same node, run
+// concurrently.
+//
+// If fn fails because a reused connection turned out to be broken, fn is called once more with a new connection, so it
+// must be safe to retry.
 //
 // ClientForNode will return an error if it fails to create a client, or if fn returns one (in which case it will be
 // returned verbatim).
 func (m *ClientManager) ClientForNode(host string, fn func(client *memcached.Client) error) error {
-	m.clientsMux.Lock()
-	defer m.clientsMux.Unlock()
+	pool, err := m.pool(host)
+	if err != nil {
+		return err
+	}
+
+	pool.slots <- struct{}{}
+	defer func() { <-pool.slots }()
+
+	client, reused, err := m.get(pool, host)
+	if err != nil {
+		return err
+	}
 
-	client, ok := m.clients[host]
+	err = m.use(pool, host, client, fn)
+	// the node may have dropped the connection while it was idle, which is only found out when using it
+	if err == nil || !reused || client.IsHealthy() {
+		return err
+	}
+
+	zap.S().Debugw("(Memcached) Retrying with a new client after the reused one broke", "host", host, "err", err)
+	client, err = m.create(host)
+	if err != nil {
+		return err
+	}
+
+	return m.use(pool, host, client, fn)
+}
+
+// pool returns the pool of connections to the host, creating it if necessary.
+func (m *ClientManager) pool(host string) (*hostPool, error) {
+	m.poolsMux.Lock()
+	defer m.poolsMux.Unlock()
+
+	if m.closed {
+		return nil, ErrClosed
+	}
+
+	pool, ok := m.pools[host]
 	if !ok {
-		zap.S().Debugw("(Memcached) Creating new client", "host", host)
-		var err error
-		client, err = m.factory.CreateClient(host, m.user, m.pasword, m.tlsConfig)
-		if err != nil {
-			return fmt.Errorf("could not create memcached client for %s: %w", host, err)
+		pool = &hostPool{slots: make(chan struct{}, m.maxConnsPerHost)}
+		m.pools[host] = pool
+	}
+
+	return pool, nil
+}
+
+// get returns an idle connection to the host if there is a usable one, otherwise it creates a new one. The idle
+// connections that are broken or have been idle for too long are closed.
+func (m *ClientManager) get(pool *hostPool, host string) (*memcached.Client, bool, error) {
+	pool.lock.Lock()
+	now := m.now()
+	var client *memcached.Client
+	for len(pool.idle) > 0 && client == nil {
+		last := pool.idle[len(pool.idle)-1]
+		pool.idle = pool.idle[:len(pool.idle)-1]
+
+		if !last.client.IsHealthy() || now.Sub(last.since) >= m.idleTimeout {
+			closeClient(host, last.client)
+			continue
 		}
-		m.clients[host] = client
+
+		client = last.client
+	}
+	pool.lock.Unlock()
+
+	if client != nil {
+		return client, true, nil
+	}
+
+	client, err := m.create(host)
+	return client, false, err
+}
+
+func (m *ClientManager) create(host string) (*memcached.Client, error) {
+	zap.S().Debugw("(Memcached) Creating new client", "host", host)
+	client, err := m.factory.CreateClient(host, m.user, m.password, m.tlsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not create memcached client for %s: %w", host, err)
+	}
+
+	return client, nil
+}
+
+// use calls fn with the client, bounding it by the I/O timeout, and then gives the client back to the pool unless it
+// broke while being used or the manager was closed in the meantime.
+func (m *ClientManager) use(pool *hostPool, host string, client *memcached.Client,
+	fn func(client *memcached.Client) error) error {
+	if m.ioTimeout > 0 {
+		client.SetDeadline(m.now().Add(m.ioTimeout))
+	}
+
+	err := fn(client)
+
+	if !client.IsHealthy() {
+		zap.S().Debugw("(Memcached) Discarding broken client", "host", host, "err", err)
+		closeClient(host, client)
+		return err
 	}
 
-	return fn(client)
+	// the pools lock is held so the manager cannot be closed before the client is back in the pool
+	m.poolsMux.Lock()
+	defer m.poolsMux.Unlock()
+
+	if m.closed {
+		closeClient(host, client)
+		return err
+	}
+
+	pool.lock.Lock()
+	pool.idle = append(pool.idle, idleClient{client: client, since: m.now()})
+	pool.lock.Unlock()
+
+	return err
+}
+
+func closeClient(host string, client *memcached.Client) {
+	if err := client.Close(); err != nil {
+		zap.S().Debugw("(Memcached) Could not close client", "host", host, "err", err)
+	}
 }
 
 // Close shuts down all memcached clients that this client manager has open, returning an error if any of their
-// Close() methods return an error.
+// Close() methods return an error. Clients that are in use are closed once they are given back.
 func (m *ClientManager) Close() error {
-	m.clientsMux.Lock()
-	defer m.clientsMux.Unlock()
-	zap.S().Debugw("(Memcached) Shutting down all clients", "n", len(m.clients))
+	m.poolsMux.Lock()
+	defer m.poolsMux.Unlock()
+
+	m.closed = true
 
 	errs := new(errdefs.MultiError)
-	for _, client := range m.clients {
-		errs.Add(client.Close())
+	for host, pool := range m.pools {
+		pool.lock.Lock()
+		zap.S().Debugw("(Memcached) Shutting down idle clients", "host", host, "n", len(pool.idle))
+		for _, idle := range pool.idle {
+			errs.Add(idle.client.Close())
+		}
+
+		pool.idle = nil
+		pool.lock.Unlock()
 	}
+
 	return errs.ErrOrNil()
 }
@@ -8,58 +8,69 @@ package internal
 import (
 	"crypto/tls"
 	"fmt"
+	"net"
 	"sync"
 	"testing"
 	"time"
 
 	memcached "github.com/couchbase/gomemcached/client"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/memcached/internal/mocks"
 )
 
-func TestClientCreationAndReuse(t *testing.T) {
+// newTestClient returns a memcached client over an in-memory connection that nothing is listening on, it can be used
+// as long as no requests are sent through it.
+func newTestClient(t *testing.T) *memcached.Client {
+	server, conn := net.Pipe()
+	t.Cleanup(func() { server.Close() })
+
+	client, err := memcached.Wrap(conn)
+	require.NoError(t, err)
+	return client
+}
+
+// newTestManager returns a client manager whose factory creates a new test client every time it is called.
+func newTestManager(t *testing.T) (*ClientManager, *mocks.McClientFactory) {
 	factory := new(mocks.McClientFactory)
+	factory.On("CreateClient", mock.Anything, "", "", (*tls.Config)(nil)).
+		Return(func(string, string, string, *tls.Config) *memcached.Client { return newTestClient(t) }, nil)
+
 	md := NewClientManager([]string{"N0", "N1"}, "", "", nil)
 	md.factory = factory
-	require.Len(t, md.clients, 0)
+	t.Cleanup(func() { md.Close() })
 
-	factory.On("CreateClient", "N0", "", "", (*tls.Config)(nil)).
-		Return(nil, nil).Once()
+	return md, factory
+}
 
-	err := md.ClientForNode("N0", func(_ *memcached.Client) error {
-		return nil
-	})
-	require.NoError(t, err)
-	require.Len(t, md.clients, 1)
-	factory.AssertExpectations(t)
+func noop(_ *memcached.Client) error {
+	return nil
+}
 
-	// Now check that reusing a client doesn't create another
-	err = md.ClientForNode("N0", func(_ *memcached.Client) error {
-		return nil
-	})
-	require.NoError(t, err)
-	require.Len(t, md.clients, 1)
-	factory.AssertExpectations(t)
+func TestClientCreationAndReuse(t *testing.T) {
+	md, factory := newTestManager(t)
+	require.Len(t, md.pools, 0)
 
-	factory.On("CreateClient", "N1", "", "", (*tls.Config)(nil)).
-		Return(nil, nil).Once()
+	require.NoError(t, md.ClientForNode("N0", noop))
+	require.Len(t, md.pools, 1)
+	factory.AssertNumberOfCalls(t, "CreateClient", 1)
 
-	err = md.ClientForNode("N1", func(_ *memcached.Client) error {
-		return nil
-	})
-	require.NoError(t, err)
-	require.Len(t, md.clients, 2)
-	factory.AssertExpectations(t)
+	// Now check that reusing a client doesn't create another
+	require.NoError(t, md.ClientForNode("N0", noop))
+	require.Len(t, md.pools["N0"].idle, 1)
+	factory.AssertNumberOfCalls(t, "CreateClient", 1)
 
-	err = md.ClientForNode("N1", func(_ *memcached.Client) error {
-		return nil
-	})
-	require.NoError(t, err)
-	require.Len(t, md.clients, 2)
-	factory.AssertExpectations(t)
+	require.NoError(t, md.ClientForNode("N1", noop))
+	require.Len(t, md.pools, 2)
+	factory.AssertNumberOfCalls(t, "CreateClient", 2)
+
+	require.NoError(t, md.ClientForNode("N1", noop))
+	factory.AssertNumberOfCalls(t, "CreateClient", 2)
 
-	// Cannot test md.Close() because we don't have a mock for memcached.Client
+	require.NoError(t, md.Close())
+	require.Empty(t, md.pools["N0"].idle)
+	require.ErrorIs(t, md.ClientForNode("N0", noop), ErrClosed)
 }
 
 func TestClientCreationHandlesErrors(t *testing.T) {
@@ -78,14 +89,12 @@ func TestClientCreationHandlesErrors(t *testing.T) {
 }
 
 func TestClientForNodeConcurrency(t *testing.T) {
-	factory := new(mocks.McClientFactory)
-	md := NewClientManager([]string{"N0", "N1"}, "", "", nil)
-	md.factory = factory
-	factory.On("CreateClient", "N0", "", "", (*tls.Config)(nil)).Return(nil, nil)
+	md, factory := newTestManager(t)
+	md.maxConnsPerHost = 2
 
-	guard := newConcurrencyGuard(t, 1)
+	guard := newConcurrencyGuard(t, 2)
 	wg := sync.WaitGroup{}
-	for i := 0; i < 3; i++ {
+	for i := 0; i < 5; i++ {
 		wg.Add(1)
 		go md.ClientForNode("N0", func(_ *memcached.Client) error { //nolint:errcheck,unparam
 			defer wg.Done()
@@ -96,4 +105,101 @@ func TestClientForNodeConcurrency(t *testing.T) {
 		})
 	}
 	wg.Wait()
+
+	// the connections are reused once they are given back so there are never more than the limit
+	require.LessOrEqual(t, len(factory.Calls), 2)
+}
+
+func TestClientForNodeHostsInParallel(t *testing.T) {
+	md, _ := newTestManager(t)
+	md.maxConnsPerHost = 1
+
+	// each host waits for the other so this only finishes if they are used at the same time
+	started := map[string]chan struct{}{"N0": make(chan struct{}), "N1": make(chan struct{})}
+	other := map[string]string{"N0": "N1", "N1": "N0"}
+
+	errs := make(chan error, 2)
+	for host := range started {
+		host := host
+		go func() {
+			errs <- md.ClientForNode(host, func(_ *memcached.Client) error {
+				close(started[host])
+				select {
+				case <-started[other[host]]:
+					return nil
+				case <-time.After(5 * time.Second):
+					return fmt.Errorf("%s was not used concurrently", other[host])
+				}
+			})
+		}()
+	}
+
+	require.NoError(t, <-errs)
+	require.NoError(t, <-errs)
+}
+
+func TestClientForNodeReplacesBrokenClients(t *testing.T) {
+	md, factory := newTestManager(t)
+
+	// hijacking the connection marks the client as unhealthy, as an I/O error would
+	err := md.ClientForNode("N0", func(client *memcached.Client) error {
+		client.Hijack()
+		return nil
+	})
+	require.NoError(t, err)
+	require.Empty(t, md.pools["N0"].idle)
+
+	require.NoError(t, md.ClientForNode("N0", noop))
+	factory.AssertNumberOfCalls(t, "CreateClient", 2)
+	require.Len(t, md.pools["N0"].idle, 1)
+}
+
+func TestClientForNodeRetriesBrokenReusedClient(t *testing.T) {
+	md, factory := newTestManager(t)
+
+	require.NoError(t, md.ClientForNode("N0", noop))
+
+	var used []*memcached.Client
+	err := md.ClientForNode("N0", func(client *memcached.Client) error {
+		used = append(used, client)
+		if len(used) == 1 {
+			client.Hijack()
+			return fmt.Errorf("connection reset by peer")
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, used, 2)
+	require.NotSame(t, used[0], used[1])
+	factory.AssertNumberOfCalls(t, "CreateClient", 2)
+
+	t.Run("new-clients-not-retried", func(t *testing.T) {
+		calls := 0
+		err := md.ClientForNode("N1", func(client *memcached.Client) error {
+			calls++
+			client.Hijack()
+			return fmt.Errorf("connection reset by peer")
+		})
+		require.Error(t, err)
+		require.Equal(t, 1, calls)
+	})
+}
+
+func TestClientForNodeIdleTimeout(t *testing.T) {
+	md, factory := newTestManager(t)
+
+	now := time.Now()
+	md.now = func() time.Time { return now }
+
+	require.NoError(t, md.ClientForNode("N0", noop))
+
+	now = now.Add(DefaultIdleTimeout / 2)
+	require.NoError(t, md.ClientForNode("N0", noop))
+	factory.AssertNumberOfCalls(t, "CreateClient", 1)
+
+	now = now.Add(DefaultIdleTimeout)
+	require.NoError(t, md.ClientForNode("N0", noop))
+	factory.AssertNumberOfCalls(t, "CreateClient", 2)
+	require.Len(t, md.pools["N0"].idle, 1)
 }
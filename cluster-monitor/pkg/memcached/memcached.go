@@ -6,6 +6,7 @@
 package memcached
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/couchbase/tools-common/netutil"
@@ -22,10 +23,11 @@ type MemDClient struct {
 	manager *internal.ClientManager
 }
 
-// NewMemcachedClient creates a new memcached client for a cluster.
-func NewMemcachedClient(cluster *values.CouchbaseCluster) (*MemDClient, error) {
-	restClient, err := couchbase.NewClient(cluster.NodesSummary.GetHosts(), cluster.User, cluster.Password,
-		cluster.GetTLSConfig(), false)
+// NewMemcachedClient creates a new memcached client for a cluster. The data nodes are found using the cluster REST
+// client from clients.
+func NewMemcachedClient(ctx context.Context, cluster *values.CouchbaseCluster,
+	clients *couchbase.ClientCache) (*MemDClient, error) {
+	restClient, err := clients.GetClient(ctx, cluster)
 	if err != nil {
 		return nil, fmt.Errorf("could not create client to communicate with data nodes: %w", err)
 	}
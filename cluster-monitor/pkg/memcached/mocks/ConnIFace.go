@@ -5,8 +5,6 @@ package mocks
 import (
 	memcached "github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/memcached"
 	mock "github.com/stretchr/testify/mock"
-
-	values "github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
 )
 
 // ConnIFace is an autogenerated mock type for the ConnIFace type
@@ -51,6 +49,29 @@ func (_m *ConnIFace) Close() error {
 	return r0
 }
 
+// CollectionStats provides a mock function with given fields: bucket
+func (_m *ConnIFace) CollectionStats(bucket string) ([]*memcached.CollectionStats, error) {
+	ret := _m.Called(bucket)
+
+	var r0 []*memcached.CollectionStats
+	if rf, ok := ret.Get(0).(func(string) []*memcached.CollectionStats); ok {
+		r0 = rf(bucket)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*memcached.CollectionStats)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(bucket)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DCPStats provides a mock function with given fields: bucket
 func (_m *ConnIFace) DCPStats(bucket string) ([]*memcached.DCPMemStats, error) {
 	ret := _m.Called(bucket)
@@ -135,3 +156,26 @@ func (_m *ConnIFace) MemStats(bucket string) ([]*memcached.MemoryStats, error) {
 
 	return r0, r1
 }
+
+// ScopeStats provides a mock function with given fields: bucket
+func (_m *ConnIFace) ScopeStats(bucket string) ([]*memcached.ScopeStats, error) {
+	ret := _m.Called(bucket)
+
+	var r0 []*memcached.ScopeStats
+	if rf, ok := ret.Get(0).(func(string) []*memcached.ScopeStats); ok {
+		r0 = rf(bucket)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*memcached.ScopeStats)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(bucket)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
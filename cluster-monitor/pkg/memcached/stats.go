@@ -7,40 +7,101 @@ package memcached
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 
 	memcached "github.com/couchbase/gomemcached/client"
-	"github.com/couchbase/tools-common/errdefs"
 )
 
-// getStats is reused by any function that needs to make a cbstats call.
-func (m *MemDClient) getStats(key string, bucket string) (map[string][]memcached.StatValue, error) {
-	stats := make(map[string][]memcached.StatValue)
-	errs := &errdefs.MultiError{
-		Prefix: "failed to get stats for some nodes: ",
+// NodesError is returned when some of the nodes could not give their stats. The stats of the other nodes are still
+// returned alongside it by the functions that say so.
+type NodesError struct {
+	// Errors are the errors of each node that failed keyed by its host.
+	Errors map[string]error
+}
+
+// Hosts returns the hosts of the nodes that failed in order.
+func (e *NodesError) Hosts() []string {
+	hosts := make([]string, 0, len(e.Errors))
+	for host := range e.Errors {
+		hosts = append(hosts, host)
+	}
+
+	sort.Strings(hosts)
+	return hosts
+}
+
+func (e *NodesError) Error() string {
+	errs := make([]string, 0, len(e.Errors))
+	for _, host := range e.Hosts() {
+		errs = append(errs, fmt.Sprintf("%s: %v", host, e.Errors[host]))
 	}
 
+	return "failed to get stats for some nodes: " + strings.Join(errs, "; ")
+}
+
+// add records the error of the node, creating the NodesError if necessary.
+func (e *NodesError) add(host string, err error) *NodesError {
+	if e == nil {
+		e = &NodesError{Errors: make(map[string]error)}
+	}
+
+	e.Errors[host] = err
+	return e
+}
+
+// getStats is reused by any function that needs to make a cbstats call. The nodes are asked for their stats in
+// parallel. If some of the nodes fail a *NodesError is returned together with the stats of the others.
+func (m *MemDClient) getStats(key string, bucket string) (map[string][]memcached.StatValue, error) {
+	var (
+		stats = make(map[string][]memcached.StatValue)
+		errs  *NodesError
+		// lock protects stats and errs which are written by the goroutines of every node
+		lock sync.Mutex
+		wg   sync.WaitGroup
+	)
+
 	for _, host := range m.manager.Hosts() {
-		err := m.manager.ClientForNode(host, func(client *memcached.Client) error {
-			_, err := client.SelectBucket(bucket)
-			if err != nil {
-				return fmt.Errorf("could not select bucket: %w", err)
-			}
+		wg.Add(1)
+		go func(host string) {
+			defer wg.Done()
 
-			stat, err := client.Stats(key)
-			if err != nil {
-				return fmt.Errorf("could not collect memcached stats: %w", err)
-			}
+			var stat []memcached.StatValue
+			err := m.manager.ClientForNode(host, func(client *memcached.Client) error {
+				_, err := client.SelectBucket(bucket)
+				if err != nil {
+					return fmt.Errorf("could not select bucket: %w", err)
+				}
+
+				stat, err = client.Stats(key)
+				if err != nil {
+					return fmt.Errorf("could not collect memcached stats: %w", err)
+				}
+
+				if stat == nil {
+					return fmt.Errorf("no %s stats available: %w", key, err)
+				}
 
-			if stat == nil {
-				return fmt.Errorf("no %s stats available: %w", key, err)
+				return nil
+			})
+
+			lock.Lock()
+			defer lock.Unlock()
+
+			if err != nil {
+				errs = errs.add(host, err)
+				return
 			}
 
 			stats[host] = stat
-			return nil
-		})
-		if err != nil {
-			errs.Add(fmt.Errorf("failed to collect stats from %s: %w", host, err))
-		}
+		}(host)
 	}
-	return stats, errs.ErrOrNil()
+
+	wg.Wait()
+	if errs != nil {
+		return stats, errs
+	}
+
+	return stats, nil
 }
@@ -0,0 +1,23 @@
+// Copyright (C) 2022 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package memcached
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodesError(t *testing.T) {
+	var errs *NodesError
+	errs = errs.add("h1:11210", fmt.Errorf("connection refused"))
+	errs = errs.add("h0:11210", fmt.Errorf("timeout"))
+
+	require.Equal(t, []string{"h0:11210", "h1:11210"}, errs.Hosts())
+	require.EqualError(t, errs,
+		"failed to get stats for some nodes: h0:11210: timeout; h1:11210: connection refused")
+}
@@ -0,0 +1,116 @@
+// Copyright (C) 2022 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package scheduler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/storage"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"go.uber.org/zap"
+)
+
+// FrequencySelector picks the frequency a scheduler uses out of the ones a cluster overrides, zero meaning the cluster
+// uses the default one.
+type FrequencySelector func(frequencies *values.ClusterFrequencies) time.Duration
+
+// Scheduler decides when each cluster is due for a monitor that runs on a single ticker, honouring both the default
+// frequency and the ones the clusters override. The ticker runs at the shortest of the frequencies, so a cluster whose
+// frequency is not a multiple of the tick runs up to half a tick early or almost a whole tick late.
+type Scheduler struct {
+	store    storage.Store
+	selector FrequencySelector
+	// logPrefix is the prefix of the monitor using the scheduler, for example "(Heart Monitor)".
+	logPrefix string
+
+	// frequency is the default time between runs, clusters can override it.
+	frequency     time.Duration
+	frequencyLock sync.Mutex
+
+	// lastRun is when each cluster was last due, it is only used by the monitor loop.
+	lastRun map[string]time.Time
+}
+
+// NewScheduler creates a scheduler that takes the cluster frequencies from the store using selector.
+func NewScheduler(store storage.Store, selector FrequencySelector, logPrefix string) *Scheduler {
+	return &Scheduler{store: store, selector: selector, logPrefix: logPrefix}
+}
+
+// SetFrequency changes the default frequency, it applies from the next tick.
+func (s *Scheduler) SetFrequency(frequency time.Duration) {
+	s.frequencyLock.Lock()
+	defer s.frequencyLock.Unlock()
+	s.frequency = frequency
+}
+
+// Frequency returns the default frequency.
+func (s *Scheduler) Frequency() time.Duration {
+	s.frequencyLock.Lock()
+	defer s.frequencyLock.Unlock()
+	return s.frequency
+}
+
+// TickInterval is how often the monitor loop has to run to honour both the default and the cluster frequencies.
+func (s *Scheduler) TickInterval() time.Duration {
+	tick := s.Frequency()
+	for _, frequency := range s.clusterFrequencies() {
+		if frequency < tick {
+			tick = frequency
+		}
+	}
+
+	return tick
+}
+
+// DueClusters returns the clusters whose frequency has elapsed since they were last due. It is only to be called by
+// the monitor loop, once per tick.
+func (s *Scheduler) DueClusters(clusters []*values.CouchbaseCluster, now time.Time,
+	tick time.Duration) []*values.CouchbaseCluster {
+	frequency := s.Frequency()
+	overrides := s.clusterFrequencies()
+
+	due := make([]*values.CouchbaseCluster, 0, len(clusters))
+	lastRun := make(map[string]time.Time, len(clusters))
+	for _, cluster := range clusters {
+		clusterFrequency, ok := overrides[cluster.UUID]
+		if !ok {
+			clusterFrequency = frequency
+		}
+
+		// ticks are not exact so allow for half a tick so clusters that are just about due are not skipped
+		if last, ok := s.lastRun[cluster.UUID]; ok && now.Sub(last) < clusterFrequency-tick/2 {
+			lastRun[cluster.UUID] = last
+			continue
+		}
+
+		lastRun[cluster.UUID] = now
+		due = append(due, cluster)
+	}
+
+	s.lastRun = lastRun
+	return due
+}
+
+// clusterFrequencies returns the frequencies of the clusters that override the default one. If they cannot be retrieved
+// the default is used for all clusters.
+func (s *Scheduler) clusterFrequencies() map[string]time.Duration {
+	overrides, err := s.store.GetClusterFrequencies()
+	if err != nil {
+		zap.S().Warnw(s.logPrefix+" Could not get cluster frequencies", "err", err)
+		return nil
+	}
+
+	frequencies := make(map[string]time.Duration, len(overrides))
+	for _, override := range overrides {
+		if frequency := s.selector(override); frequency > 0 {
+			frequencies[override.ClusterUUID] = frequency
+		}
+	}
+
+	return frequencies
+}
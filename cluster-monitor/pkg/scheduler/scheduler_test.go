@@ -0,0 +1,57 @@
+// Copyright (C) 2022 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package scheduler
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/storage/sqlite"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulerDueClusters(t *testing.T) {
+	store, err := sqlite.NewSQLiteDB(filepath.Join(t.TempDir(), "store.sqlite"), "key")
+	require.NoError(t, err)
+	defer store.Close()
+
+	require.NoError(t, store.SetClusterFrequencies(&values.ClusterFrequencies{
+		ClusterUUID: "critical",
+		Heart:       10 * time.Second,
+		Status:      time.Hour,
+	}))
+
+	clusters := []*values.CouchbaseCluster{{UUID: "critical"}, {UUID: "other"}}
+
+	scheduler := NewScheduler(store, func(frequencies *values.ClusterFrequencies) time.Duration {
+		return frequencies.Heart
+	}, "(Test)")
+	scheduler.SetFrequency(time.Minute)
+	require.Equal(t, 10*time.Second, scheduler.TickInterval())
+
+	dueUUIDs := func(now time.Time) []string {
+		uuids := make([]string, 0)
+		for _, cluster := range scheduler.DueClusters(clusters, now, 10*time.Second) {
+			uuids = append(uuids, cluster.UUID)
+		}
+
+		return uuids
+	}
+
+	start := time.Now()
+	require.Equal(t, []string{"critical", "other"}, dueUUIDs(start))
+	// ticks can be slightly early so the critical cluster should still be due
+	require.Equal(t, []string{"critical"}, dueUUIDs(start.Add(9900*time.Millisecond)))
+	require.Equal(t, []string{"critical"}, dueUUIDs(start.Add(20*time.Second)))
+	require.Equal(t, []string{"critical", "other"}, dueUUIDs(start.Add(time.Minute)))
+
+	// changing the default frequency applies from the next tick
+	scheduler.SetFrequency(20 * time.Second)
+	require.Equal(t, []string{"critical", "other"}, dueUUIDs(start.Add(80*time.Second)))
+}
@@ -0,0 +1,212 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package status
+
+import (
+	"strings"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/couchbase/tools-common/cbvalue"
+)
+
+// recommendedDataNodes is the number of data nodes recommended for each number of replicas. Replica counts not in the
+// map only need enough data nodes to hold all the copies.
+var recommendedDataNodes = map[uint64]int{2: 5, 3: 10}
+
+const (
+	defaultVBucketCount      = 1024
+	defaultMacOSVBucketCount = 64
+)
+
+const (
+	// residentRatioWarn and residentRatioAlert are the percentages of the active items in memory below which the
+	// resident ratio is too low.
+	residentRatioWarn  = 10
+	residentRatioAlert = 5
+
+	// bucketMemoryUsageAlert is the percentage of the quota the memory used by the bucket should stay below, the
+	// bucket is only reported once it stays above it for more than bucketMemoryUsageSamples stats samples, which are a
+	// second apart.
+	bucketMemoryUsageAlert   = 95
+	bucketMemoryUsageSamples = 5
+
+	// maxTTLLimit is the max TTL, in seconds, from which MB-37643 applies it from when memcached started.
+	maxTTLLimit = 30 * 24 * 60 * 60
+	// maxTTLMinVersion and maxTTLFixedVersion are the range of versions affected by MB-37643.
+	maxTTLMinVersion   = cbvalue.Version("5.5.0")
+	maxTTLFixedVersion = cbvalue.Version("6.0.4")
+)
+
+// knownStorageEngines are the storage engines a bucket can use. The engine is empty for memcached buckets and on
+// versions that only support couchstore.
+var knownStorageEngines = map[string]bool{"": true, "couchstore": true, "magma": true, "ephemeral": true}
+
+// replicaVBucketNumberCheck alerts if there are not enough data nodes to hold every replica of the bucket and warns if
+// there are fewer than recommended for its number of replicas.
+func replicaVBucketNumberCheck(bucket values.BucketSummary, resources *clusterResources) (*values.CheckerResult,
+	error) {
+	var dataNodes int
+	for _, node := range resources.cluster.NodesSummary {
+		if node.HasService("kv") && node.ClusterMembership == "active" {
+			dataNodes++
+		}
+	}
+
+	value := map[string]interface{}{"num_replicas": bucket.NumReplicas, "data_nodes": dataNodes}
+	status := values.GoodCheckerStatus
+	recommended, ok := recommendedDataNodes[bucket.NumReplicas]
+	switch {
+	case uint64(dataNodes) < bucket.NumReplicas+1:
+		status = values.AlertCheckerStatus
+	case ok && dataNodes < recommended:
+		status = values.WarnCheckerStatus
+		value["recommended_data_nodes"] = recommended
+	}
+
+	return newResult(values.CheckReplicaVBucketNumber, status, value)
+}
+
+// nonDefaultVBucketCountCheck warns if the bucket does not have the default number of vBuckets for the platform the
+// cluster runs on. Buckets without vBuckets, such as memcached ones, are always good.
+func nonDefaultVBucketCountCheck(bucket values.BucketSummary, resources *clusterResources) (*values.CheckerResult,
+	error) {
+	poolsBucket, err := resources.getPoolsBucket(bucket.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	count := len(poolsBucket.VBucketServerMap.VBucketMap)
+	if count == 0 {
+		return newResult(values.CheckNonDefaultVBucketCount, values.GoodCheckerStatus, nil)
+	}
+
+	expected := defaultVBucketCount
+	for _, node := range resources.cluster.NodesSummary {
+		if strings.Contains(node.OS, "darwin") {
+			expected = defaultMacOSVBucketCount
+			break
+		}
+	}
+
+	status := values.GoodCheckerStatus
+	if count != expected {
+		status = values.WarnCheckerStatus
+	}
+
+	return newResult(values.CheckNonDefaultVBucketCount, status,
+		map[string]int{"vbuckets": count, "default": expected})
+}
+
+// residentRatioCheck warns or alerts if the average of the bucket's resident ratio samples is too low. Buckets without
+// any samples, such as memcached ones, are always good.
+func residentRatioCheck(bucket values.BucketSummary, resources *clusterResources) (*values.CheckerResult, error) {
+	stats, err := resources.getBucketStats(bucket.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(stats.VbActiveRatio) == 0 {
+		return newResult(values.CheckResidentRatio, values.GoodCheckerStatus, nil)
+	}
+
+	var total float64
+	for _, ratio := range stats.VbActiveRatio {
+		total += ratio
+	}
+
+	ratio := total / float64(len(stats.VbActiveRatio))
+
+	status := values.GoodCheckerStatus
+	switch {
+	case ratio < residentRatioAlert:
+		status = values.AlertCheckerStatus
+	case ratio < residentRatioWarn:
+		status = values.WarnCheckerStatus
+	}
+
+	return newResult(values.CheckResidentRatio, status, map[string]float64{"resident_ratio": ratio})
+}
+
+// bucketMemoryUsageCheck alerts if the bucket's memory usage has been at or above bucketMemoryUsageAlert percent of
+// its quota for more than bucketMemoryUsageSamples of the most recent stats samples.
+func bucketMemoryUsageCheck(bucket values.BucketSummary, resources *clusterResources) (*values.CheckerResult,
+	error) {
+	if bucket.Quota == 0 {
+		return newResult(values.CheckBucketMemoryUsage, values.GoodCheckerStatus, nil)
+	}
+
+	stats, err := resources.getBucketStats(bucket.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var over int
+	for i := len(stats.MemUsed) - 1; i >= 0; i-- {
+		if stats.MemUsed[i]/float64(bucket.Quota)*100 < bucketMemoryUsageAlert {
+			break
+		}
+
+		over++
+	}
+
+	status := values.GoodCheckerStatus
+	if over > bucketMemoryUsageSamples {
+		status = values.AlertCheckerStatus
+	}
+
+	value := map[string]interface{}{"quota": bucket.Quota, "seconds_over_limit": over}
+	if len(stats.MemUsed) > 0 {
+		memUsed := stats.MemUsed[len(stats.MemUsed)-1]
+		value["mem_used"] = memUsed
+		value["usage_percent"] = percentage(uint64(memUsed), bucket.Quota)
+	}
+
+	return newResult(values.CheckBucketMemoryUsage, status, value)
+}
+
+// unknownStorageEngineCheck warns if the bucket uses a storage engine that is not one of the known ones.
+func unknownStorageEngineCheck(bucket values.BucketSummary, resources *clusterResources) (*values.CheckerResult,
+	error) {
+	poolsBucket, err := resources.getPoolsBucket(bucket.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	status := values.GoodCheckerStatus
+	if !knownStorageEngines[poolsBucket.StorageEngine] {
+		status = values.WarnCheckerStatus
+	}
+
+	return newResult(values.CheckUnknownStorageEngine, status,
+		map[string]string{"storage_engine": poolsBucket.StorageEngine})
+}
+
+// maxTTLCheck alerts if the bucket has a max TTL of 30 days or more and any of the nodes runs a version affected by
+// MB-37643, as all the documents in the bucket would expire at the same time.
+func maxTTLCheck(bucket values.BucketSummary, resources *clusterResources) (*values.CheckerResult, error) {
+	poolsBucket, err := resources.getPoolsBucket(bucket.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	affected := make([]string, 0)
+	for _, node := range resources.cluster.NodesSummary {
+		version := cbvalue.Version(strings.SplitN(node.Version, "-", 2)[0])
+		if node.Version != "" && version.AtLeast(maxTTLMinVersion) && version.Older(maxTTLFixedVersion) {
+			affected = append(affected, node.NodeUUID)
+		}
+	}
+
+	status := values.GoodCheckerStatus
+	value := map[string]interface{}{"max_ttl": poolsBucket.MaxTTL}
+	if poolsBucket.MaxTTL >= maxTTLLimit && len(affected) > 0 {
+		status = values.AlertCheckerStatus
+		value["affected_nodes"] = affected
+	}
+
+	return newResult(values.CheckMaxTTL, status, value)
+}
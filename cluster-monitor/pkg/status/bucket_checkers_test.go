@@ -0,0 +1,226 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package status
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/couchbase"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/couchbase/mocks"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// dataNodesCluster returns a cluster with the given number of active data nodes plus a query node.
+func dataNodesCluster(dataNodes int, os string) *values.CouchbaseCluster {
+	cluster := &values.CouchbaseCluster{
+		NodesSummary: values.NodesSummary{
+			{NodeUUID: "query", Services: []string{"n1ql"}, ClusterMembership: "active", OS: os},
+			{NodeUUID: "added", Services: []string{"kv"}, ClusterMembership: "inactiveAdded", OS: os},
+		},
+	}
+
+	for i := 0; i < dataNodes; i++ {
+		cluster.NodesSummary = append(cluster.NodesSummary, values.NodeSummary{
+			Services:          []string{"kv", "index"},
+			ClusterMembership: "active",
+			OS:                os,
+		})
+	}
+
+	return cluster
+}
+
+func TestReplicaVBucketNumberCheck(t *testing.T) {
+	cases := []struct {
+		name           string
+		dataNodes      int
+		replicas       uint64
+		expectedStatus values.CheckerStatus
+	}{
+		{name: "no-replicas", dataNodes: 1, expectedStatus: values.GoodCheckerStatus},
+		{name: "one-replica", dataNodes: 2, replicas: 1, expectedStatus: values.GoodCheckerStatus},
+		{name: "one-replica-one-node", dataNodes: 1, replicas: 1, expectedStatus: values.AlertCheckerStatus},
+		{name: "two-replicas", dataNodes: 5, replicas: 2, expectedStatus: values.GoodCheckerStatus},
+		{name: "two-replicas-few-nodes", dataNodes: 4, replicas: 2, expectedStatus: values.WarnCheckerStatus},
+		{name: "three-replicas", dataNodes: 10, replicas: 3, expectedStatus: values.GoodCheckerStatus},
+		{name: "three-replicas-few-nodes", dataNodes: 9, replicas: 3, expectedStatus: values.WarnCheckerStatus},
+		{name: "three-replicas-three-nodes", dataNodes: 3, replicas: 3, expectedStatus: values.AlertCheckerStatus},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := replicaVBucketNumberCheck(values.BucketSummary{Name: "B0", NumReplicas: tc.replicas},
+				&clusterResources{cluster: dataNodesCluster(tc.dataNodes, "")})
+			require.NoError(t, err)
+			require.Equal(t, values.CheckReplicaVBucketNumber, result.Name)
+			require.Equal(t, tc.expectedStatus, result.Status)
+		})
+	}
+}
+
+func TestNonDefaultVBucketCountCheck(t *testing.T) {
+	vbMap := func(count int) couchbase.VBucketServerMap {
+		return couchbase.VBucketServerMap{ServerList: []string{"h0"}, VBucketMap: make([][]int, count)}
+	}
+
+	buckets := []couchbase.Bucket{
+		{Name: "default", VBucketServerMap: vbMap(1024)},
+		{Name: "small", VBucketServerMap: vbMap(64)},
+		{Name: "memcached"},
+	}
+
+	cases := []struct {
+		name           string
+		bucket         string
+		os             string
+		expectedStatus values.CheckerStatus
+	}{
+		{name: "default", bucket: "default", os: "x86_64-unknown-linux-gnu", expectedStatus: values.GoodCheckerStatus},
+		{name: "non-default", bucket: "small", os: "x86_64-unknown-linux-gnu", expectedStatus: values.WarnCheckerStatus},
+		{name: "macos", bucket: "small", os: "x86_64-apple-darwin19.6.0", expectedStatus: values.GoodCheckerStatus},
+		{name: "memcached", bucket: "memcached", expectedStatus: values.GoodCheckerStatus},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resources := mockClientResources(t, dataNodesCluster(1, tc.os), "GetPoolsBucket", buckets, nil)
+
+			result, err := nonDefaultVBucketCountCheck(values.BucketSummary{Name: tc.bucket}, resources)
+			require.NoError(t, err)
+			require.Equal(t, values.CheckNonDefaultVBucketCount, result.Name)
+			require.Equal(t, tc.expectedStatus, result.Status)
+		})
+	}
+
+	t.Run("missing-bucket", func(t *testing.T) {
+		_, err := nonDefaultVBucketCountCheck(values.BucketSummary{Name: "other"},
+			mockClientResources(t, dataNodesCluster(1, ""), "GetPoolsBucket", buckets, nil))
+		require.ErrorIs(t, err, values.ErrNotFound)
+	})
+}
+
+func TestResidentRatioCheck(t *testing.T) {
+	cases := []struct {
+		name           string
+		samples        []float64
+		expectedStatus values.CheckerStatus
+	}{
+		{name: "no-samples", expectedStatus: values.GoodCheckerStatus},
+		{name: "high", samples: []float64{100, 90, 80}, expectedStatus: values.GoodCheckerStatus},
+		{name: "averaged", samples: []float64{2, 12, 16}, expectedStatus: values.GoodCheckerStatus},
+		{name: "low", samples: []float64{8, 9}, expectedStatus: values.WarnCheckerStatus},
+		{name: "very-low", samples: []float64{4, 5, 3}, expectedStatus: values.AlertCheckerStatus},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resources := mockClientResources(t, testCluster, "GetBucketStats", &values.BucketStat{VbActiveRatio: tc.samples},
+				nil)
+
+			result, err := residentRatioCheck(values.BucketSummary{Name: "B0"}, resources)
+			require.NoError(t, err)
+			require.Equal(t, values.CheckResidentRatio, result.Name)
+			require.Equal(t, tc.expectedStatus, result.Status)
+		})
+	}
+}
+
+func TestBucketMemoryUsageCheck(t *testing.T) {
+	cases := []struct {
+		name           string
+		samples        []float64
+		expectedStatus values.CheckerStatus
+	}{
+		{name: "below", samples: []float64{50, 60, 70}, expectedStatus: values.GoodCheckerStatus},
+		{
+			name:           "short-spike",
+			samples:        []float64{96, 96, 96, 96, 96, 96, 80, 95, 96},
+			expectedStatus: values.GoodCheckerStatus,
+		},
+		{name: "sustained", samples: []float64{80, 95, 96, 97, 98, 99, 100}, expectedStatus: values.AlertCheckerStatus},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resources := mockClientResources(t, testCluster, "GetBucketStats", &values.BucketStat{MemUsed: tc.samples}, nil)
+			bucket := values.BucketSummary{Name: "B0", Quota: 100}
+
+			result, err := bucketMemoryUsageCheck(bucket, resources)
+			require.NoError(t, err)
+			require.Equal(t, values.CheckBucketMemoryUsage, result.Name)
+			require.Equal(t, tc.expectedStatus, result.Status)
+
+			// the stats are cached for the other checkers
+			_, err = residentRatioCheck(bucket, resources)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestBucketStatsError(t *testing.T) {
+	client := &mocks.ClientIFace{}
+	client.On("GetBucketStats", mock.Anything, "B0").Return(nil, fmt.Errorf("connection refused"))
+
+	_, err := residentRatioCheck(values.BucketSummary{Name: "B0"}, &clusterResources{cluster: testCluster, client: client})
+	require.Error(t, err)
+}
+
+func TestUnknownStorageEngineCheck(t *testing.T) {
+	resources := mockClientResources(t, testCluster, "GetPoolsBucket", []couchbase.Bucket{
+		{Name: "couchstore", StorageEngine: "couchstore"},
+		{Name: "magma", StorageEngine: "magma"},
+		{Name: "old-version"},
+		{Name: "unknown", StorageEngine: "forestdb"},
+	}, nil)
+
+	for bucket, expected := range map[string]values.CheckerStatus{
+		"couchstore":  values.GoodCheckerStatus,
+		"magma":       values.GoodCheckerStatus,
+		"old-version": values.GoodCheckerStatus,
+		"unknown":     values.WarnCheckerStatus,
+	} {
+		result, err := unknownStorageEngineCheck(values.BucketSummary{Name: bucket}, resources)
+		require.NoError(t, err)
+		require.Equal(t, values.CheckUnknownStorageEngine, result.Name)
+		require.Equal(t, expected, result.Status, bucket)
+	}
+}
+
+func TestMaxTTLCheck(t *testing.T) {
+	const day = 24 * 60 * 60
+
+	cases := []struct {
+		name           string
+		version        string
+		maxTTL         uint64
+		expectedStatus values.CheckerStatus
+	}{
+		{name: "affected", version: "6.0.3-2895-enterprise", maxTTL: 30 * day, expectedStatus: values.AlertCheckerStatus},
+		{name: "short-ttl", version: "6.0.3-2895-enterprise", maxTTL: day, expectedStatus: values.GoodCheckerStatus},
+		{name: "fixed", version: "6.0.4-3082-enterprise", maxTTL: 60 * day, expectedStatus: values.GoodCheckerStatus},
+		{name: "older", version: "5.1.3-6212-enterprise", maxTTL: 60 * day, expectedStatus: values.GoodCheckerStatus},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := &values.CouchbaseCluster{
+				NodesSummary: values.NodesSummary{{NodeUUID: "N0", Version: tc.version}},
+			}
+
+			resources := mockClientResources(t, cluster, "GetPoolsBucket", []couchbase.Bucket{{Name: "B0", MaxTTL: tc.maxTTL}},
+				nil)
+
+			result, err := maxTTLCheck(values.BucketSummary{Name: "B0"}, resources)
+			require.NoError(t, err)
+			require.Equal(t, values.CheckMaxTTL, result.Name)
+			require.Equal(t, tc.expectedStatus, result.Status)
+		})
+	}
+}
@@ -0,0 +1,366 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/configuration"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/couchbase"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/memcached"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"go.uber.org/zap"
+)
+
+// clusterResources is everything the checkers can use to inspect a cluster.
+type clusterResources struct {
+	// ctx is the context of the check, it is given to all the requests the checkers make to the cluster.
+	ctx        context.Context
+	cluster    *values.CouchbaseCluster
+	client     couchbase.ClientIFace
+	thresholds *configuration.CheckerThresholds
+
+	// newNodeClient creates a client that only talks to the given node, for the information that is only available
+	// per node.
+	newNodeClient func(node values.NodeSummary) (couchbase.ClientIFace, error)
+	// nodeClients caches the clients for each node so they are only created once per check.
+	nodeClients map[string]couchbase.ClientIFace
+	// nodeStorage caches the storage information of the nodes so it is only requested once per check.
+	nodeStorage map[string]*values.Storage
+	// poolsBuckets caches the buckets, including their vBucket maps, so they are only requested once per check.
+	poolsBuckets []couchbase.Bucket
+	// indexStatus caches the status of the GSI indexes so it is only requested once per check.
+	indexStatus []*values.IndexStatus
+	// bucketStats caches the stats samples of each bucket so they are only requested once per check.
+	bucketStats map[string]*values.BucketStat
+
+	// newMemcachedClient creates a client for the Data Service nodes, for the information that is only available from
+	// memcached.
+	newMemcachedClient func() (memcached.ConnIFace, error)
+	// memcachedClient is created the first time a checker needs it and closed once all the checkers have run.
+	memcachedClient memcached.ConnIFace
+
+	// logScanner remembers how far the logs of the nodes were scanned between checks.
+	logScanner *logScanner
+	// logMatches caches the result of scanning each log file so they are only fetched once per check.
+	logMatches map[logFileKey]logScanResult
+}
+
+// logScanResult is the outcome of scanning a log file.
+type logScanResult struct {
+	matches map[string][]logMatch
+	err     error
+}
+
+// getNodeClient returns a client that only talks to the given node.
+func (r *clusterResources) getNodeClient(node values.NodeSummary) (couchbase.ClientIFace, error) {
+	if client, ok := r.nodeClients[node.NodeUUID]; ok {
+		return client, nil
+	}
+
+	client, err := r.newNodeClient(node)
+	if err != nil {
+		return nil, fmt.Errorf("could not create client for node: %w", err)
+	}
+
+	if r.nodeClients == nil {
+		r.nodeClients = make(map[string]couchbase.ClientIFace)
+	}
+
+	r.nodeClients[node.NodeUUID] = client
+	return client, nil
+}
+
+// getNodeStorage returns the storage information of the node. The checkers are run one at a time so no locking is
+// needed.
+func (r *clusterResources) getNodeStorage(node values.NodeSummary) (*values.Storage, error) {
+	if storage, ok := r.nodeStorage[node.NodeUUID]; ok {
+		return storage, nil
+	}
+
+	client, err := r.getNodeClient(node)
+	if err != nil {
+		return nil, err
+	}
+
+	storage, err := client.GetNodeStorage(r.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get node storage: %w", err)
+	}
+
+	if r.nodeStorage == nil {
+		r.nodeStorage = make(map[string]*values.Storage)
+	}
+
+	r.nodeStorage[node.NodeUUID] = storage
+	return storage, nil
+}
+
+// getLogMatches returns the matches of the log checkers in the log file of the node that have not expired yet. Each
+// log file is only fetched and scanned once per check, including when it fails.
+func (r *clusterResources) getLogMatches(node values.NodeSummary, file logFile) (map[string][]logMatch, error) {
+	key := logFileKey{cluster: r.cluster.UUID, node: node.NodeUUID, file: file.name}
+	if result, ok := r.logMatches[key]; ok {
+		return result.matches, result.err
+	}
+
+	var result logScanResult
+	client, err := r.getNodeClient(node)
+	if err != nil {
+		result.err = err
+	} else {
+		result.matches, result.err = scanLog(r.ctx, r.logScanner, r.cluster.UUID, node, file, client)
+	}
+
+	if r.logMatches == nil {
+		r.logMatches = make(map[logFileKey]logScanResult)
+	}
+
+	r.logMatches[key] = result
+	return result.matches, result.err
+}
+
+// getPoolsBuckets returns the buckets as given by the cluster manager, including their vBucket maps.
+func (r *clusterResources) getPoolsBuckets() ([]couchbase.Bucket, error) {
+	if r.poolsBuckets != nil {
+		return r.poolsBuckets, nil
+	}
+
+	buckets, err := r.client.GetPoolsBucket(r.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if buckets == nil {
+		buckets = make([]couchbase.Bucket, 0)
+	}
+
+	r.poolsBuckets = buckets
+	return buckets, nil
+}
+
+// getPoolsBucket returns a single bucket from getPoolsBuckets.
+func (r *clusterResources) getPoolsBucket(name string) (*couchbase.Bucket, error) {
+	buckets, err := r.getPoolsBuckets()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range buckets {
+		if buckets[i].Name == name {
+			return &buckets[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("bucket '%s' not returned by the cluster: %w", name, values.ErrNotFound)
+}
+
+// getIndexStatus returns the status of every GSI index instance, including replicas.
+func (r *clusterResources) getIndexStatus() ([]*values.IndexStatus, error) {
+	if r.indexStatus != nil {
+		return r.indexStatus, nil
+	}
+
+	indexes, err := r.client.GetIndexStatus(r.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if indexes == nil {
+		indexes = make([]*values.IndexStatus, 0)
+	}
+
+	r.indexStatus = indexes
+	return indexes, nil
+}
+
+// getBucketStats returns the recent stats samples of the bucket.
+func (r *clusterResources) getBucketStats(name string) (*values.BucketStat, error) {
+	if stats, ok := r.bucketStats[name]; ok {
+		return stats, nil
+	}
+
+	stats, err := r.client.GetBucketStats(r.ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.bucketStats == nil {
+		r.bucketStats = make(map[string]*values.BucketStat)
+	}
+
+	r.bucketStats[name] = stats
+	return stats, nil
+}
+
+// getMemcachedClient returns the client for the Data Service nodes, creating it if this is the first time it is needed.
+func (r *clusterResources) getMemcachedClient() (memcached.ConnIFace, error) {
+	if r.memcachedClient != nil {
+		return r.memcachedClient, nil
+	}
+
+	client, err := r.newMemcachedClient()
+	if err != nil {
+		return nil, fmt.Errorf("could not create memcached client: %w", err)
+	}
+
+	r.memcachedClient = client
+	return client, nil
+}
+
+// close releases the connections the checkers opened.
+func (r *clusterResources) close() {
+	if r.memcachedClient == nil {
+		return
+	}
+
+	if err := r.memcachedClient.Close(); err != nil {
+		zap.S().Warnw("(Status Monitor) Could not close memcached client", "cluster", r.cluster.UUID, "err", err)
+	}
+}
+
+// usageStatus returns the status for a usage percentage given its warning and alert thresholds.
+func usageStatus(usage, warn, alert float64) values.CheckerStatus {
+	switch {
+	case usage > alert:
+		return values.AlertCheckerStatus
+	case usage > warn:
+		return values.WarnCheckerStatus
+	default:
+		return values.GoodCheckerStatus
+	}
+}
+
+// clusterCheckerFn is run once per cluster. As they see the whole cluster they can return results for any scope, the
+// monitor will set the cluster UUID.
+type clusterCheckerFn func(resources *clusterResources) ([]*values.WrappedCheckerResult, error)
+
+// nodeCheckerFn is run once for every node in the cluster.
+type nodeCheckerFn func(node values.NodeSummary, resources *clusterResources) (*values.CheckerResult, error)
+
+// bucketCheckerFn is run once for every bucket in the cluster.
+type bucketCheckerFn func(bucket values.BucketSummary, resources *clusterResources) (*values.CheckerResult, error)
+
+func defaultClusterCheckers() map[string]clusterCheckerFn {
+	return map[string]clusterCheckerFn{
+		values.CheckDuplicateNodeUUID:         duplicateNodeUUIDCheck,
+		values.CheckMissingActiveVBuckets:     missingActiveVBucketsCheck,
+		values.CheckMissingReplicaVBuckets:    missingReplicaVBucketsCheck,
+		values.CheckIndexWithNoRedundancy:     indexWithNoRedundancyCheck,
+		values.CheckBadRedundantIndex:         badRedundantIndexCheck,
+		values.CheckTooManyIndexReplicas:      tooManyIndexReplicasCheck,
+		values.CheckMissingIndexPartitions:    missingIndexPartitionsCheck,
+		values.CheckImbalancedIndexPartitions: imbalancedIndexPartitionsCheck,
+		values.CheckGSILogLevel:               gsiLogLevelCheck,
+		values.CheckDCPPaused:                 dcpPausedCheck,
+		values.CheckLargeCheckpoints:          largeCheckpointsCheck,
+		values.CheckMemcachedFragmentation:    memcachedFragmentationCheck,
+		values.CheckDataLoss:                  logCheck(values.CheckDataLoss, values.AlertCheckerStatus),
+		values.CheckSegmentationFaults:        logCheck(values.CheckSegmentationFaults, values.WarnCheckerStatus),
+		values.CheckManagedProcessCrash:       logCheck(values.CheckManagedProcessCrash, values.WarnCheckerStatus),
+		values.CheckOOMKills:                  logCheck(values.CheckOOMKills, values.AlertCheckerStatus),
+		values.CheckSYNFlooding:               logCheck(values.CheckSYNFlooding, values.WarnCheckerStatus),
+		values.CheckCPUSoftLockup:             logCheck(values.CheckCPUSoftLockup, values.WarnCheckerStatus),
+		values.CheckConnTrackingTableFull:     logCheck(values.CheckConnTrackingTableFull, values.WarnCheckerStatus),
+
+		values.CheckSingleOrTwoNodeCluster: singleOrTwoNodeClusterCheck,
+		values.CheckMixedMode:              mixedModeCheck,
+		values.CheckActiveClusterNodes:     activeClusterNodesCheck,
+		values.CheckAsymmetricalCluster:    asymmetricalClusterCheck,
+		values.CheckEmptyServerGroup:       emptyServerGroupCheck,
+		values.CheckDeveloperPreview:       developerPreviewCheck,
+		values.CheckNumberOfBuckets:        numberOfBucketsCheck,
+		values.CheckServerQuota:            serverQuotaCheck,
+		values.CheckGlobalAutoCompaction:   globalAutoCompactionCheck,
+		values.CheckAutoFailoverEnabled:    autoFailoverCheck,
+	}
+}
+
+func defaultNodeCheckers() map[string]nodeCheckerFn {
+	return map[string]nodeCheckerFn{
+		values.CheckUnhealthyNode:     unhealthyNodeCheck,
+		values.CheckNodeSwapUsage:     nodeSwapUsageCheck,
+		values.CheckNodeDiskSpace:     nodeDiskSpaceCheck,
+		values.CheckMinimumNodeMemory: minimumNodeMemoryCheck,
+		values.CheckFreeMemory:        freeMemoryCheck,
+		values.CheckSupportedVersion:  supportedVersionCheck,
+		values.CheckGABuild:           gaBuildCheck,
+		values.CheckSupportedOS:       supportedOSCheck,
+		values.CheckOneServicePerNode: oneServicePerNodeCheck,
+		values.CheckCPUBucketCount:    cpuBucketCountCheck,
+		values.CheckSharedFilesystems: sharedFilesystemsCheck,
+	}
+}
+
+func defaultBucketCheckers() map[string]bucketCheckerFn {
+	return map[string]bucketCheckerFn{
+		values.CheckReplicaVBucketNumber:   replicaVBucketNumberCheck,
+		values.CheckNonDefaultVBucketCount: nonDefaultVBucketCountCheck,
+		values.CheckResidentRatio:          residentRatioCheck,
+		values.CheckBucketMemoryUsage:      bucketMemoryUsageCheck,
+		values.CheckUnknownStorageEngine:   unknownStorageEngineCheck,
+		values.CheckMaxTTL:                 maxTTLCheck,
+	}
+}
+
+// newResult creates a result for the checker marshalling the value if one is given.
+func newResult(name string, status values.CheckerStatus, value interface{}) (*values.CheckerResult, error) {
+	result := &values.CheckerResult{
+		Name:   name,
+		Status: status,
+		Time:   time.Now().UTC(),
+	}
+
+	if value == nil {
+		return result, nil
+	}
+
+	var err error
+	result.Value, err = json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal value for checker '%s': %w", name, err)
+	}
+
+	return result, nil
+}
+
+// addRemediation adds the checker's default remediation to warnings and alerts that do not give a specific one.
+func addRemediation(result *values.CheckerResult) {
+	if result.Remediation != "" ||
+		(result.Status != values.WarnCheckerStatus && result.Status != values.AlertCheckerStatus) {
+		return
+	}
+
+	if def, ok := values.AllCheckerDefs[result.Name]; ok {
+		result.Remediation = def.Remediation
+	}
+}
+
+// percentage returns part as a percentage of total rounded to two decimal places.
+func percentage(part, total uint64) float64 {
+	return math.Round(float64(part)/float64(total)*10000) / 100
+}
+
+// worstStatus returns the most severe of the two statuses.
+func worstStatus(a, b values.CheckerStatus) values.CheckerStatus {
+	severity := map[values.CheckerStatus]int{
+		values.InfoCheckerStatus:  0,
+		values.GoodCheckerStatus:  1,
+		values.WarnCheckerStatus:  2,
+		values.AlertCheckerStatus: 3,
+	}
+
+	if severity[b] > severity[a] {
+		return b
+	}
+
+	return a
+}
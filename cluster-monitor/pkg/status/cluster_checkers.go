@@ -0,0 +1,466 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package status
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+)
+
+// duplicateNodeUUIDCheck produces a result for every node UUID in the cluster, alerting for the ones shared by more
+// than one host.
+func duplicateNodeUUIDCheck(resources *clusterResources) ([]*values.WrappedCheckerResult, error) {
+	hostsByUUID := make(map[string][]string)
+	order := make([]string, 0, len(resources.cluster.NodesSummary))
+	for _, node := range resources.cluster.NodesSummary {
+		if _, ok := hostsByUUID[node.NodeUUID]; !ok {
+			order = append(order, node.NodeUUID)
+		}
+
+		hostsByUUID[node.NodeUUID] = append(hostsByUUID[node.NodeUUID], node.Host)
+	}
+
+	results := make([]*values.WrappedCheckerResult, 0, len(order))
+	for _, uuid := range order {
+		status := values.GoodCheckerStatus
+		var value interface{}
+		if hosts := hostsByUUID[uuid]; len(hosts) > 1 {
+			status = values.AlertCheckerStatus
+			value = map[string][]string{"hosts": hosts}
+		}
+
+		result, err := newResult(values.CheckDuplicateNodeUUID, status, value)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, &values.WrappedCheckerResult{Result: result, Node: uuid})
+	}
+
+	return results, nil
+}
+
+// missingActiveVBucketsCheck produces a result for every bucket with a vBucket map, alerting if any of the vBuckets
+// does not have an active copy. The nodes with replicas of those vBuckets are given so they can be recovered.
+func missingActiveVBucketsCheck(resources *clusterResources) ([]*values.WrappedCheckerResult, error) {
+	buckets, err := resources.getPoolsBuckets()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*values.WrappedCheckerResult, 0, len(buckets))
+	for _, bucket := range buckets {
+		vbMap := bucket.VBucketServerMap
+		if len(vbMap.VBucketMap) == 0 {
+			continue
+		}
+
+		missing := make([]int, 0)
+		nodes := make(nodeSet)
+		for vb, servers := range vbMap.VBucketMap {
+			if len(servers) > 0 && servers[0] >= 0 {
+				continue
+			}
+
+			missing = append(missing, vb)
+			for _, server := range servers {
+				nodes.add(vbMap.ServerList, server)
+			}
+		}
+
+		status := values.GoodCheckerStatus
+		var value interface{}
+		if len(missing) > 0 {
+			status = values.AlertCheckerStatus
+			value = map[string]interface{}{"vbuckets": missing, "replica_nodes": nodes.list()}
+		}
+
+		result, err := newResult(values.CheckMissingActiveVBuckets, status, value)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, &values.WrappedCheckerResult{Result: result, Bucket: bucket.Name})
+	}
+
+	return results, nil
+}
+
+// missingReplicaVBucketsCheck produces a result for every bucket with a vBucket map, warning if any of the vBuckets
+// has fewer replicas than the bucket is configured for. The nodes with the active copies of those vBuckets are given.
+func missingReplicaVBucketsCheck(resources *clusterResources) ([]*values.WrappedCheckerResult, error) {
+	buckets, err := resources.getPoolsBuckets()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*values.WrappedCheckerResult, 0, len(buckets))
+	for _, bucket := range buckets {
+		vbMap := bucket.VBucketServerMap
+		if len(vbMap.VBucketMap) == 0 {
+			continue
+		}
+
+		missing := make([]int, 0)
+		nodes := make(nodeSet)
+		for vb, servers := range vbMap.VBucketMap {
+			for replica := 1; replica <= vbMap.NumReplicas; replica++ {
+				if replica < len(servers) && servers[replica] >= 0 {
+					continue
+				}
+
+				missing = append(missing, vb)
+				if len(servers) > 0 {
+					nodes.add(vbMap.ServerList, servers[0])
+				}
+
+				break
+			}
+		}
+
+		status := values.GoodCheckerStatus
+		var value interface{}
+		if len(missing) > 0 {
+			status = values.WarnCheckerStatus
+			value = map[string]interface{}{
+				"vbuckets":     missing,
+				"num_replicas": vbMap.NumReplicas,
+				"active_nodes": nodes.list(),
+			}
+		}
+
+		result, err := newResult(values.CheckMissingReplicaVBuckets, status, value)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, &values.WrappedCheckerResult{Result: result, Bucket: bucket.Name})
+	}
+
+	return results, nil
+}
+
+// nodeSet is a set of the nodes in a vBucket map server list.
+type nodeSet map[string]struct{}
+
+// add adds the server at the given index of the server list, indexes outside of it (such as -1) are ignored.
+func (s nodeSet) add(serverList []string, index int) {
+	if index >= 0 && index < len(serverList) {
+		s[serverList[index]] = struct{}{}
+	}
+}
+
+// list returns the nodes in the set sorted.
+func (s nodeSet) list() []string {
+	nodes := make([]string, 0, len(s))
+	for node := range s {
+		nodes = append(nodes, node)
+	}
+
+	sort.Strings(nodes)
+	return nodes
+}
+
+// clusterResult wraps a result that applies to the whole cluster.
+func clusterResult(name string, status values.CheckerStatus, value interface{}) ([]*values.WrappedCheckerResult,
+	error) {
+	result, err := newResult(name, status, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*values.WrappedCheckerResult{{Result: result}}, nil
+}
+
+// singleOrTwoNodeClusterCheck warns if the cluster has fewer than three nodes.
+func singleOrTwoNodeClusterCheck(resources *clusterResources) ([]*values.WrappedCheckerResult, error) {
+	status := values.GoodCheckerStatus
+	var value interface{}
+	if nodes := len(resources.cluster.NodesSummary); nodes < 3 {
+		status = values.WarnCheckerStatus
+		value = map[string]int{"nodes": nodes}
+	}
+
+	return clusterResult(values.CheckSingleOrTwoNodeCluster, status, value)
+}
+
+// mixedModeCheck warns if any of the nodes runs a different version than the oldest one in the cluster.
+func mixedModeCheck(resources *clusterResources) ([]*values.WrappedCheckerResult, error) {
+	minVersion := resources.cluster.NodesSummary.GetMinVersion()
+
+	versions := make(map[string]struct{})
+	for _, node := range resources.cluster.NodesSummary {
+		versions[node.Version] = struct{}{}
+	}
+
+	status := values.GoodCheckerStatus
+	var value interface{}
+	if len(versions) > 1 {
+		list := make([]string, 0, len(versions))
+		for version := range versions {
+			list = append(list, version)
+		}
+
+		sort.Strings(list)
+
+		status = values.WarnCheckerStatus
+		value = map[string]interface{}{"min_version": minVersion, "versions": list}
+	}
+
+	return clusterResult(values.CheckMixedMode, status, value)
+}
+
+// activeClusterNodesCheck alerts if any of the nodes is not an active member of the cluster. Nodes that were added but
+// not rebalanced in yet are only a warning.
+func activeClusterNodesCheck(resources *clusterResources) ([]*values.WrappedCheckerResult, error) {
+	type inactiveNode struct {
+		Node       string `json:"node"`
+		Host       string `json:"host"`
+		Membership string `json:"membership"`
+	}
+
+	status := values.GoodCheckerStatus
+	inactive := make([]inactiveNode, 0)
+	for _, node := range resources.cluster.NodesSummary {
+		nodeStatus := values.GoodCheckerStatus
+		switch node.ClusterMembership {
+		case "active":
+		case "inactiveAdded":
+			nodeStatus = values.WarnCheckerStatus
+		default:
+			nodeStatus = values.AlertCheckerStatus
+		}
+
+		if nodeStatus == values.GoodCheckerStatus {
+			continue
+		}
+
+		status = worstStatus(status, nodeStatus)
+		inactive = append(inactive, inactiveNode{
+			Node:       node.NodeUUID,
+			Host:       node.Host,
+			Membership: node.ClusterMembership,
+		})
+	}
+
+	var value interface{}
+	if len(inactive) > 0 {
+		value = map[string][]inactiveNode{"inactive_nodes": inactive}
+	}
+
+	return clusterResult(values.CheckActiveClusterNodes, status, value)
+}
+
+// asymmetricalMemoryTolerance is the percentage the memory of nodes running the same services can differ by, as the
+// memory reported for identical machines is not always exactly the same.
+const asymmetricalMemoryTolerance = 5
+
+// asymmetricalClusterCheck warns if nodes running the same services have a different number of CPUs or amount of
+// memory. Nodes running different services are expected to be sized differently.
+func asymmetricalClusterCheck(resources *clusterResources) ([]*values.WrappedCheckerResult, error) {
+	type nodeHardware struct {
+		Node     string   `json:"node"`
+		Host     string   `json:"host"`
+		Services []string `json:"services"`
+		CPUCount int      `json:"cpu_count"`
+		MemTotal uint64   `json:"mem_total"`
+	}
+
+	groups := make(map[string][]nodeHardware)
+	order := make([]string, 0)
+	for _, node := range resources.cluster.NodesSummary {
+		services := append([]string(nil), node.Services...)
+		sort.Strings(services)
+
+		key := strings.Join(services, ",")
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+
+		groups[key] = append(groups[key], nodeHardware{
+			Node:     node.NodeUUID,
+			Host:     node.Host,
+			Services: services,
+			CPUCount: node.CPUCount,
+			MemTotal: node.MemTotal,
+		})
+	}
+
+	asymmetrical := make([]nodeHardware, 0)
+	for _, key := range order {
+		nodes := groups[key]
+
+		minMem, maxMem := nodes[0].MemTotal, nodes[0].MemTotal
+		differentCPUs := false
+		for _, node := range nodes[1:] {
+			differentCPUs = differentCPUs || node.CPUCount != nodes[0].CPUCount
+			if node.MemTotal < minMem {
+				minMem = node.MemTotal
+			}
+
+			if node.MemTotal > maxMem {
+				maxMem = node.MemTotal
+			}
+		}
+
+		if differentCPUs || percentage(maxMem-minMem, maxMem) > asymmetricalMemoryTolerance {
+			asymmetrical = append(asymmetrical, nodes...)
+		}
+	}
+
+	status := values.GoodCheckerStatus
+	var value interface{}
+	if len(asymmetrical) > 0 {
+		status = values.WarnCheckerStatus
+		value = map[string][]nodeHardware{"nodes": asymmetrical}
+	}
+
+	return clusterResult(values.CheckAsymmetricalCluster, status, value)
+}
+
+// emptyServerGroupCheck warns if any of the server groups does not have any nodes.
+func emptyServerGroupCheck(resources *clusterResources) ([]*values.WrappedCheckerResult, error) {
+	serverGroups, err := resources.client.GetServerGroups(resources.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get server groups: %w", err)
+	}
+
+	empty := make([]string, 0)
+	for _, group := range serverGroups {
+		if len(group.Nodes) == 0 {
+			empty = append(empty, group.Name)
+		}
+	}
+
+	status := values.GoodCheckerStatus
+	var value interface{}
+	if len(empty) > 0 {
+		status = values.WarnCheckerStatus
+		value = map[string][]string{"server_groups": empty}
+	}
+
+	return clusterResult(values.CheckEmptyServerGroup, status, value)
+}
+
+// developerPreviewCheck warns if the cluster is in Developer Preview mode.
+func developerPreviewCheck(resources *clusterResources) ([]*values.WrappedCheckerResult, error) {
+	info := resources.client.GetClusterInfo()
+	if info == nil {
+		return nil, fmt.Errorf("no cluster information available")
+	}
+
+	status := values.GoodCheckerStatus
+	if info.DeveloperPreview {
+		status = values.WarnCheckerStatus
+	}
+
+	return clusterResult(values.CheckDeveloperPreview, status, nil)
+}
+
+// maxBuckets is the most buckets a cluster should have before performance degrades.
+const maxBuckets = 30
+
+// numberOfBucketsCheck warns if the cluster has more than maxBuckets buckets.
+func numberOfBucketsCheck(resources *clusterResources) ([]*values.WrappedCheckerResult, error) {
+	buckets := len(resources.cluster.BucketsSummary)
+
+	status := values.GoodCheckerStatus
+	if buckets > maxBuckets {
+		status = values.WarnCheckerStatus
+	}
+
+	return clusterResult(values.CheckNumberOfBuckets, status, map[string]int{"buckets": buckets, "max": maxBuckets})
+}
+
+const (
+	// serverQuotaWarn and serverQuotaAlert are the percentages of a node's memory the quotas of its services can add
+	// up to before the operating system is left without enough memory.
+	serverQuotaWarn  = 80
+	serverQuotaAlert = 90
+
+	// maxAutoFailoverTimeout is the longest, in seconds, a node should be unresponsive before it is failed over. It is
+	// the default timeout.
+	maxAutoFailoverTimeout = 120
+)
+
+// serverQuotaCheck produces a result for every node that reported its memory, warning or alerting if the memory quotas
+// of the services it runs add up to too much of it.
+func serverQuotaCheck(resources *clusterResources) ([]*values.WrappedCheckerResult, error) {
+	quotas, err := resources.client.GetMemoryQuotas(resources.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*values.WrappedCheckerResult, 0, len(resources.cluster.NodesSummary))
+	for _, node := range resources.cluster.NodesSummary {
+		if node.MemTotal == 0 {
+			continue
+		}
+
+		var quota uint64
+		for _, service := range node.Services {
+			quota += quotas.ServiceQuota(service) * 1024 * 1024
+		}
+
+		usage := percentage(quota, node.MemTotal)
+		result, err := newResult(values.CheckServerQuota, usageStatus(usage, serverQuotaWarn, serverQuotaAlert),
+			map[string]interface{}{"quota": quota, "mem_total": node.MemTotal, "quota_percent": usage})
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, &values.WrappedCheckerResult{Result: result, Node: node.NodeUUID})
+	}
+
+	return results, nil
+}
+
+// globalAutoCompactionCheck warns if neither of the global database fragmentation thresholds is set, as the buckets
+// that do not override them would never be compacted automatically.
+func globalAutoCompactionCheck(resources *clusterResources) ([]*values.WrappedCheckerResult, error) {
+	settings, err := resources.client.GetAutoCompactionSettings(resources.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := values.GoodCheckerStatus
+	if !settings.DatabaseFragmentationThreshold.IsSet() {
+		status = values.WarnCheckerStatus
+	}
+
+	return clusterResult(values.CheckGlobalAutoCompaction, status, map[string]*uint64{
+		"fragmentation_percentage": settings.DatabaseFragmentationThreshold.Percentage,
+		"fragmentation_size":       settings.DatabaseFragmentationThreshold.Size,
+	})
+}
+
+// autoFailoverCheck alerts if auto-failover is disabled and warns if the nodes have to be unresponsive for longer than
+// maxAutoFailoverTimeout before they are failed over.
+func autoFailoverCheck(resources *clusterResources) ([]*values.WrappedCheckerResult, error) {
+	settings, err := resources.client.GetAutoFailOverSettings(resources.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := values.GoodCheckerStatus
+	switch {
+	case !settings.Enabled:
+		status = values.AlertCheckerStatus
+	case settings.Timeout > maxAutoFailoverTimeout:
+		status = values.WarnCheckerStatus
+	}
+
+	return clusterResult(values.CheckAutoFailoverEnabled, status, map[string]interface{}{
+		"enabled":                 settings.Enabled,
+		"timeout":                 settings.Timeout,
+		"max_count":               settings.MaxCount,
+		"failover_server_group":   settings.FailoverServerGroup,
+		"failover_on_disk_issues": settings.FailoverOnDataDiskIssues.Enabled,
+	})
+}
@@ -0,0 +1,362 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/couchbase"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/couchbase/mocks"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuplicateNodeUUIDCheck(t *testing.T) {
+	cluster := &values.CouchbaseCluster{
+		NodesSummary: values.NodesSummary{
+			{NodeUUID: "N0", Host: "h0"},
+			{NodeUUID: "N1", Host: "h1"},
+			{NodeUUID: "N0", Host: "h2"},
+		},
+	}
+
+	results, err := duplicateNodeUUIDCheck(&clusterResources{cluster: cluster})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	require.Equal(t, "N0", results[0].Node)
+	require.Equal(t, values.AlertCheckerStatus, results[0].Result.Status)
+	require.JSONEq(t, `{"hosts":["h0","h2"]}`, string(results[0].Result.Value))
+
+	require.Equal(t, "N1", results[1].Node)
+	require.Equal(t, values.GoodCheckerStatus, results[1].Result.Status)
+	require.Equal(t, json.RawMessage(nil), results[1].Result.Value)
+}
+
+var testVBucketBuckets = []couchbase.Bucket{
+	{
+		Name: "healthy",
+		VBucketServerMap: couchbase.VBucketServerMap{
+			ServerList:  []string{"h0:11210", "h1:11210"},
+			NumReplicas: 1,
+			VBucketMap:  [][]int{{0, 1}, {1, 0}, {0, 1}, {1, 0}},
+		},
+	},
+	{
+		Name: "degraded",
+		VBucketServerMap: couchbase.VBucketServerMap{
+			ServerList:  []string{"h0:11210", "h1:11210", "h2:11210"},
+			NumReplicas: 2,
+			VBucketMap:  [][]int{{0, 1, 2}, {-1, 0, 2}, {2, -1, -1}, {-1, -1, -1}, {1, 0}},
+		},
+	},
+	{Name: "memcached"},
+}
+
+func TestMissingActiveVBucketsCheck(t *testing.T) {
+	resources := mockClientResources(t, testCluster, "GetPoolsBucket", testVBucketBuckets, nil)
+
+	results, err := missingActiveVBucketsCheck(resources)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	require.Equal(t, "healthy", results[0].Bucket)
+	require.Equal(t, values.CheckMissingActiveVBuckets, results[0].Result.Name)
+	require.Equal(t, values.GoodCheckerStatus, results[0].Result.Status)
+
+	require.Equal(t, "degraded", results[1].Bucket)
+	require.Equal(t, values.AlertCheckerStatus, results[1].Result.Status)
+	require.JSONEq(t, `{"vbuckets":[1,3],"replica_nodes":["h0:11210","h2:11210"]}`,
+		string(results[1].Result.Value))
+
+	// the buckets are cached so other checkers do not request them again
+	_, err = missingReplicaVBucketsCheck(resources)
+	require.NoError(t, err)
+}
+
+func TestMissingReplicaVBucketsCheck(t *testing.T) {
+	results, err := missingReplicaVBucketsCheck(mockClientResources(t, testCluster, "GetPoolsBucket", testVBucketBuckets,
+		nil))
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	require.Equal(t, "healthy", results[0].Bucket)
+	require.Equal(t, values.CheckMissingReplicaVBuckets, results[0].Result.Name)
+	require.Equal(t, values.GoodCheckerStatus, results[0].Result.Status)
+
+	require.Equal(t, "degraded", results[1].Bucket)
+	require.Equal(t, values.WarnCheckerStatus, results[1].Result.Status)
+	require.JSONEq(t, `{"vbuckets":[2,3,4],"num_replicas":2,"active_nodes":["h1:11210","h2:11210"]}`,
+		string(results[1].Result.Value))
+}
+
+func TestVBucketCheckersError(t *testing.T) {
+	client := &mocks.ClientIFace{}
+	client.On("GetPoolsBucket", mock.Anything).Return(nil, fmt.Errorf("connection refused"))
+
+	_, err := missingActiveVBucketsCheck(&clusterResources{cluster: testCluster, client: client})
+	require.Error(t, err)
+}
+
+func TestSingleOrTwoNodeClusterCheck(t *testing.T) {
+	results, err := singleOrTwoNodeClusterCheck(&clusterResources{cluster: testCluster})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Empty(t, results[0].Node)
+	require.Equal(t, values.WarnCheckerStatus, results[0].Result.Status)
+	require.JSONEq(t, `{"nodes":2}`, string(results[0].Result.Value))
+
+	results, err = singleOrTwoNodeClusterCheck(&clusterResources{cluster: dataNodesCluster(3, "")})
+	require.NoError(t, err)
+	require.Equal(t, values.GoodCheckerStatus, results[0].Result.Status)
+}
+
+func TestMixedModeCheck(t *testing.T) {
+	cluster := kvCluster("7.0.3-7031-enterprise")
+
+	results, err := mixedModeCheck(&clusterResources{cluster: cluster})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, values.GoodCheckerStatus, results[0].Result.Status)
+
+	results, err = mixedModeCheck(&clusterResources{cluster: kvCluster("6.6.3-9808-enterprise")})
+	require.NoError(t, err)
+	require.Equal(t, values.WarnCheckerStatus, results[0].Result.Status)
+	require.JSONEq(t, `{"min_version":"6.6.3-9808-enterprise",`+
+		`"versions":["6.6.3-9808-enterprise","7.0.3-7031-enterprise"]}`, string(results[0].Result.Value))
+}
+
+func TestActiveClusterNodesCheck(t *testing.T) {
+	cases := []struct {
+		name           string
+		memberships    []string
+		expectedStatus values.CheckerStatus
+	}{
+		{name: "active", memberships: []string{"active", "active"}, expectedStatus: values.GoodCheckerStatus},
+		{name: "added", memberships: []string{"active", "inactiveAdded"}, expectedStatus: values.WarnCheckerStatus},
+		{
+			name:           "failed",
+			memberships:    []string{"inactiveFailed", "inactiveAdded"},
+			expectedStatus: values.AlertCheckerStatus,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := &values.CouchbaseCluster{}
+			for i, membership := range tc.memberships {
+				cluster.NodesSummary = append(cluster.NodesSummary, values.NodeSummary{
+					NodeUUID:          fmt.Sprintf("N%d", i),
+					ClusterMembership: membership,
+				})
+			}
+
+			results, err := activeClusterNodesCheck(&clusterResources{cluster: cluster})
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+			require.Equal(t, values.CheckActiveClusterNodes, results[0].Result.Name)
+			require.Equal(t, tc.expectedStatus, results[0].Result.Status)
+		})
+	}
+}
+
+func TestAsymmetricalClusterCheck(t *testing.T) {
+	const gib = 1024 * 1024 * 1024
+
+	cluster := &values.CouchbaseCluster{
+		NodesSummary: values.NodesSummary{
+			{NodeUUID: "N0", Services: []string{"kv"}, CPUCount: 8, MemTotal: 32 * gib},
+			{NodeUUID: "N1", Services: []string{"kv"}, CPUCount: 8, MemTotal: 31 * gib},
+			// nodes running other services can be sized differently
+			{NodeUUID: "N2", Services: []string{"n1ql", "index"}, CPUCount: 16, MemTotal: 64 * gib},
+			{NodeUUID: "N3", Services: []string{"index", "n1ql"}, CPUCount: 16, MemTotal: 64 * gib},
+		},
+	}
+
+	results, err := asymmetricalClusterCheck(&clusterResources{cluster: cluster})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, values.GoodCheckerStatus, results[0].Result.Status)
+
+	cluster.NodesSummary[3].CPUCount = 8
+	cluster.NodesSummary[1].MemTotal = 16 * gib
+
+	results, err = asymmetricalClusterCheck(&clusterResources{cluster: cluster})
+	require.NoError(t, err)
+	require.Equal(t, values.WarnCheckerStatus, results[0].Result.Status)
+
+	var value struct {
+		Nodes []struct {
+			Node string `json:"node"`
+		} `json:"nodes"`
+	}
+	require.NoError(t, json.Unmarshal(results[0].Result.Value, &value))
+	require.Len(t, value.Nodes, 4)
+}
+
+func TestEmptyServerGroupCheck(t *testing.T) {
+	client := &mocks.ClientIFace{}
+	client.On("GetServerGroups", mock.Anything).Return([]values.ServerGroup{
+		{Name: "Group 1", Nodes: []values.GroupNodes{{Hostname: "h0:8091", NodeUUID: "N0"}}},
+		{Name: "Group 2"},
+	}, nil).Once()
+	client.On("GetServerGroups", mock.Anything).Return(nil, fmt.Errorf("connection refused")).Once()
+	t.Cleanup(func() { client.AssertExpectations(t) })
+
+	resources := &clusterResources{cluster: testCluster, client: client}
+
+	results, err := emptyServerGroupCheck(resources)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, values.WarnCheckerStatus, results[0].Result.Status)
+	require.JSONEq(t, `{"server_groups":["Group 2"]}`, string(results[0].Result.Value))
+
+	_, err = emptyServerGroupCheck(resources)
+	require.Error(t, err)
+}
+
+func TestDeveloperPreviewCheck(t *testing.T) {
+	for _, preview := range []bool{false, true} {
+		client := &mocks.ClientIFace{}
+		client.On("GetClusterInfo").Return(&couchbase.PoolsMetadata{DeveloperPreview: preview})
+
+		results, err := developerPreviewCheck(&clusterResources{cluster: testCluster, client: client})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+
+		expected := values.GoodCheckerStatus
+		if preview {
+			expected = values.WarnCheckerStatus
+		}
+
+		require.Equal(t, expected, results[0].Result.Status)
+	}
+}
+
+func TestNumberOfBucketsCheck(t *testing.T) {
+	cluster := &values.CouchbaseCluster{}
+	for i := 0; i < maxBuckets; i++ {
+		cluster.BucketsSummary = append(cluster.BucketsSummary, values.BucketSummary{Name: fmt.Sprintf("B%d", i)})
+	}
+
+	results, err := numberOfBucketsCheck(&clusterResources{cluster: cluster})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, values.GoodCheckerStatus, results[0].Result.Status)
+
+	cluster.BucketsSummary = append(cluster.BucketsSummary, values.BucketSummary{Name: "one-too-many"})
+
+	results, err = numberOfBucketsCheck(&clusterResources{cluster: cluster})
+	require.NoError(t, err)
+	require.Equal(t, values.WarnCheckerStatus, results[0].Result.Status)
+	require.JSONEq(t, `{"buckets":31,"max":30}`, string(results[0].Result.Value))
+}
+
+func TestServerQuotaCheck(t *testing.T) {
+	const gib = 1024 * 1024 * 1024
+
+	client := &mocks.ClientIFace{}
+	client.On("GetMemoryQuotas", mock.Anything).
+		Return(&couchbase.MemoryQuotas{KV: 6144, Index: 1024, FTS: 512}, nil).Once()
+	t.Cleanup(func() { client.AssertExpectations(t) })
+
+	cluster := &values.CouchbaseCluster{
+		NodesSummary: values.NodesSummary{
+			{NodeUUID: "N0", Services: []string{"kv"}, MemTotal: 8 * gib},
+			{NodeUUID: "N1", Services: []string{"kv", "index", "fts"}, MemTotal: 8 * gib},
+			{NodeUUID: "N2", Services: []string{"index", "n1ql"}, MemTotal: 8 * gib},
+			{NodeUUID: "N3", Services: []string{"kv"}},
+		},
+	}
+
+	results, err := serverQuotaCheck(&clusterResources{cluster: cluster, client: client})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	for i, expected := range []values.CheckerStatus{
+		values.GoodCheckerStatus, values.AlertCheckerStatus, values.GoodCheckerStatus,
+	} {
+		require.Equal(t, fmt.Sprintf("N%d", i), results[i].Node)
+		require.Equal(t, values.CheckServerQuota, results[i].Result.Name)
+		require.Equal(t, expected, results[i].Result.Status)
+	}
+
+	require.JSONEq(t, `{"quota":8053063680,"mem_total":8589934592,"quota_percent":93.75}`,
+		string(results[1].Result.Value))
+}
+
+func TestGlobalAutoCompactionCheck(t *testing.T) {
+	thirty := uint64(30)
+
+	for _, tc := range []struct {
+		threshold      couchbase.FragmentationThreshold
+		expectedStatus values.CheckerStatus
+	}{
+		{threshold: couchbase.FragmentationThreshold{Percentage: &thirty}, expectedStatus: values.GoodCheckerStatus},
+		{threshold: couchbase.FragmentationThreshold{Size: &thirty}, expectedStatus: values.GoodCheckerStatus},
+		{expectedStatus: values.WarnCheckerStatus},
+	} {
+		client := &mocks.ClientIFace{}
+		client.On("GetAutoCompactionSettings", mock.Anything).
+			Return(&couchbase.AutoCompactionSettings{DatabaseFragmentationThreshold: tc.threshold}, nil)
+
+		results, err := globalAutoCompactionCheck(&clusterResources{cluster: testCluster, client: client})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, values.CheckGlobalAutoCompaction, results[0].Result.Name)
+		require.Equal(t, tc.expectedStatus, results[0].Result.Status)
+	}
+}
+
+func TestAutoFailoverCheck(t *testing.T) {
+	cases := []struct {
+		name           string
+		settings       couchbase.AutoFailoverSettings
+		expectedStatus values.CheckerStatus
+	}{
+		{
+			name:           "enabled",
+			settings:       couchbase.AutoFailoverSettings{Enabled: true, Timeout: 120, MaxCount: 1},
+			expectedStatus: values.GoodCheckerStatus,
+		},
+		{
+			name:           "long-timeout",
+			settings:       couchbase.AutoFailoverSettings{Enabled: true, Timeout: 600, MaxCount: 1},
+			expectedStatus: values.WarnCheckerStatus,
+		},
+		{
+			name:           "disabled",
+			settings:       couchbase.AutoFailoverSettings{Timeout: 120},
+			expectedStatus: values.AlertCheckerStatus,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			settings := tc.settings
+			client := &mocks.ClientIFace{}
+			client.On("GetAutoFailOverSettings", mock.Anything).Return(&settings, nil)
+
+			results, err := autoFailoverCheck(&clusterResources{cluster: testCluster, client: client})
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+			require.Equal(t, values.CheckAutoFailoverEnabled, results[0].Result.Name)
+			require.Equal(t, tc.expectedStatus, results[0].Result.Status)
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		client := &mocks.ClientIFace{}
+		client.On("GetAutoFailOverSettings", mock.Anything).Return(nil, fmt.Errorf("connection refused"))
+
+		_, err := autoFailoverCheck(&clusterResources{cluster: testCluster, client: client})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,513 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package status
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+)
+
+// imbalancedPartitionFactor is how much larger than the average of the other partitions of the same index a partition
+// can be before it is considered imbalanced.
+const imbalancedPartitionFactor = 1.2
+
+// indexWithNoRedundancyCheck produces a result for every bucket with indexes, warning about the indexes that have
+// neither a replica nor an equivalent index to fall back on if their node goes down.
+func indexWithNoRedundancyCheck(resources *clusterResources) ([]*values.WrappedCheckerResult, error) {
+	groups, err := getIndexGroups(resources)
+	if err != nil || groups == nil {
+		return nil, err
+	}
+
+	flagged := make(map[string][]interface{})
+	for _, key := range groups.keys {
+		if len(groups.equivalent[groups.indexes[key][0].equivalenceKey()]) > 1 {
+			continue
+		}
+
+		if len(groups.indexes[key]) == 1 {
+			flagged[key.bucket] = append(flagged[key.bucket], key.String())
+		}
+	}
+
+	return bucketIndexResults(values.CheckIndexWithNoRedundancy, values.WarnCheckerStatus, groups.buckets, flagged)
+}
+
+// badRedundantIndexCheck produces a result for every bucket with indexes, warning about replicas and equivalent
+// indexes that would all be lost together, either because they share a node or because they are all in the same
+// server group when there is more than one.
+func badRedundantIndexCheck(resources *clusterResources) ([]*values.WrappedCheckerResult, error) {
+	groups, err := getIndexGroups(resources)
+	if err != nil || groups == nil {
+		return nil, err
+	}
+
+	type badRedundancy struct {
+		Indexes     []string `json:"indexes"`
+		Node        string   `json:"node,omitempty"`
+		ServerGroup string   `json:"server_group,omitempty"`
+	}
+
+	// the server groups are only needed if there are redundant indexes
+	var hostGroups map[string]string
+
+	flagged := make(map[string][]interface{})
+	for _, equivalenceKey := range groups.equivalenceKeys {
+		instances := make([]*indexInstance, 0)
+		for _, key := range groups.equivalent[equivalenceKey] {
+			instances = append(instances, groups.indexes[key]...)
+		}
+
+		if len(instances) < 2 {
+			continue
+		}
+
+		bucket := instances[0].Bucket
+		if node, names := colocatedInstances(instances); node != "" {
+			flagged[bucket] = append(flagged[bucket], badRedundancy{Indexes: names, Node: node})
+			continue
+		}
+
+		if hostGroups == nil {
+			if hostGroups, err = getHostServerGroups(resources); err != nil {
+				return nil, err
+			}
+		}
+
+		if group := sharedServerGroup(instances, hostGroups); group != "" {
+			flagged[bucket] = append(flagged[bucket], badRedundancy{
+				Indexes:     instanceNames(instances),
+				ServerGroup: group,
+			})
+		}
+	}
+
+	return bucketIndexResults(values.CheckBadRedundantIndex, values.WarnCheckerStatus, groups.buckets, flagged)
+}
+
+// tooManyIndexReplicasCheck produces a result for every bucket with indexes, warning about the indexes with more
+// copies than there are active Index Service nodes to place them on.
+func tooManyIndexReplicasCheck(resources *clusterResources) ([]*values.WrappedCheckerResult, error) {
+	groups, err := getIndexGroups(resources)
+	if err != nil || groups == nil {
+		return nil, err
+	}
+
+	type tooManyReplicas struct {
+		Index      string `json:"index"`
+		NumReplica int    `json:"num_replica"`
+		IndexNodes int    `json:"index_nodes"`
+	}
+
+	nodes := len(indexNodes(resources.cluster, true))
+
+	flagged := make(map[string][]interface{})
+	for _, key := range groups.keys {
+		var replicas int
+		for _, instance := range groups.indexes[key] {
+			if instance.NumReplica > replicas {
+				replicas = instance.NumReplica
+			}
+		}
+
+		if replicas+1 > nodes {
+			flagged[key.bucket] = append(flagged[key.bucket], tooManyReplicas{
+				Index:      key.String(),
+				NumReplica: replicas,
+				IndexNodes: nodes,
+			})
+		}
+	}
+
+	return bucketIndexResults(values.CheckTooManyIndexReplicas, values.WarnCheckerStatus, groups.buckets, flagged)
+}
+
+// missingIndexPartitionsCheck produces a result for every bucket with indexes, alerting about the partitioned index
+// instances that have fewer partitions on the nodes than they were created with.
+func missingIndexPartitionsCheck(resources *clusterResources) ([]*values.WrappedCheckerResult, error) {
+	groups, err := getIndexGroups(resources)
+	if err != nil || groups == nil {
+		return nil, err
+	}
+
+	type missingPartitions struct {
+		Index         string `json:"index"`
+		Partitions    int    `json:"partitions"`
+		NumPartitions int    `json:"num_partitions"`
+	}
+
+	flagged := make(map[string][]interface{})
+	for _, key := range groups.keys {
+		for _, instance := range groups.indexes[key] {
+			if !instance.Partitioned {
+				continue
+			}
+
+			partitions := make(map[int]struct{})
+			for _, ids := range instance.PartitionMap {
+				for _, id := range ids {
+					partitions[id] = struct{}{}
+				}
+			}
+
+			if len(partitions) < instance.NumPartition {
+				flagged[key.bucket] = append(flagged[key.bucket], missingPartitions{
+					Index:         instance.qualifiedName(),
+					Partitions:    len(partitions),
+					NumPartitions: instance.NumPartition,
+				})
+			}
+		}
+	}
+
+	return bucketIndexResults(values.CheckMissingIndexPartitions, values.AlertCheckerStatus, groups.buckets, flagged)
+}
+
+// imbalancedIndexPartitionsCheck produces a result for every bucket with partitioned indexes, warning about the
+// partitions that use more memory than imbalancedPartitionFactor times the average of the other partitions of the
+// same index.
+func imbalancedIndexPartitionsCheck(resources *clusterResources) ([]*values.WrappedCheckerResult, error) {
+	if len(indexNodes(resources.cluster, false)) == 0 {
+		return nil, nil
+	}
+
+	stats, err := resources.client.GetIndexStorageStats(resources.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type imbalancedPartition struct {
+		Index             string  `json:"index"`
+		PartitionID       int     `json:"partition_id"`
+		MemorySize        int     `json:"memory_size"`
+		AverageMemorySize float64 `json:"average_memory_size"`
+	}
+
+	partitions := make(map[string][]*values.IndexStatsStorage)
+	for _, stat := range stats {
+		partitions[stat.Name] = append(partitions[stat.Name], stat)
+	}
+
+	names := make([]string, 0, len(partitions))
+	for name := range partitions {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	buckets := make([]string, 0)
+	flagged := make(map[string][]interface{})
+	for _, name := range names {
+		if len(partitions[name]) < 2 {
+			continue
+		}
+
+		// the stats name the indexes as bucket:index or bucket:scope:collection:index
+		bucket := strings.SplitN(name, ":", 2)[0]
+		if _, ok := flagged[bucket]; !ok {
+			buckets = append(buckets, bucket)
+			flagged[bucket] = make([]interface{}, 0)
+		}
+
+		var total int
+		for _, partition := range partitions[name] {
+			total += partition.Stats.IndexMemory
+		}
+
+		for _, partition := range partitions[name] {
+			average := float64(total-partition.Stats.IndexMemory) / float64(len(partitions[name])-1)
+			if average <= 0 || float64(partition.Stats.IndexMemory) <= average*imbalancedPartitionFactor {
+				continue
+			}
+
+			flagged[bucket] = append(flagged[bucket], imbalancedPartition{
+				Index:             name,
+				PartitionID:       partition.PartitionID,
+				MemorySize:        partition.Stats.IndexMemory,
+				AverageMemorySize: average,
+			})
+		}
+	}
+
+	sort.Strings(buckets)
+	return bucketIndexResults(values.CheckImbalancedIndexPartitions, values.WarnCheckerStatus, buckets, flagged)
+}
+
+// gsiLogLevelCheck produces a result for every Index Service node warning if the log level is not the default. The
+// setting is cluster wide but it is reported per node as that is where it takes effect.
+func gsiLogLevelCheck(resources *clusterResources) ([]*values.WrappedCheckerResult, error) {
+	nodes := indexNodes(resources.cluster, false)
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+
+	settings, err := resources.client.GetGSISettings(resources.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := values.GoodCheckerStatus
+	if settings.LogLevel != values.DefaultGSILogLevel {
+		status = values.WarnCheckerStatus
+	}
+
+	results := make([]*values.WrappedCheckerResult, 0, len(nodes))
+	for _, node := range nodes {
+		result, err := newResult(values.CheckGSILogLevel, status, map[string]values.GSILogLevel{
+			"log_level": settings.LogLevel,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, &values.WrappedCheckerResult{Result: result, Node: node.NodeUUID})
+	}
+
+	return results, nil
+}
+
+// indexNodes returns the nodes running the Index Service, optionally only the ones that are active.
+func indexNodes(cluster *values.CouchbaseCluster, activeOnly bool) []values.NodeSummary {
+	nodes := make([]values.NodeSummary, 0)
+	for _, node := range cluster.NodesSummary {
+		if node.HasService("index") && (!activeOnly || node.ClusterMembership == "active") {
+			nodes = append(nodes, node)
+		}
+	}
+
+	return nodes
+}
+
+// indexInstance is one of the copies of an index, either the index itself or one of its replicas.
+type indexInstance struct {
+	*values.IndexStatus
+}
+
+// qualifiedName returns the name of the instance, including the replica if it is one, qualified by its scope and
+// collection.
+func (i *indexInstance) qualifiedName() string {
+	return qualifyIndexName(i.Scope, i.Collection, i.Name)
+}
+
+// equivalenceKey returns a key that is the same for all the instances of equivalent indexes. Those are the ones
+// defined on the same keyspace with the same keys and conditions, which is what follows ON in the definition.
+func (i *indexInstance) equivalenceKey() string {
+	definition := i.Definition
+	if index := strings.Index(definition, " ON "); index >= 0 {
+		definition = definition[index+len(" ON "):]
+	}
+
+	if index := strings.Index(definition, " WITH "); index >= 0 {
+		definition = definition[:index]
+	}
+
+	return fmt.Sprintf("%s/%t/%s", i.Bucket, i.IsPrimary, strings.TrimSpace(definition))
+}
+
+// indexKey identifies an index and all its replicas.
+type indexKey struct {
+	bucket     string
+	scope      string
+	collection string
+	name       string
+}
+
+func (k indexKey) String() string {
+	return qualifyIndexName(k.scope, k.collection, k.name)
+}
+
+func qualifyIndexName(scope, collection, name string) string {
+	if scope == "" {
+		return name
+	}
+
+	return scope + "." + collection + "." + name
+}
+
+// indexGroups is the index status grouped into indexes and into sets of equivalent indexes.
+type indexGroups struct {
+	// buckets are the buckets with indexes, sorted.
+	buckets []string
+	// keys are the indexes sorted by bucket and name.
+	keys    []indexKey
+	indexes map[indexKey][]*indexInstance
+	// equivalenceKeys are the keys of the sets of equivalent indexes, sorted.
+	equivalenceKeys []string
+	equivalent      map[string][]indexKey
+}
+
+// getIndexGroups groups the index status, it returns nil if the cluster has no Index Service nodes.
+func getIndexGroups(resources *clusterResources) (*indexGroups, error) {
+	if len(indexNodes(resources.cluster, false)) == 0 {
+		return nil, nil
+	}
+
+	status, err := resources.getIndexStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	groups := &indexGroups{
+		buckets:         make([]string, 0),
+		keys:            make([]indexKey, 0),
+		indexes:         make(map[indexKey][]*indexInstance),
+		equivalenceKeys: make([]string, 0),
+		equivalent:      make(map[string][]indexKey),
+	}
+
+	buckets := make(map[string]struct{})
+	for _, index := range status {
+		instance := &indexInstance{IndexStatus: index}
+		key := indexKey{bucket: index.Bucket, scope: index.Scope, collection: index.Collection, name: index.IndexName}
+		if _, ok := groups.indexes[key]; !ok {
+			groups.keys = append(groups.keys, key)
+
+			equivalenceKey := instance.equivalenceKey()
+			if _, ok := groups.equivalent[equivalenceKey]; !ok {
+				groups.equivalenceKeys = append(groups.equivalenceKeys, equivalenceKey)
+			}
+
+			groups.equivalent[equivalenceKey] = append(groups.equivalent[equivalenceKey], key)
+		}
+
+		groups.indexes[key] = append(groups.indexes[key], instance)
+
+		if _, ok := buckets[index.Bucket]; !ok {
+			buckets[index.Bucket] = struct{}{}
+			groups.buckets = append(groups.buckets, index.Bucket)
+		}
+	}
+
+	sort.Strings(groups.buckets)
+	sort.Strings(groups.equivalenceKeys)
+	sort.Slice(groups.keys, func(i, j int) bool {
+		if groups.keys[i].bucket != groups.keys[j].bucket {
+			return groups.keys[i].bucket < groups.keys[j].bucket
+		}
+
+		return groups.keys[i].String() < groups.keys[j].String()
+	})
+
+	return groups, nil
+}
+
+// colocatedInstances returns the first node that holds the same data for more than one of the instances, together
+// with the names of those instances. For partitioned indexes only the same partition on the same node counts.
+func colocatedInstances(instances []*indexInstance) (string, []string) {
+	type placement struct {
+		host      string
+		partition int
+	}
+
+	placements := make(map[placement][]*indexInstance)
+	order := make([]placement, 0)
+	for _, instance := range instances {
+		for _, host := range instance.Hosts {
+			partitions := []int{0}
+			if instance.Partitioned && len(instance.PartitionMap[host]) > 0 {
+				partitions = instance.PartitionMap[host]
+			}
+
+			for _, partition := range partitions {
+				key := placement{host: host, partition: partition}
+				if _, ok := placements[key]; !ok {
+					order = append(order, key)
+				}
+
+				placements[key] = append(placements[key], instance)
+			}
+		}
+	}
+
+	for _, key := range order {
+		if len(placements[key]) > 1 {
+			return key.host, instanceNames(placements[key])
+		}
+	}
+
+	return "", nil
+}
+
+// sharedServerGroup returns the server group holding all the instances, if the cluster has more than one server group
+// and they are all in the same one.
+func sharedServerGroup(instances []*indexInstance, hostGroups map[string]string) string {
+	groups := make(map[string]struct{})
+	for _, group := range hostGroups {
+		groups[group] = struct{}{}
+	}
+
+	if len(groups) < 2 {
+		return ""
+	}
+
+	var shared string
+	for _, instance := range instances {
+		for _, host := range instance.Hosts {
+			group, ok := hostGroups[host]
+			if !ok || (shared != "" && group != shared) {
+				return ""
+			}
+
+			shared = group
+		}
+	}
+
+	return shared
+}
+
+// getHostServerGroups returns the server group of every node by host name. Empty server groups are not included.
+func getHostServerGroups(resources *clusterResources) (map[string]string, error) {
+	serverGroups, err := resources.client.GetServerGroups(resources.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hostGroups := make(map[string]string)
+	for _, group := range serverGroups {
+		for _, node := range group.Nodes {
+			hostGroups[node.Hostname] = group.Name
+		}
+	}
+
+	return hostGroups, nil
+}
+
+func instanceNames(instances []*indexInstance) []string {
+	names := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		names = append(names, instance.qualifiedName())
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// bucketIndexResults produces a result for each of the buckets, with the given status and the flagged indexes as the
+// value for the buckets that have any.
+func bucketIndexResults(name string, status values.CheckerStatus, buckets []string,
+	flagged map[string][]interface{}) ([]*values.WrappedCheckerResult, error) {
+	results := make([]*values.WrappedCheckerResult, 0, len(buckets))
+	for _, bucket := range buckets {
+		bucketStatus := values.GoodCheckerStatus
+		var value interface{}
+		if len(flagged[bucket]) > 0 {
+			bucketStatus = status
+			value = map[string]interface{}{"indexes": flagged[bucket]}
+		}
+
+		result, err := newResult(name, bucketStatus, value)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, &values.WrappedCheckerResult{Result: result, Bucket: bucket})
+	}
+
+	return results, nil
+}
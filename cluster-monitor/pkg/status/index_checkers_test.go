@@ -0,0 +1,231 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package status
+
+import (
+	"testing"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/couchbase/mocks"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var indexCluster = &values.CouchbaseCluster{
+	NodesSummary: values.NodesSummary{
+		{NodeUUID: "N0", Services: []string{"kv"}, ClusterMembership: "active"},
+		{NodeUUID: "N1", Services: []string{"index"}, ClusterMembership: "active"},
+		{NodeUUID: "N2", Services: []string{"index", "n1ql"}, ClusterMembership: "active"},
+	},
+}
+
+func testIndex(bucket, name string, replica int, definition string, hosts ...string) *values.IndexStatus {
+	index := &values.IndexStatus{
+		Name:       name,
+		IndexName:  name,
+		Bucket:     bucket,
+		Definition: definition,
+		Hosts:      hosts,
+		ReplicaID:  replica,
+	}
+
+	if replica > 0 {
+		index.Name = name + " (replica 1)"
+	}
+
+	return index
+}
+
+func TestIndexCheckersNoIndexNodes(t *testing.T) {
+	resources := &clusterResources{cluster: testCluster, client: &mocks.ClientIFace{}}
+
+	for name, checker := range map[string]clusterCheckerFn{
+		values.CheckIndexWithNoRedundancy:     indexWithNoRedundancyCheck,
+		values.CheckBadRedundantIndex:         badRedundantIndexCheck,
+		values.CheckTooManyIndexReplicas:      tooManyIndexReplicasCheck,
+		values.CheckMissingIndexPartitions:    missingIndexPartitionsCheck,
+		values.CheckImbalancedIndexPartitions: imbalancedIndexPartitionsCheck,
+		values.CheckGSILogLevel:               gsiLogLevelCheck,
+	} {
+		t.Run(name, func(t *testing.T) {
+			results, err := checker(resources)
+			require.NoError(t, err)
+			require.Empty(t, results)
+		})
+	}
+}
+
+func TestIndexWithNoRedundancyCheck(t *testing.T) {
+	resources := mockClientResources(t, indexCluster, "GetIndexStatus", []*values.IndexStatus{
+		testIndex("B0", "replicated", 0, "CREATE INDEX `replicated` ON `B0`(`a`)", "h1:8091"),
+		testIndex("B0", "replicated", 1, "CREATE INDEX `replicated` ON `B0`(`a`)", "h2:8091"),
+		testIndex("B0", "equivalent-0", 0, "CREATE INDEX `equivalent-0` ON `B0`(`b`)", "h1:8091"),
+		testIndex("B0", "equivalent-1", 0, "CREATE INDEX `equivalent-1` ON `B0`(`b`) WITH {\"defer_build\":true}",
+			"h2:8091"),
+		testIndex("B1", "single", 0, "CREATE INDEX `single` ON `B1`(`a`)", "h1:8091"),
+	}, nil)
+
+	results, err := indexWithNoRedundancyCheck(resources)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	require.Equal(t, "B0", results[0].Bucket)
+	require.Equal(t, values.CheckIndexWithNoRedundancy, results[0].Result.Name)
+	require.Equal(t, values.GoodCheckerStatus, results[0].Result.Status)
+
+	require.Equal(t, "B1", results[1].Bucket)
+	require.Equal(t, values.WarnCheckerStatus, results[1].Result.Status)
+	require.JSONEq(t, `{"indexes":["single"]}`, string(results[1].Result.Value))
+
+	// the index status is cached so other checkers do not request it again
+	_, err = tooManyIndexReplicasCheck(resources)
+	require.NoError(t, err)
+}
+
+func TestBadRedundantIndexCheck(t *testing.T) {
+	t.Run("same-node", func(t *testing.T) {
+		results, err := badRedundantIndexCheck(mockClientResources(t, indexCluster, "GetIndexStatus",
+			[]*values.IndexStatus{
+				testIndex("B0", "idx-0", 0, "CREATE INDEX `idx-0` ON `B0`(`a`)", "h1:8091"),
+				testIndex("B0", "idx-1", 0, "CREATE INDEX `idx-1` ON `B0`(`a`)", "h1:8091"),
+			}, nil))
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, values.WarnCheckerStatus, results[0].Result.Status)
+		require.JSONEq(t, `{"indexes":[{"indexes":["idx-0","idx-1"],"node":"h1:8091"}]}`,
+			string(results[0].Result.Value))
+	})
+
+	t.Run("partitions-on-same-node", func(t *testing.T) {
+		index := testIndex("B0", "idx", 0, "CREATE INDEX `idx` ON `B0`(`a`)", "h1:8091", "h2:8091")
+		index.Partitioned = true
+		index.PartitionMap = map[string][]int{"h1:8091": {1}, "h2:8091": {2}}
+
+		replica := testIndex("B0", "idx", 1, "CREATE INDEX `idx` ON `B0`(`a`)", "h1:8091", "h2:8091")
+		replica.Partitioned = true
+		replica.PartitionMap = map[string][]int{"h1:8091": {2}, "h2:8091": {1}}
+
+		resources := mockClientResources(t, indexCluster, "GetIndexStatus", []*values.IndexStatus{index, replica}, nil)
+		client := resources.client.(*mocks.ClientIFace)
+		client.On("GetServerGroups", mock.Anything).Return([]values.ServerGroup{{
+			Name:  "Group 1",
+			Nodes: []values.GroupNodes{{Hostname: "h1:8091"}, {Hostname: "h2:8091"}},
+		}}, nil).Once()
+
+		results, err := badRedundantIndexCheck(resources)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, values.GoodCheckerStatus, results[0].Result.Status)
+	})
+
+	t.Run("same-server-group", func(t *testing.T) {
+		resources := mockClientResources(t, indexCluster, "GetIndexStatus", []*values.IndexStatus{
+			testIndex("B0", "idx", 0, "CREATE INDEX `idx` ON `B0`(`a`)", "h1:8091"),
+			testIndex("B0", "idx", 1, "CREATE INDEX `idx` ON `B0`(`a`)", "h2:8091"),
+		}, nil)
+		client := resources.client.(*mocks.ClientIFace)
+		client.On("GetServerGroups", mock.Anything).Return([]values.ServerGroup{
+			{Name: "Group 1", Nodes: []values.GroupNodes{{Hostname: "h1:8091"}, {Hostname: "h2:8091"}}},
+			{Name: "Group 2", Nodes: []values.GroupNodes{{Hostname: "h0:8091"}}},
+		}, nil).Once()
+
+		results, err := badRedundantIndexCheck(resources)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, values.WarnCheckerStatus, results[0].Result.Status)
+		require.JSONEq(t, `{"indexes":[{"indexes":["idx","idx (replica 1)"],"server_group":"Group 1"}]}`,
+			string(results[0].Result.Value))
+	})
+}
+
+func TestTooManyIndexReplicasCheck(t *testing.T) {
+	index := testIndex("B0", "idx", 0, "CREATE INDEX `idx` ON `B0`(`a`)", "h1:8091")
+	index.Scope = "inventory"
+	index.Collection = "airline"
+	index.NumReplica = 2
+
+	fine := testIndex("B0", "fine", 0, "CREATE INDEX `fine` ON `B0`(`b`)", "h1:8091")
+	fine.NumReplica = 1
+
+	results, err := tooManyIndexReplicasCheck(mockClientResources(t, indexCluster, "GetIndexStatus",
+		[]*values.IndexStatus{index, fine}, nil))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, values.WarnCheckerStatus, results[0].Result.Status)
+	require.JSONEq(t, `{"indexes":[{"index":"inventory.airline.idx","num_replica":2,"index_nodes":2}]}`,
+		string(results[0].Result.Value))
+}
+
+func TestMissingIndexPartitionsCheck(t *testing.T) {
+	complete := testIndex("B0", "complete", 0, "CREATE INDEX `complete` ON `B0`(`a`)", "h1:8091", "h2:8091")
+	complete.Partitioned = true
+	complete.NumPartition = 4
+	complete.PartitionMap = map[string][]int{"h1:8091": {1, 3}, "h2:8091": {2, 4}}
+
+	missing := testIndex("B1", "missing", 1, "CREATE INDEX `missing` ON `B1`(`a`)", "h1:8091")
+	missing.Partitioned = true
+	missing.NumPartition = 4
+	missing.PartitionMap = map[string][]int{"h1:8091": {1, 3}}
+
+	results, err := missingIndexPartitionsCheck(mockClientResources(t, indexCluster, "GetIndexStatus",
+		[]*values.IndexStatus{complete, missing}, nil))
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	require.Equal(t, "B0", results[0].Bucket)
+	require.Equal(t, values.GoodCheckerStatus, results[0].Result.Status)
+
+	require.Equal(t, "B1", results[1].Bucket)
+	require.Equal(t, values.AlertCheckerStatus, results[1].Result.Status)
+	require.JSONEq(t, `{"indexes":[{"index":"missing (replica 1)","partitions":2,"num_partitions":4}]}`,
+		string(results[1].Result.Value))
+}
+
+func TestImbalancedIndexPartitionsCheck(t *testing.T) {
+	stat := func(name string, partition, memory int) *values.IndexStatsStorage {
+		stats := &values.IndexStatsStorage{Name: name, PartitionID: partition}
+		stats.Stats.IndexMemory = memory
+		return stats
+	}
+
+	client := &mocks.ClientIFace{}
+	client.On("GetIndexStorageStats", mock.Anything).Return([]*values.IndexStatsStorage{
+		stat("B0:balanced", 1, 100),
+		stat("B0:balanced", 2, 110),
+		stat("B1:imbalanced", 1, 100),
+		stat("B1:imbalanced", 2, 100),
+		stat("B1:imbalanced", 3, 200),
+		stat("B2:not-partitioned", 0, 500),
+	}, nil).Once()
+
+	results, err := imbalancedIndexPartitionsCheck(&clusterResources{cluster: indexCluster, client: client})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	require.Equal(t, "B0", results[0].Bucket)
+	require.Equal(t, values.GoodCheckerStatus, results[0].Result.Status)
+
+	require.Equal(t, "B1", results[1].Bucket)
+	require.Equal(t, values.WarnCheckerStatus, results[1].Result.Status)
+	require.JSONEq(t, `{"indexes":[{"index":"B1:imbalanced","partition_id":3,"memory_size":200,`+
+		`"average_memory_size":100}]}`, string(results[1].Result.Value))
+}
+
+func TestGSILogLevelCheck(t *testing.T) {
+	client := &mocks.ClientIFace{}
+	client.On("GetGSISettings", mock.Anything).Return(&values.GSISettings{LogLevel: values.Debug}, nil).Once()
+
+	results, err := gsiLogLevelCheck(&clusterResources{cluster: indexCluster, client: client})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	for i, node := range []string{"N1", "N2"} {
+		require.Equal(t, node, results[i].Node)
+		require.Equal(t, values.WarnCheckerStatus, results[i].Result.Status)
+		require.JSONEq(t, `{"log_level":"Debug"}`, string(results[i].Result.Value))
+	}
+}
@@ -0,0 +1,22 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package status
+
+import (
+	"context"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+)
+
+//go:generate mockery --name MonitorIFace
+
+type MonitorIFace interface {
+	Start(frequency time.Duration)
+	Stop()
+	Reconfigure(frequency time.Duration)
+	CheckCluster(ctx context.Context, cluster *values.CouchbaseCluster) error
+}
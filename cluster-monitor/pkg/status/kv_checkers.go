@@ -0,0 +1,346 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package status
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/memcached"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/couchbase/tools-common/cbvalue"
+	"github.com/couchbase/tools-common/netutil"
+)
+
+const (
+	// dcpPausedMinVersion and dcpPausedFixedVersion are the range of versions affected by MB-46482.
+	dcpPausedMinVersion   = cbvalue.Version("6.5.0")
+	dcpPausedFixedVersion = cbvalue.Version("6.6.3")
+
+	// dcpBufferLogFull is the paused reason of the DCP replications stuck because of MB-46482.
+	dcpBufferLogFull = "BufferLogFull"
+
+	// maxHeapFragmentation is the percentage of the memcached heap that can be fragmented before it is reported.
+	maxHeapFragmentation = 15
+)
+
+// dcpPausedCheck produces a result for every Data Service node and bucket when any node runs a version affected by
+// MB-46482. It alerts if any of the node's DCP replications are paused because the buffer log is full and warns if
+// more synchronous writes were accepted than fit in the DCP buffer.
+func dcpPausedCheck(resources *clusterResources) ([]*values.WrappedCheckerResult, error) {
+	affected := make(map[string]bool)
+	for _, node := range resources.cluster.NodesSummary {
+		version := cbvalue.Version(strings.SplitN(node.Version, "-", 2)[0])
+		if node.Version != "" && version.AtLeast(dcpPausedMinVersion) && version.Older(dcpPausedFixedVersion) {
+			affected[node.NodeUUID] = true
+		}
+	}
+
+	if len(affected) == 0 {
+		return nil, nil
+	}
+
+	type pausedStream struct {
+		Source      string `json:"source"`
+		Destination string `json:"destination"`
+		Reason      string `json:"reason"`
+	}
+
+	return kvBucketResults(resources, func(client memcached.ConnIFace,
+		bucket values.BucketSummary) ([]*values.WrappedCheckerResult, error) {
+		dcpStats, err := client.DCPStats(bucket.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		defaultStats, err := client.DefaultStats(bucket.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		syncWrites := make(map[string]string, len(defaultStats))
+		for _, stats := range defaultStats {
+			syncWrites[stats.Host] = stats.VbActiveSyncAccepted
+		}
+
+		results := make([]*values.WrappedCheckerResult, 0, len(dcpStats))
+		for _, stats := range dcpStats {
+			node, err := kvNodeUUID(resources, stats.Host)
+			if err != nil {
+				return nil, err
+			}
+
+			status := values.GoodCheckerStatus
+			var value interface{}
+			if affected[node] {
+				paused := make([]pausedStream, 0)
+				for _, stream := range stats.PausedReason {
+					if stream.Value == dcpBufferLogFull {
+						paused = append(paused, pausedStream{
+							Source:      stream.Source,
+							Destination: stream.Destination,
+							Reason:      stream.Value,
+						})
+					}
+				}
+
+				accepted, maxBuffer, err := syncWritesOverBuffer(syncWrites[stats.Host], stats.MaxBufferBytes)
+				if err != nil {
+					return nil, err
+				}
+
+				switch {
+				case len(paused) > 0:
+					status = values.AlertCheckerStatus
+					value = map[string]interface{}{"paused_streams": paused}
+				case accepted > maxBuffer:
+					status = values.WarnCheckerStatus
+					value = map[string]uint64{"sync_writes_accepted": accepted, "max_buffer_bytes": maxBuffer}
+				}
+			}
+
+			result, err := newResult(values.CheckDCPPaused, status, value)
+			if err != nil {
+				return nil, err
+			}
+
+			results = append(results, &values.WrappedCheckerResult{Result: result, Node: node, Bucket: bucket.Name})
+		}
+
+		return results, nil
+	})
+}
+
+// syncWritesOverBuffer returns the number of synchronous writes accepted and the smallest DCP buffer of the
+// replication streams. If there are no replication streams the buffer is reported as big enough.
+func syncWritesOverBuffer(accepted string, buffers []memcached.ReplicationStat) (uint64, uint64, error) {
+	if accepted == "" || len(buffers) == 0 {
+		return 0, 0, nil
+	}
+
+	acceptedCount, err := strconv.ParseUint(accepted, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("could not parse synchronous writes accepted: %w", err)
+	}
+
+	var smallest uint64
+	for i, buffer := range buffers {
+		size, err := strconv.ParseUint(buffer.Value, 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("could not parse DCP max buffer bytes: %w", err)
+		}
+
+		if i == 0 || size < smallest {
+			smallest = size
+		}
+	}
+
+	return acceptedCount, smallest, nil
+}
+
+// largeCheckpointsCheck produces a result for every Data Service node and bucket, warning about the vBuckets with
+// checkpoints that use more memory than the configured limit or percentage of the bucket quota, or that have more
+// items than the configured limit.
+func largeCheckpointsCheck(resources *clusterResources) ([]*values.WrappedCheckerResult, error) {
+	type largeCheckpoint struct {
+		VBucket  int    `json:"vbucket"`
+		MemUsage uint64 `json:"mem_usage"`
+		Items    uint64 `json:"items"`
+	}
+
+	thresholds := resources.thresholds
+	return kvBucketResults(resources, func(client memcached.ConnIFace,
+		bucket values.BucketSummary) ([]*values.WrappedCheckerResult, error) {
+		hosts := append([]string(nil), client.Hosts()...)
+		sort.Strings(hosts)
+
+		results := make([]*values.WrappedCheckerResult, 0, len(hosts))
+		for _, host := range hosts {
+			node, err := kvNodeUUID(resources, host)
+			if err != nil {
+				return nil, err
+			}
+
+			stats, err := client.CheckpointStats(host, bucket.Name)
+			if err != nil {
+				return nil, err
+			}
+
+			large := make([]largeCheckpoint, 0)
+			for vb, vbStats := range stats {
+				// vBuckets not on the node have no stats
+				if vbStats == nil {
+					continue
+				}
+
+				memUsage, err := parseOptionalUint(vbStats["mem_usage"])
+				if err != nil {
+					return nil, fmt.Errorf("could not parse checkpoint memory usage of vBucket %d: %w", vb, err)
+				}
+
+				items, err := parseOptionalUint(vbStats["num_checkpoint_items"])
+				if err != nil {
+					return nil, fmt.Errorf("could not parse checkpoint items of vBucket %d: %w", vb, err)
+				}
+
+				if memUsage > thresholds.LargeCheckpointMemory ||
+					(bucket.Quota > 0 && percentage(memUsage, bucket.Quota) > thresholds.LargeCheckpointQuota) ||
+					(thresholds.LargeCheckpointItems > 0 && items > thresholds.LargeCheckpointItems) {
+					large = append(large, largeCheckpoint{VBucket: vb, MemUsage: memUsage, Items: items})
+				}
+			}
+
+			status := values.GoodCheckerStatus
+			var value interface{}
+			if len(large) > 0 {
+				status = values.WarnCheckerStatus
+				value = map[string]interface{}{"vbuckets": large}
+			}
+
+			result, err := newResult(values.CheckLargeCheckpoints, status, value)
+			if err != nil {
+				return nil, err
+			}
+
+			results = append(results, &values.WrappedCheckerResult{Result: result, Node: node, Bucket: bucket.Name})
+		}
+
+		return results, nil
+	})
+}
+
+// memcachedFragmentationCheck produces a result for every Data Service node and bucket, warning if more than
+// maxHeapFragmentation percent of the memcached heap is fragmented.
+func memcachedFragmentationCheck(resources *clusterResources) ([]*values.WrappedCheckerResult, error) {
+	return kvBucketResults(resources, func(client memcached.ConnIFace,
+		bucket values.BucketSummary) ([]*values.WrappedCheckerResult, error) {
+		memStats, err := client.MemStats(bucket.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		results := make([]*values.WrappedCheckerResult, 0, len(memStats))
+		for _, stats := range memStats {
+			node, err := kvNodeUUID(resources, stats.Host)
+			if err != nil {
+				return nil, err
+			}
+
+			fragmentation, err := parseOptionalUint(stats.FragmentationBytes)
+			if err != nil {
+				return nil, fmt.Errorf("could not parse fragmentation bytes: %w", err)
+			}
+
+			heap, err := parseOptionalUint(stats.HeapBytes)
+			if err != nil {
+				return nil, fmt.Errorf("could not parse heap bytes: %w", err)
+			}
+
+			var fragmented float64
+			if heap > 0 {
+				fragmented = percentage(fragmentation, heap)
+			}
+
+			status := values.GoodCheckerStatus
+			if fragmented > maxHeapFragmentation {
+				status = values.WarnCheckerStatus
+			}
+
+			result, err := newResult(values.CheckMemcachedFragmentation, status, map[string]interface{}{
+				"fragmentation_bytes":   fragmentation,
+				"heap_bytes":            heap,
+				"fragmentation_percent": fragmented,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			results = append(results, &values.WrappedCheckerResult{Result: result, Node: node, Bucket: bucket.Name})
+		}
+
+		return results, nil
+	})
+}
+
+// kvBucketResults runs check for every bucket with data in memcached, returning all the results sorted by bucket and
+// node. Memcached buckets are skipped as they do not replicate or checkpoint.
+func kvBucketResults(resources *clusterResources, check func(client memcached.ConnIFace,
+	bucket values.BucketSummary) ([]*values.WrappedCheckerResult, error)) ([]*values.WrappedCheckerResult, error) {
+	buckets := make(values.BucketsSummary, 0, len(resources.cluster.BucketsSummary))
+	for _, bucket := range resources.cluster.BucketsSummary {
+		if bucket.BucketType != "memcached" {
+			buckets = append(buckets, bucket)
+		}
+	}
+
+	if len(buckets) == 0 {
+		return nil, nil
+	}
+
+	client, err := resources.getMemcachedClient()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*values.WrappedCheckerResult, 0)
+	for _, bucket := range buckets {
+		bucketResults, err := check(client, bucket)
+		if err != nil {
+			return nil, fmt.Errorf("could not check bucket '%s': %w", bucket.Name, err)
+		}
+
+		sort.Slice(bucketResults, func(i, j int) bool { return bucketResults[i].Node < bucketResults[j].Node })
+		results = append(results, bucketResults...)
+	}
+
+	return results, nil
+}
+
+// kvNodeUUID returns the UUID of the node memcached is listening on the given host for. Nodes are matched by the
+// host-port memcached listens on, as several nodes can share an address. Nodes summarised before the KV host was known
+// are matched by host name, as long as only one node has that host name.
+func kvNodeUUID(resources *clusterResources, kvHost string) (string, error) {
+	kvHost = netutil.TrimSchema(kvHost)
+	host, _, err := net.SplitHostPort(kvHost)
+	if err != nil {
+		return "", fmt.Errorf("could not split memcached host '%s': %w", kvHost, err)
+	}
+
+	var hostMatches []string
+	for _, node := range resources.cluster.NodesSummary {
+		if node.KVHost != "" {
+			if node.KVHost == kvHost {
+				return node.NodeUUID, nil
+			}
+
+			continue
+		}
+
+		nodeHost, _, err := net.SplitHostPort(netutil.TrimSchema(node.Host))
+		if err == nil && nodeHost == host {
+			hostMatches = append(hostMatches, node.NodeUUID)
+		}
+	}
+
+	if len(hostMatches) == 1 {
+		return hostMatches[0], nil
+	}
+
+	return "", fmt.Errorf("no node found for memcached host '%s': %w", kvHost, values.ErrNotFound)
+}
+
+// parseOptionalUint parses a memcached stat, stats that were not returned are treated as zero.
+func parseOptionalUint(value string) (uint64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.ParseUint(value, 10, 64)
+}
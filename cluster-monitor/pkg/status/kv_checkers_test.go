@@ -0,0 +1,211 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package status
+
+import (
+	"testing"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/configuration"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/memcached"
+	memcachedmocks "github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/memcached/mocks"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/stretchr/testify/require"
+)
+
+func kvCluster(version string) *values.CouchbaseCluster {
+	return &values.CouchbaseCluster{
+		NodesSummary: values.NodesSummary{
+			{NodeUUID: "N0", Host: "http://h0:8091", KVHost: "h0:11210", Version: version},
+			{NodeUUID: "N1", Host: "http://h1:8091", KVHost: "h1:11210", Version: "7.0.3-7031-enterprise"},
+		},
+		BucketsSummary: values.BucketsSummary{
+			{Name: "B0", BucketType: "couchbase", Quota: 1024 * 1024 * 1024},
+			{Name: "M0", BucketType: "memcached"},
+		},
+	}
+}
+
+// kvResources returns resources that give the memcached client, failing the test if it is created more than once.
+func kvResources(t *testing.T, cluster *values.CouchbaseCluster,
+	client *memcachedmocks.ConnIFace) *clusterResources {
+	t.Cleanup(func() { client.AssertExpectations(t) })
+
+	var created bool
+	return &clusterResources{
+		cluster:    cluster,
+		thresholds: &configuration.DefaultCheckerThresholds,
+		newMemcachedClient: func() (memcached.ConnIFace, error) {
+			require.False(t, created, "memcached client created more than once")
+			created = true
+			return client, nil
+		},
+	}
+}
+
+func TestDCPPausedCheck(t *testing.T) {
+	dcpStats := func(host string, paused string) *memcached.DCPMemStats {
+		return &memcached.DCPMemStats{
+			Host: host,
+			PausedReason: []memcached.ReplicationStat{
+				{Name: "paused_reason", Source: "ns_1@h0", Destination: "ns_1@h1", Value: paused},
+			},
+			MaxBufferBytes: []memcached.ReplicationStat{
+				{Name: "max_buffer_bytes", Source: "ns_1@h0", Destination: "ns_1@h1", Value: "1000"},
+			},
+		}
+	}
+
+	t.Run("paused", func(t *testing.T) {
+		client := &memcachedmocks.ConnIFace{}
+		client.On("DCPStats", "B0").Return([]*memcached.DCPMemStats{
+			dcpStats("h1:11210", dcpBufferLogFull),
+			dcpStats("h0:11210", dcpBufferLogFull),
+		}, nil).Once()
+		client.On("DefaultStats", "B0").Return([]*memcached.DefStats{}, nil).Once()
+
+		results, err := dcpPausedCheck(kvResources(t, kvCluster("6.6.2-9588-enterprise"), client))
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+
+		require.Equal(t, "N0", results[0].Node)
+		require.Equal(t, "B0", results[0].Bucket)
+		require.Equal(t, values.AlertCheckerStatus, results[0].Result.Status)
+		require.JSONEq(t, `{"paused_streams":[{"source":"ns_1@h0","destination":"ns_1@h1","reason":"BufferLogFull"}]}`,
+			string(results[0].Result.Value))
+
+		// the fixed version is not affected
+		require.Equal(t, "N1", results[1].Node)
+		require.Equal(t, values.GoodCheckerStatus, results[1].Result.Status)
+	})
+
+	t.Run("sync-writes-over-buffer", func(t *testing.T) {
+		client := &memcachedmocks.ConnIFace{}
+		client.On("DCPStats", "B0").Return([]*memcached.DCPMemStats{dcpStats("h0:11210", "ReadyListEmpty")}, nil).
+			Once()
+		client.On("DefaultStats", "B0").Return([]*memcached.DefStats{
+			{Host: "h0:11210", VbActiveSyncAccepted: "1500"},
+		}, nil).Once()
+
+		results, err := dcpPausedCheck(kvResources(t, kvCluster("6.5.1-6299-enterprise"), client))
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, values.WarnCheckerStatus, results[0].Result.Status)
+		require.JSONEq(t, `{"sync_writes_accepted":1500,"max_buffer_bytes":1000}`, string(results[0].Result.Value))
+	})
+
+	t.Run("unaffected-cluster", func(t *testing.T) {
+		results, err := dcpPausedCheck(kvResources(t, kvCluster("7.0.3-7031-enterprise"), &memcachedmocks.ConnIFace{}))
+		require.NoError(t, err)
+		require.Empty(t, results)
+	})
+}
+
+func TestLargeCheckpointsCheck(t *testing.T) {
+	client := &memcachedmocks.ConnIFace{}
+	client.On("Hosts").Return([]string{"h1:11210", "h0:11210"})
+	client.On("CheckpointStats", "h0:11210", "B0").Return(memcached.BucketCheckpointStats{
+		{"mem_usage": "1000", "num_checkpoint_items": "10"},
+		nil,
+		{"mem_usage": "62914560", "num_checkpoint_items": "20"},
+	}, nil)
+	// above 1% of the bucket quota but below the memory limit
+	client.On("CheckpointStats", "h1:11210", "B0").Return(memcached.BucketCheckpointStats{
+		nil,
+		{"mem_usage": "20971520", "num_checkpoint_items": "30"},
+	}, nil)
+
+	resources := kvResources(t, kvCluster(""), client)
+
+	results, err := largeCheckpointsCheck(resources)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	require.Equal(t, "N0", results[0].Node)
+	require.Equal(t, "B0", results[0].Bucket)
+	require.Equal(t, values.WarnCheckerStatus, results[0].Result.Status)
+	require.JSONEq(t, `{"vbuckets":[{"vbucket":2,"mem_usage":62914560,"items":20}]}`, string(results[0].Result.Value))
+
+	require.Equal(t, "N1", results[1].Node)
+	require.Equal(t, values.WarnCheckerStatus, results[1].Result.Status)
+	require.JSONEq(t, `{"vbuckets":[{"vbucket":1,"mem_usage":20971520,"items":30}]}`, string(results[1].Result.Value))
+
+	t.Run("items", func(t *testing.T) {
+		thresholds := configuration.DefaultCheckerThresholds
+		thresholds.LargeCheckpointMemory = 100 * 1024 * 1024
+		thresholds.LargeCheckpointQuota = 100
+		thresholds.LargeCheckpointItems = 15
+		resources.thresholds = &thresholds
+
+		results, err := largeCheckpointsCheck(resources)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		require.JSONEq(t, `{"vbuckets":[{"vbucket":2,"mem_usage":62914560,"items":20}]}`,
+			string(results[0].Result.Value))
+		require.JSONEq(t, `{"vbuckets":[{"vbucket":1,"mem_usage":20971520,"items":30}]}`,
+			string(results[1].Result.Value))
+	})
+
+	client.On("Close").Return(nil).Once()
+	resources.close()
+}
+
+func TestMemcachedFragmentationCheck(t *testing.T) {
+	client := &memcachedmocks.ConnIFace{}
+	client.On("MemStats", "B0").Return([]*memcached.MemoryStats{
+		{Host: "h1:11210", FragmentationBytes: "10", HeapBytes: "100"},
+		{Host: "h0:11210", FragmentationBytes: "20", HeapBytes: "100"},
+	}, nil).Once()
+
+	results, err := memcachedFragmentationCheck(kvResources(t, kvCluster(""), client))
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	require.Equal(t, "N0", results[0].Node)
+	require.Equal(t, values.WarnCheckerStatus, results[0].Result.Status)
+	require.JSONEq(t, `{"fragmentation_bytes":20,"heap_bytes":100,"fragmentation_percent":20}`,
+		string(results[0].Result.Value))
+
+	require.Equal(t, "N1", results[1].Node)
+	require.Equal(t, values.GoodCheckerStatus, results[1].Result.Status)
+}
+
+func TestKVNodeUUID(t *testing.T) {
+	t.Run("unknownHost", func(t *testing.T) {
+		_, err := kvNodeUUID(&clusterResources{cluster: kvCluster("")}, "h2:11210")
+		require.ErrorIs(t, err, values.ErrNotFound)
+	})
+
+	t.Run("sharedAddress", func(t *testing.T) {
+		cluster := &values.CouchbaseCluster{NodesSummary: values.NodesSummary{
+			{NodeUUID: "N0", Host: "http://127.0.0.1:9000", KVHost: "127.0.0.1:12000"},
+			{NodeUUID: "N1", Host: "http://127.0.0.1:9001", KVHost: "127.0.0.1:12002"},
+		}}
+
+		node, err := kvNodeUUID(&clusterResources{cluster: cluster}, "127.0.0.1:12002")
+		require.NoError(t, err)
+		require.Equal(t, "N1", node)
+
+		_, err = kvNodeUUID(&clusterResources{cluster: cluster}, "127.0.0.1:12004")
+		require.ErrorIs(t, err, values.ErrNotFound)
+	})
+
+	t.Run("withoutKVHost", func(t *testing.T) {
+		cluster := &values.CouchbaseCluster{NodesSummary: values.NodesSummary{
+			{NodeUUID: "N0", Host: "http://h0:8091"},
+			{NodeUUID: "N1", Host: "http://127.0.0.1:9000"},
+			{NodeUUID: "N2", Host: "http://127.0.0.1:9001"},
+		}}
+
+		node, err := kvNodeUUID(&clusterResources{cluster: cluster}, "h0:11210")
+		require.NoError(t, err)
+		require.Equal(t, "N0", node)
+
+		// the host name alone is ambiguous
+		_, err = kvNodeUUID(&clusterResources{cluster: cluster}, "127.0.0.1:12000")
+		require.ErrorIs(t, err, values.ErrNotFound)
+	})
+}
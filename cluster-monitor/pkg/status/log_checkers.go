@@ -0,0 +1,177 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/couchbase"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+)
+
+// logScanTimeout is how long fetching and scanning a single log file can take.
+const logScanTimeout = 10 * time.Minute
+
+// logPattern is what a log checker looks for in the lines of a log file.
+type logPattern struct {
+	checker string
+	regex   *regexp.Regexp
+}
+
+// logFile is a log that is scanned on every node, together with the patterns the checkers look for in it.
+type logFile struct {
+	// name is the log file the results are given for.
+	name     string
+	open     func(ctx context.Context, client couchbase.ClientIFace) (io.ReadCloser, error)
+	patterns []logPattern
+	// unordered is set for the logs whose timestamps do not always increase, see logScanner.scan.
+	unordered bool
+}
+
+var (
+	dataLossPattern = logPattern{
+		checker: values.CheckDataLoss,
+		regex:   regexp.MustCompile(`Data has been lost for|Lost data in`),
+	}
+
+	segmentationFaultPattern = logPattern{
+		checker: values.CheckSegmentationFaults,
+		regex:   regexp.MustCompile(`segfault at [0-9a-fA-F]+`),
+	}
+
+	// the babysitter logs the services that exit unexpectedly, a zero status is a clean shutdown
+	managedProcessCrashPattern = logPattern{
+		checker: values.CheckManagedProcessCrash,
+		regex:   regexp.MustCompile(`Service '?[\w-]+'? exited with status [1-9]\d*`),
+	}
+
+	oomKillPattern = logPattern{
+		checker: values.CheckOOMKills,
+		regex:   regexp.MustCompile(`Out of memory: Kill(?:ed)? process|invoked oom-killer`),
+	}
+
+	synFloodingPattern = logPattern{
+		checker: values.CheckSYNFlooding,
+		regex:   regexp.MustCompile(`SYN flooding on port \d+`),
+	}
+
+	cpuSoftLockupPattern = logPattern{
+		checker: values.CheckCPUSoftLockup,
+		regex:   regexp.MustCompile(`soft lockup - CPU#\d+ stuck`),
+	}
+
+	connTrackingTableFullPattern = logPattern{
+		checker: values.CheckConnTrackingTableFull,
+		regex:   regexp.MustCompile(`conntrack: table full, dropping packet`),
+	}
+)
+
+// scannedLogFiles are the logs fetched from every node. The diag includes the kernel messages and the user logs.
+var scannedLogFiles = []logFile{
+	{
+		name:      "diag",
+		unordered: true,
+		open: func(ctx context.Context, client couchbase.ClientIFace) (io.ReadCloser, error) {
+			return client.GetDiagLog(ctx)
+		},
+		patterns: []logPattern{
+			dataLossPattern, segmentationFaultPattern, oomKillPattern, synFloodingPattern, cpuSoftLockupPattern,
+			connTrackingTableFullPattern,
+		},
+	},
+	saslLogFile("babysitter", managedProcessCrashPattern),
+	saslLogFile("info", dataLossPattern),
+}
+
+func saslLogFile(name string, patterns ...logPattern) logFile {
+	return logFile{
+		name: name + ".log",
+		open: func(ctx context.Context, client couchbase.ClientIFace) (io.ReadCloser, error) {
+			return client.GetSASLLogs(ctx, name)
+		},
+		patterns: patterns,
+	}
+}
+
+// logCheck returns a checker that produces a result for every node and log file the checker looks at, with the
+// given status if the checker's pattern was logged within the log check lifetime.
+func logCheck(name string, status values.CheckerStatus) clusterCheckerFn {
+	return func(resources *clusterResources) ([]*values.WrappedCheckerResult, error) {
+		results := make([]*values.WrappedCheckerResult, 0)
+		for _, node := range resources.cluster.NodesSummary {
+			for _, file := range scannedLogFiles {
+				if !file.hasChecker(name) {
+					continue
+				}
+
+				matches, err := resources.getLogMatches(node, file)
+				if errors.Is(err, values.ErrNotFound) {
+					continue
+				}
+
+				if err != nil {
+					return nil, err
+				}
+
+				resultStatus := values.GoodCheckerStatus
+				var value interface{}
+				if len(matches[name]) > 0 {
+					resultStatus = status
+					value = map[string][]logMatch{"matches": matches[name]}
+				}
+
+				result, err := newResult(name, resultStatus, value)
+				if err != nil {
+					return nil, err
+				}
+
+				results = append(results, &values.WrappedCheckerResult{
+					Result:  result,
+					Node:    node.NodeUUID,
+					LogFile: file.name,
+				})
+			}
+		}
+
+		return results, nil
+	}
+}
+
+func (f logFile) hasChecker(name string) bool {
+	for _, pattern := range f.patterns {
+		if pattern.checker == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// scanLog fetches the log file from the node and scans it.
+func scanLog(ctx context.Context, scanner *logScanner, cluster string, node values.NodeSummary, file logFile,
+	client couchbase.ClientIFace) (map[string][]logMatch, error) {
+	ctx, cancel := context.WithTimeout(ctx, logScanTimeout)
+	defer cancel()
+
+	log, err := file.open(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("could not get log '%s': %w", file.name, err)
+	}
+	defer log.Close()
+
+	matches, err := scanner.scan(logFileKey{cluster: cluster, node: node.NodeUUID, file: file.name}, file.patterns,
+		file.unordered, log, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("could not scan log '%s': %w", file.name, err)
+	}
+
+	return matches, nil
+}
@@ -0,0 +1,129 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/couchbase"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/couchbase/mocks"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// logResources returns resources with node clients that return the given logs, each of them only once. Logs that are
+// not given are not found.
+func logResources(t *testing.T, logs map[string]map[string]string) *clusterResources {
+	return &clusterResources{
+		ctx:        context.Background(),
+		cluster:    testCluster,
+		logScanner: newLogScanner(time.Hour),
+		newNodeClient: func(node values.NodeSummary) (couchbase.ClientIFace, error) {
+			open := func(file string) (io.ReadCloser, error) {
+				log, ok := logs[node.NodeUUID][file]
+				if !ok {
+					return nil, values.ErrNotFound
+				}
+
+				return io.NopCloser(strings.NewReader(log)), nil
+			}
+
+			diag, diagErr := open("diag")
+			babysitter, babysitterErr := open("babysitter")
+			info, infoErr := open("info")
+
+			client := &mocks.ClientIFace{}
+			client.On("GetDiagLog", mock.Anything).Return(diag, diagErr).Once()
+			client.On("GetSASLLogs", mock.Anything, "babysitter").Return(babysitter, babysitterErr).Once()
+			client.On("GetSASLLogs", mock.Anything, "info").Return(info, infoErr).Once()
+			t.Cleanup(func() { client.AssertExpectations(t) })
+			return client, nil
+		},
+	}
+}
+
+func TestLogCheckers(t *testing.T) {
+	now := time.Now().UTC().Add(-time.Minute).Format(time.RFC3339Nano)
+	resources := logResources(t, map[string]map[string]string{
+		"N0": {
+			"diag": fmt.Sprintf("%s ns_1@10.0.0.1:0:warning:message - Data has been lost for 50%% of vbuckets in "+
+				"bucket \"B0\".\n[100.0] Out of memory: Killed process 1234 (memcached)\n", now),
+			"babysitter": "",
+			"info":       "",
+		},
+		"N1": {
+			"diag": "[100.0] TCP: request_sock_TCP: Possible SYN flooding on port 11210. Sending cookies.\n",
+			"info": fmt.Sprintf("[user:warn,%s,ns_1@10.0.0.2:<0.1.0>:failover:42]Lost data in \"B0\" for [1,2]\n",
+				now),
+		},
+	})
+
+	results, err := logCheck(values.CheckDataLoss, values.AlertCheckerStatus)(resources)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	expected := []struct {
+		node   string
+		file   string
+		status values.CheckerStatus
+	}{
+		{node: "N0", file: "diag", status: values.AlertCheckerStatus},
+		{node: "N0", file: "info.log", status: values.GoodCheckerStatus},
+		{node: "N1", file: "diag", status: values.GoodCheckerStatus},
+		{node: "N1", file: "info.log", status: values.AlertCheckerStatus},
+	}
+
+	for i, result := range results {
+		require.Equal(t, expected[i].node, result.Node)
+		require.Equal(t, expected[i].file, result.LogFile)
+		require.Equal(t, values.CheckDataLoss, result.Result.Name)
+		require.Equal(t, expected[i].status, result.Result.Status)
+	}
+
+	require.Contains(t, string(results[3].Result.Value), `Lost data in \"B0\"`)
+
+	// the logs are only fetched once per check, the babysitter log not being found is not an error
+	for checker, expected := range map[string][]values.CheckerStatus{
+		values.CheckOOMKills:            {values.AlertCheckerStatus, values.GoodCheckerStatus},
+		values.CheckSYNFlooding:         {values.GoodCheckerStatus, values.AlertCheckerStatus},
+		values.CheckManagedProcessCrash: {values.GoodCheckerStatus},
+	} {
+		results, err := logCheck(checker, values.AlertCheckerStatus)(resources)
+		require.NoError(t, err)
+		require.Len(t, results, len(expected), checker)
+
+		for i, status := range expected {
+			require.Equal(t, status, results[i].Result.Status, checker)
+		}
+	}
+}
+
+func TestLogCheckersError(t *testing.T) {
+	resources := &clusterResources{
+		ctx:        context.Background(),
+		cluster:    testCluster,
+		logScanner: newLogScanner(time.Hour),
+		newNodeClient: func(node values.NodeSummary) (couchbase.ClientIFace, error) {
+			client := &mocks.ClientIFace{}
+			client.On("GetDiagLog", mock.Anything).Return(nil, fmt.Errorf("connection refused")).Once()
+			return client, nil
+		},
+	}
+
+	_, err := logCheck(values.CheckOOMKills, values.AlertCheckerStatus)(resources)
+	require.Error(t, err)
+
+	// the failure is cached as well
+	_, err = logCheck(values.CheckSYNFlooding, values.WarnCheckerStatus)(resources)
+	require.Error(t, err)
+}
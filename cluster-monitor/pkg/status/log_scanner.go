@@ -0,0 +1,289 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package status
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"hash/fnv"
+	"io"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+)
+
+const (
+	// maxLogLineLength is the longest a log line can be, the rest of the line is ignored so a single malformed line
+	// cannot use up the memory.
+	maxLogLineLength = 64 * 1024
+
+	// maxLogMatches is how many of the most recent matching lines are kept for each checker, node and log file.
+	maxLogMatches = 10
+
+	// logTimePrefixLength is how far into a line its timestamp is looked for.
+	logTimePrefixLength = 128
+)
+
+// logTimeRegex matches the timestamps at the start of the Couchbase Server log lines, for example
+// [ns_server:info,2021-06-01T10:00:00.123Z,ns_1@10.0.0.1:<0.1.0>:...] or 2021-06-01 10:00:00.123 in the diag UI logs.
+var logTimeRegex = regexp.MustCompile(`(\d{4}-\d{2}-\d{2})[T ](\d{2}:\d{2}:\d{2}(?:\.\d+)?)(Z|[+-]\d{2}:\d{2})?`)
+
+// logMatch is a log line that matched one of the checkers' patterns.
+type logMatch struct {
+	Time time.Time `json:"time"`
+	Line string    `json:"line"`
+}
+
+// logFileKey identifies a log file of a node.
+type logFileKey struct {
+	cluster string
+	node    string
+	file    string
+}
+
+// logFileState is how far a log file has been scanned and the matches that have not expired yet.
+type logFileState struct {
+	// lastTime is the timestamp of the last line scanned. It is what decides whether a line is new as the logs are
+	// returned from the start every time and they can be rotated between scans.
+	lastTime time.Time
+	// offset is the size of the log the last time it was scanned. It is only used for the lines before the first
+	// timestamp, which are new if they are past it.
+	offset int64
+	// seen counts the hashes of the matching lines found the last time an unordered log was scanned. It is what
+	// decides whether a line is new in those logs, as their timestamps say nothing about what comes after them.
+	seen map[uint64]int
+	// matches are the recent lines that matched for each checker, oldest first.
+	matches map[string][]logMatch
+}
+
+// logScanner scans the logs of the nodes for the patterns the log checkers look for, remembering how far each log was
+// scanned so only the new lines are looked at and so the matches are reported until they expire.
+type logScanner struct {
+	// lifetime is how long a match is reported for after the line was logged.
+	lifetime time.Duration
+
+	lock  sync.Mutex
+	state map[logFileKey]*logFileState
+}
+
+func newLogScanner(lifetime time.Duration) *logScanner {
+	return &logScanner{lifetime: lifetime, state: make(map[logFileKey]*logFileState)}
+}
+
+func (s *logScanner) getState(key logFileKey) *logFileState {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	state, ok := s.state[key]
+	if !ok {
+		state = &logFileState{matches: make(map[string][]logMatch)}
+		s.state[key] = state
+	}
+
+	return state
+}
+
+// forgetRemovedClusters drops the state of the logs of the clusters that are not in the given ones, so the state of
+// removed clusters is not kept forever.
+func (s *logScanner) forgetRemovedClusters(clusters []*values.CouchbaseCluster) {
+	current := make(map[string]bool, len(clusters))
+	for _, cluster := range clusters {
+		current[cluster.UUID] = true
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for key := range s.state {
+		if !current[key.cluster] {
+			delete(s.state, key)
+		}
+	}
+}
+
+// scan streams the log looking for the patterns in the lines logged since the last scan and returns the matches for
+// each checker that have not expired yet. Lines logged before the lifetime are ignored, so logs that were never scanned
+// before only report recent issues. A log is only scanned by one goroutine at a time as a cluster is only checked by
+// one worker at a time.
+//
+// The lines of an ordered log are new if they come after the last timestamp seen, lines without a timestamp taking the
+// one of the line before them. Unordered logs, which are made of several sections or have lines without a timestamp,
+// such as the kernel messages, are instead compared by content with the lines matched in the last scan. Their lines
+// only use their own timestamp, those without one are taken to have been logged when they are first seen.
+func (s *logScanner) scan(key logFileKey, patterns []logPattern, unordered bool, log io.Reader,
+	now time.Time) (map[string][]logMatch, error) {
+	state := s.getState(key)
+	cutoff := now.Add(-s.lifetime)
+
+	var (
+		lastTime time.Time
+		offset   int64
+		seen     = make(map[uint64]int)
+		found    = make(map[string][]logMatch)
+	)
+
+	err := readLogLines(log, func(line []byte, end int64) {
+		offset = end
+		lineTime, hasTime := parseLogTime(line)
+		if hasTime {
+			lastTime = lineTime
+		}
+
+		matchTime := now
+		if unordered {
+			if hasTime {
+				matchTime = lineTime
+			}
+		} else {
+			// lines without a timestamp belong to the last line with one
+			newLine := end > state.offset
+			if !lastTime.IsZero() {
+				newLine = lastTime.After(state.lastTime)
+				matchTime = lastTime
+			}
+
+			if !newLine {
+				return
+			}
+		}
+
+		if matchTime.Before(cutoff) {
+			return
+		}
+
+		checkers := make([]string, 0, 1)
+		for _, pattern := range patterns {
+			if pattern.regex.Match(line) {
+				checkers = append(checkers, pattern.checker)
+			}
+		}
+
+		if len(checkers) == 0 {
+			return
+		}
+
+		if unordered {
+			// a line repeated in the log is only new if it is there more times than in the last scan
+			hash := hashLogLine(line)
+			seen[hash]++
+			if seen[hash] <= state.seen[hash] {
+				return
+			}
+		}
+
+		for _, checker := range checkers {
+			found[checker] = appendLogMatch(found[checker], logMatch{Time: matchTime, Line: string(line)})
+		}
+	})
+	// the state is only updated if the whole log was scanned so the next scan picks up from the same place
+	if err != nil {
+		return nil, err
+	}
+
+	state.offset = offset
+	if lastTime.After(state.lastTime) {
+		state.lastTime = lastTime
+	}
+
+	if unordered {
+		state.seen = seen
+	}
+
+	matches := make(map[string][]logMatch, len(patterns))
+	for _, pattern := range patterns {
+		current := make([]logMatch, 0, maxLogMatches)
+		for _, match := range append(state.matches[pattern.checker], found[pattern.checker]...) {
+			if !match.Time.Before(cutoff) {
+				current = appendLogMatch(current, match)
+			}
+		}
+
+		state.matches[pattern.checker] = current
+		matches[pattern.checker] = current
+	}
+
+	return matches, nil
+}
+
+func hashLogLine(line []byte) uint64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write(line)
+	return hash.Sum64()
+}
+
+// appendLogMatch adds the match dropping the oldest one if there are already maxLogMatches.
+func appendLogMatch(matches []logMatch, match logMatch) []logMatch {
+	if len(matches) >= maxLogMatches {
+		matches = append(matches[:0], matches[len(matches)-maxLogMatches+1:]...)
+	}
+
+	return append(matches, match)
+}
+
+// readLogLines calls fn with every line in the log, without the line ending, and the offset of the end of the line. The
+// log is streamed so only one line is in memory at a time, lines longer than maxLogLineLength are truncated.
+func readLogLines(log io.Reader, fn func(line []byte, end int64)) error {
+	reader := bufio.NewReaderSize(log, maxLogLineLength)
+	line := make([]byte, 0, maxLogLineLength)
+
+	var offset int64
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		offset += int64(len(chunk))
+
+		if space := maxLogLineLength - len(line); space > 0 {
+			if len(chunk) > space {
+				chunk = chunk[:space]
+			}
+
+			line = append(line, chunk...)
+		}
+
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+
+		if len(line) > 0 {
+			fn(bytes.TrimRight(line, "\r\n"), offset)
+			line = line[:0]
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// parseLogTime returns the timestamp at the start of the line, times without a time zone are taken to be in UTC.
+func parseLogTime(line []byte) (time.Time, bool) {
+	if len(line) > logTimePrefixLength {
+		line = line[:logTimePrefixLength]
+	}
+
+	parts := logTimeRegex.FindSubmatch(line)
+	if parts == nil {
+		return time.Time{}, false
+	}
+
+	zone := string(parts[3])
+	if zone == "" {
+		zone = "Z"
+	}
+
+	parsed, err := time.Parse(time.RFC3339Nano, string(parts[1])+"T"+string(parts[2])+zone)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return parsed.UTC(), true
+}
@@ -0,0 +1,213 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package status
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadLogLines(t *testing.T) {
+	long := strings.Repeat("a", maxLogLineLength+10)
+	log := "first\r\n" + long + "\nlast"
+
+	type line struct {
+		text string
+		end  int64
+	}
+
+	lines := make([]line, 0)
+	require.NoError(t, readLogLines(strings.NewReader(log), func(text []byte, end int64) {
+		lines = append(lines, line{text: string(text), end: end})
+	}))
+
+	require.Equal(t, []line{
+		{text: "first", end: 7},
+		{text: long[:maxLogLineLength], end: int64(7 + len(long) + 1)},
+		{text: "last", end: int64(len(log))},
+	}, lines)
+}
+
+func TestParseLogTime(t *testing.T) {
+	cases := []struct {
+		line     string
+		expected time.Time
+		ok       bool
+	}{
+		{
+			line:     "[ns_server:info,2021-06-01T10:00:00.123Z,ns_1@10.0.0.1:<0.1.0>:ns_log:67]Message",
+			expected: time.Date(2021, 6, 1, 10, 0, 0, 123000000, time.UTC),
+			ok:       true,
+		},
+		{
+			line:     "[ns_server:info,2021-06-01T11:00:00.000+01:00,babysitter_of_ns_1@cb.local:<0.2.0>]",
+			expected: time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC),
+			ok:       true,
+		},
+		{
+			line:     "2021-06-01 10:00:00.500 ns_1@10.0.0.1:0:info:message",
+			expected: time.Date(2021, 6, 1, 10, 0, 0, 500000000, time.UTC),
+			ok:       true,
+		},
+		{line: "[12345.678901] segfault at 0 ip 00007f sp 00007f error 4"},
+		{line: strings.Repeat(" ", logTimePrefixLength) + "2021-06-01T10:00:00Z"},
+	}
+
+	for _, tc := range cases {
+		parsed, ok := parseLogTime([]byte(tc.line))
+		require.Equal(t, tc.ok, ok, tc.line)
+		require.Equal(t, tc.expected, parsed, tc.line)
+	}
+}
+
+var testLogPatterns = []logPattern{segmentationFaultPattern, managedProcessCrashPattern}
+
+func babysitterLine(at time.Time, message string) string {
+	return fmt.Sprintf("[ns_server:info,%s,babysitter_of_ns_1@cb.local:<0.2.0>:ns_port_server]%s\n",
+		at.Format(time.RFC3339Nano), message)
+}
+
+func TestLogScannerScan(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	scanner := newLogScanner(time.Hour)
+	key := logFileKey{cluster: "C0", node: "N0", file: "babysitter.log"}
+
+	crash := "Service 'memcached' exited with status 134. Restarting. Messages:"
+	log := babysitterLine(now.Add(-2*time.Hour), crash) +
+		babysitterLine(now.Add(-30*time.Minute), crash) +
+		babysitterLine(now.Add(-20*time.Minute), "Service 'memcached' exited with status 0. Restarting.") +
+		"continuation line without a timestamp\n"
+
+	matches, err := scanner.scan(key, testLogPatterns, false, strings.NewReader(log), now)
+	require.NoError(t, err)
+	// the crash logged before the lifetime is ignored
+	require.Len(t, matches[managedProcessCrashPattern.checker], 1)
+	require.Equal(t, now.Add(-30*time.Minute), matches[managedProcessCrashPattern.checker][0].Time)
+	require.Empty(t, matches[segmentationFaultPattern.checker])
+
+	t.Run("only-new-lines", func(t *testing.T) {
+		later := now.Add(10 * time.Minute)
+		log += babysitterLine(later.Add(-time.Minute), crash)
+
+		matches, err := scanner.scan(key, testLogPatterns, false, strings.NewReader(log), later)
+		require.NoError(t, err)
+		require.Len(t, matches[managedProcessCrashPattern.checker], 2)
+		require.Equal(t, now.Add(-30*time.Minute), matches[managedProcessCrashPattern.checker][0].Time)
+		require.Equal(t, later.Add(-time.Minute), matches[managedProcessCrashPattern.checker][1].Time)
+	})
+
+	t.Run("rotated", func(t *testing.T) {
+		later := now.Add(20 * time.Minute)
+		rotated := babysitterLine(later.Add(-time.Minute), crash)
+
+		matches, err := scanner.scan(key, testLogPatterns, false, strings.NewReader(rotated), later)
+		require.NoError(t, err)
+		require.Len(t, matches[managedProcessCrashPattern.checker], 3)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		matches, err := scanner.scan(key, testLogPatterns, false, strings.NewReader(""), now.Add(2*time.Hour))
+		require.NoError(t, err)
+		require.Empty(t, matches[managedProcessCrashPattern.checker])
+	})
+}
+
+func TestLogScannerScanWithoutTimestamps(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	scanner := newLogScanner(time.Hour)
+	key := logFileKey{cluster: "C0", node: "N0", file: "diag"}
+
+	log := "[100.000000] memcached[123]: segfault at 0 ip 00007f sp 00007f error 4\n"
+	matches, err := scanner.scan(key, testLogPatterns, true, strings.NewReader(log), now)
+	require.NoError(t, err)
+	require.Len(t, matches[segmentationFaultPattern.checker], 1)
+	require.Equal(t, now, matches[segmentationFaultPattern.checker][0].Time)
+
+	// the lines already scanned are not matched again
+	log += "[200.000000] indexer[456]: segfault at 0 ip 00007f sp 00007f error 4\n"
+	matches, err = scanner.scan(key, testLogPatterns, true, strings.NewReader(log), now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, matches[segmentationFaultPattern.checker], 2)
+	require.Contains(t, matches[segmentationFaultPattern.checker][1].Line, "indexer")
+}
+
+func TestLogScannerScanUnordered(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	scanner := newLogScanner(time.Hour)
+	key := logFileKey{cluster: "C0", node: "N0", file: "diag"}
+
+	// the kernel messages come after the user logs in the diag so without their own timestamp they would otherwise
+	// take the one of the last user log line, which is older than the lifetime here
+	userLogs := "2021-06-01 10:00:00.000 ns_1@10.0.0.1:0:info:message\n"
+	kernel := "[100.000000] memcached[123]: segfault at 0 ip 00007f sp 00007f error 4\n"
+	matches, err := scanner.scan(key, testLogPatterns, true, strings.NewReader(userLogs+kernel), now)
+	require.NoError(t, err)
+	require.Len(t, matches[segmentationFaultPattern.checker], 1)
+	require.Equal(t, now, matches[segmentationFaultPattern.checker][0].Time)
+
+	// newer user log lines are added before the kernel messages, which get a new line that is still new even though
+	// it comes after older timestamps
+	later := now.Add(10 * time.Minute)
+	userLogs += "2021-06-01 12:05:00.000 ns_1@10.0.0.1:0:info:message\n"
+	kernel = "[50.000000] kernel: soft lockup - CPU#0 stuck for 22s\n" + kernel +
+		"[200.000000] indexer[456]: segfault at 0 ip 00007f sp 00007f error 4\n" +
+		"[100.000000] memcached[123]: segfault at 0 ip 00007f sp 00007f error 4\n"
+
+	matches, err = scanner.scan(key, []logPattern{segmentationFaultPattern, cpuSoftLockupPattern}, true,
+		strings.NewReader(userLogs+kernel), later)
+	require.NoError(t, err)
+	require.Len(t, matches[cpuSoftLockupPattern.checker], 1)
+	require.Equal(t, later, matches[cpuSoftLockupPattern.checker][0].Time)
+
+	// the repeated memcached line is there once more than before so it is new
+	require.Len(t, matches[segmentationFaultPattern.checker], 3)
+	require.Equal(t, now, matches[segmentationFaultPattern.checker][0].Time)
+	require.Contains(t, matches[segmentationFaultPattern.checker][1].Line, "indexer")
+	require.Equal(t, later, matches[segmentationFaultPattern.checker][2].Time)
+
+	// scanning the same log again finds nothing new
+	matches, err = scanner.scan(key, []logPattern{segmentationFaultPattern, cpuSoftLockupPattern}, true,
+		strings.NewReader(userLogs+kernel), later.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, matches[segmentationFaultPattern.checker], 3)
+	require.Len(t, matches[cpuSoftLockupPattern.checker], 1)
+}
+
+func TestLogScannerMaxMatches(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	var log strings.Builder
+	for i := 0; i < maxLogMatches+5; i++ {
+		log.WriteString(babysitterLine(now.Add(time.Duration(i-30)*time.Second),
+			fmt.Sprintf("Service 'goxdcr' exited with status %d. Restarting.", i+1)))
+	}
+
+	matches, err := newLogScanner(time.Hour).scan(logFileKey{}, testLogPatterns, false,
+		strings.NewReader(log.String()), now)
+	require.NoError(t, err)
+	require.Len(t, matches[managedProcessCrashPattern.checker], maxLogMatches)
+	require.Contains(t, matches[managedProcessCrashPattern.checker][maxLogMatches-1].Line,
+		fmt.Sprintf("status %d.", maxLogMatches+5))
+}
+
+func TestLogScannerForgetRemovedClusters(t *testing.T) {
+	scanner := newLogScanner(time.Hour)
+	for _, cluster := range []string{"C0", "C1"} {
+		_, err := scanner.scan(logFileKey{cluster: cluster, node: "N0", file: "diag"}, testLogPatterns, true,
+			strings.NewReader(""), time.Now())
+		require.NoError(t, err)
+	}
+
+	scanner.forgetRemovedClusters([]*values.CouchbaseCluster{{UUID: "C1"}})
+	require.Len(t, scanner.state, 1)
+	require.Contains(t, scanner.state, logFileKey{cluster: "C1", node: "N0", file: "diag"})
+}
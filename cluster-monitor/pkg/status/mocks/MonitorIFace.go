@@ -0,0 +1,47 @@
+// Code generated by mockery v2.9.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	values "github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+)
+
+// MonitorIFace is an autogenerated mock type for the MonitorIFace type
+type MonitorIFace struct {
+	mock.Mock
+}
+
+// CheckCluster provides a mock function with given fields: ctx, cluster
+func (_m *MonitorIFace) CheckCluster(ctx context.Context, cluster *values.CouchbaseCluster) error {
+	ret := _m.Called(ctx, cluster)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *values.CouchbaseCluster) error); ok {
+		r0 = rf(ctx, cluster)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Reconfigure provides a mock function with given fields: frequency
+func (_m *MonitorIFace) Reconfigure(frequency time.Duration) {
+	_m.Called(frequency)
+}
+
+// Start provides a mock function with given fields: frequency
+func (_m *MonitorIFace) Start(frequency time.Duration) {
+	_m.Called(frequency)
+}
+
+// Stop provides a mock function with given fields:
+func (_m *MonitorIFace) Stop() {
+	_m.Called()
+}
@@ -0,0 +1,408 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/configuration"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/couchbase"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/memcached"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/scheduler"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/storage"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"go.uber.org/zap"
+)
+
+// ErrCheckInProgress is returned when asked to check a cluster that is already being checked.
+var ErrCheckInProgress = errors.New("status check already in progress for cluster")
+
+// Monitor is in charge of periodically running the checkers against all the enterprise clusters and storing the
+// results.
+type Monitor struct {
+	store storage.Store
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	workStream chan *values.CouchbaseCluster
+	numWorkers int
+	workerWg   sync.WaitGroup
+
+	clusterCheckers map[string]clusterCheckerFn
+	nodeCheckers    map[string]nodeCheckerFn
+	bucketCheckers  map[string]bucketCheckerFn
+
+	// logScanner keeps track of the node logs the log checkers have scanned.
+	logScanner *logScanner
+
+	// thresholds are the limits the checkers use to decide the status of their results.
+	thresholds configuration.CheckerThresholds
+
+	// clients are the REST clients shared with the rest of the manager.
+	clients *couchbase.ClientCache
+	// newClient creates the REST client given to the checkers, it is only swapped during testing.
+	newClient func(ctx context.Context, cluster *values.CouchbaseCluster) (couchbase.ClientIFace, error)
+	// newNodeClient creates a REST client that only talks to the given node, it is only swapped during testing.
+	newNodeClient func(ctx context.Context, cluster *values.CouchbaseCluster,
+		node values.NodeSummary) (couchbase.ClientIFace, error)
+	// newMemcachedClient creates the client for the Data Service nodes, it is only swapped during testing.
+	newMemcachedClient func(ctx context.Context, cluster *values.CouchbaseCluster) (memcached.ConnIFace, error)
+
+	inProgressLock sync.Mutex
+	inProgress     map[string]struct{}
+
+	// scheduler decides which clusters are due a status check given their frequencies.
+	scheduler *scheduler.Scheduler
+	// reconfigured is signalled when the frequencies change so the check loop can adjust its ticker.
+	reconfigured chan struct{}
+}
+
+// NewMonitor creates a monitor, the matches of the log checkers are reported for logCheckLifetime after they were
+// logged. The REST clients are taken from clients so they are shared with the rest of the manager.
+func NewMonitor(store storage.Store, workers int, thresholds configuration.CheckerThresholds,
+	logCheckLifetime time.Duration, clients *couchbase.ClientCache) *Monitor {
+	m := &Monitor{
+		store:           store,
+		numWorkers:      workers,
+		thresholds:      thresholds,
+		logScanner:      newLogScanner(logCheckLifetime),
+		clusterCheckers: defaultClusterCheckers(),
+		nodeCheckers:    defaultNodeCheckers(),
+		bucketCheckers:  defaultBucketCheckers(),
+		clients:         clients,
+		inProgress:      make(map[string]struct{}),
+		scheduler: scheduler.NewScheduler(store, func(frequencies *values.ClusterFrequencies) time.Duration {
+			return frequencies.Status
+		}, "(Status Monitor)"),
+		reconfigured: make(chan struct{}, 1),
+	}
+
+	m.newClient = m.newCouchbaseClient
+	m.newNodeClient = m.newCouchbaseNodeClient
+	m.newMemcachedClient = m.newCouchbaseMemcachedClient
+	return m
+}
+
+func (m *Monitor) newCouchbaseClient(ctx context.Context,
+	cluster *values.CouchbaseCluster) (couchbase.ClientIFace, error) {
+	client, err := m.clients.GetClient(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (m *Monitor) newCouchbaseNodeClient(ctx context.Context, cluster *values.CouchbaseCluster,
+	node values.NodeSummary) (couchbase.ClientIFace, error) {
+	client, err := m.clients.GetNodeClient(ctx, cluster, node.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (m *Monitor) newCouchbaseMemcachedClient(ctx context.Context,
+	cluster *values.CouchbaseCluster) (memcached.ConnIFace, error) {
+	client, err := memcached.NewMemcachedClient(ctx, cluster, m.clients)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (m *Monitor) Start(frequency time.Duration) {
+	// monitor already running
+	if m.ctx != nil {
+		return
+	}
+
+	zap.S().Infow("(Status Monitor) Starting monitor", "frequency", frequency)
+	m.scheduler.SetFrequency(frequency)
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	m.wg.Add(1)
+	go m.checkLoop()
+}
+
+// Reconfigure changes the default status check frequency and picks up any changes to the cluster frequencies. Checks
+// in progress are not interrupted, the new frequencies are used from the next tick.
+func (m *Monitor) Reconfigure(frequency time.Duration) {
+	zap.S().Infow("(Status Monitor) Reconfiguring monitor", "frequency", frequency)
+	m.scheduler.SetFrequency(frequency)
+
+	select {
+	case m.reconfigured <- struct{}{}:
+	default:
+	}
+}
+
+func (m *Monitor) Stop() {
+	// not running
+	if m.ctx == nil {
+		return
+	}
+
+	zap.S().Info("(Status Monitor) Stopping monitor")
+	m.cancel()
+	m.wg.Wait()
+	m.ctx, m.cancel = nil, nil
+}
+
+func (m *Monitor) checkLoop() {
+	tick := m.scheduler.TickInterval()
+	ticker := time.NewTicker(tick)
+	defer func() {
+		m.wg.Done()
+		ticker.Stop()
+	}()
+
+	// the status frequency is usually several minutes so do a first run straight away rather than waiting for the
+	// first tick
+	check := true
+	for {
+		if check {
+			if err := m.checkClusters(m.ctx, tick); err != nil {
+				zap.S().Warnw("(Status Monitor) There was an issue checking the clusters", "err", err.Error())
+			}
+		}
+
+		select {
+		case <-ticker.C:
+			check = true
+		case <-m.reconfigured:
+			check = false
+		case <-m.ctx.Done():
+			return
+		}
+
+		if newTick := m.scheduler.TickInterval(); newTick != tick {
+			zap.S().Infow("(Status Monitor) Changing tick interval", "old", tick, "new", newTick)
+			tick = newTick
+			ticker.Reset(tick)
+		}
+	}
+}
+
+// checkClusters checks the clusters that are due given their frequency and the tick interval. Cancelling ctx stops the
+// checks in progress and skips the clusters that have not been started.
+func (m *Monitor) checkClusters(ctx context.Context, tick time.Duration) error {
+	zap.S().Infow("(Status Monitor) Starting status checks")
+	start := time.Now()
+	allClusters, err := m.store.GetClusters(true, true)
+	if err != nil {
+		return fmt.Errorf("could not get clusters to check: %w", err)
+	}
+
+	m.logScanner.forgetRemovedClusters(allClusters)
+	clusters := m.scheduler.DueClusters(allClusters, start, tick)
+
+	m.workStream = make(chan *values.CouchbaseCluster)
+	// start the workers
+	for i := 0; i < m.numWorkers; i++ {
+		m.workerWg.Add(1)
+		go m.checkWorkerFn(ctx)
+	}
+
+	// send the data
+sendLoop:
+	for _, cluster := range clusters {
+		select {
+		case m.workStream <- cluster:
+		case <-ctx.Done():
+			break sendLoop
+		}
+	}
+
+	close(m.workStream)
+
+	// to avoid starting the next run before finishing this one we wait until all the workers are done
+	m.workerWg.Wait()
+
+	zap.S().Debugw("(Status Monitor) Status checks finished", "elapsed", time.Since(start).String(), "#clusters",
+		len(clusters))
+	return nil
+}
+
+func (m *Monitor) checkWorkerFn(ctx context.Context) {
+	defer m.workerWg.Done()
+
+	for cluster := range m.workStream {
+		if err := m.CheckCluster(ctx, cluster); err != nil {
+			zap.S().Errorw("(Status Monitor) Could not check cluster", "uuid", cluster.UUID, "err", err)
+		}
+	}
+}
+
+// CheckCluster runs all the checkers against the cluster and stores the results. All results are stored with a new
+// version, results from the previous versions that were not produced again are deleted. The exception is the results
+// of checkers that failed to run, those are carried over so that a transient failure does not hide existing issues.
+// If ctx is cancelled the check is abandoned and the previous results are kept as they were.
+func (m *Monitor) CheckCluster(ctx context.Context, cluster *values.CouchbaseCluster) error {
+	if !cluster.Enterprise {
+		return fmt.Errorf("status checks are only run against enterprise clusters")
+	}
+
+	if !m.markInProgress(cluster.UUID) {
+		return ErrCheckInProgress
+	}
+	defer m.unmarkInProgress(cluster.UUID)
+
+	zap.S().Debugw("(Status Monitor) Checking cluster", "uuid", cluster.UUID)
+	client, err := m.newClient(ctx, cluster)
+	if err != nil {
+		return fmt.Errorf("could not create client for cluster: %w", err)
+	}
+
+	previous, err := m.store.GetCheckerResult(values.CheckerSearch{Cluster: &cluster.UUID})
+	if err != nil {
+		return fmt.Errorf("could not get previous results: %w", err)
+	}
+
+	version := 1
+	for _, result := range previous {
+		if result.Result.Version >= version {
+			version = result.Result.Version + 1
+		}
+	}
+
+	resources := &clusterResources{
+		ctx:        ctx,
+		cluster:    cluster,
+		client:     client,
+		thresholds: &m.thresholds,
+		newNodeClient: func(node values.NodeSummary) (couchbase.ClientIFace, error) {
+			return m.newNodeClient(ctx, cluster, node)
+		},
+		newMemcachedClient: func() (memcached.ConnIFace, error) {
+			return m.newMemcachedClient(ctx, cluster)
+		},
+		logScanner: m.logScanner,
+	}
+
+	results, failures := m.runCheckers(resources)
+	resources.close()
+
+	// the checkers that ran after the cancellation failed because of it rather than the cluster
+	if ctx.Err() != nil {
+		return fmt.Errorf("status check cancelled: %w", ctx.Err())
+	}
+
+	for _, result := range previous {
+		for _, failure := range failures {
+			if failure.matches(result) {
+				results = append(results, result)
+				break
+			}
+		}
+	}
+
+	for _, result := range results {
+		result.Cluster = cluster.UUID
+		result.Result.Version = version
+		if err = m.store.SetCheckerResult(result); err != nil {
+			return fmt.Errorf("could not store result for checker '%s': %w", result.Result.Name, err)
+		}
+	}
+
+	if err = m.store.DeleteOldCheckerResults(cluster.UUID, version); err != nil {
+		return fmt.Errorf("could not remove stale results: %w", err)
+	}
+
+	zap.S().Debugw("(Status Monitor) Cluster checked", "uuid", cluster.UUID, "version", version, "#results",
+		len(results), "#failures", len(failures))
+	return nil
+}
+
+func (m *Monitor) markInProgress(uuid string) bool {
+	m.inProgressLock.Lock()
+	defer m.inProgressLock.Unlock()
+
+	if _, ok := m.inProgress[uuid]; ok {
+		return false
+	}
+
+	m.inProgress[uuid] = struct{}{}
+	return true
+}
+
+func (m *Monitor) unmarkInProgress(uuid string) {
+	m.inProgressLock.Lock()
+	delete(m.inProgress, uuid)
+	m.inProgressLock.Unlock()
+}
+
+// failedChecker identifies a checker that could not run. Empty node and bucket mean it failed for all of them.
+type failedChecker struct {
+	name   string
+	node   string
+	bucket string
+}
+
+func (f failedChecker) matches(result *values.WrappedCheckerResult) bool {
+	return result.Result.Name == f.name && (f.node == "" || f.node == result.Node) &&
+		(f.bucket == "" || f.bucket == result.Bucket)
+}
+
+// runCheckers runs every registered checker, returning the results as well as the checkers that failed.
+func (m *Monitor) runCheckers(resources *clusterResources) ([]*values.WrappedCheckerResult, []failedChecker) {
+	results := make([]*values.WrappedCheckerResult, 0)
+	failures := make([]failedChecker, 0)
+	uuid := resources.cluster.UUID
+
+	for name, checker := range m.clusterCheckers {
+		out, err := checker(resources)
+		if err != nil {
+			zap.S().Warnw("(Status Monitor) Cluster checker failed", "cluster", uuid, "checker", name, "err", err)
+			failures = append(failures, failedChecker{name: name})
+			continue
+		}
+
+		results = append(results, out...)
+	}
+
+	for _, node := range resources.cluster.NodesSummary {
+		for name, checker := range m.nodeCheckers {
+			out, err := checker(node, resources)
+			if err != nil {
+				zap.S().Warnw("(Status Monitor) Node checker failed", "cluster", uuid, "node", node.NodeUUID,
+					"checker", name, "err", err)
+				failures = append(failures, failedChecker{name: name, node: node.NodeUUID})
+				continue
+			}
+
+			results = append(results, &values.WrappedCheckerResult{Result: out, Node: node.NodeUUID})
+		}
+	}
+
+	for _, bucket := range resources.cluster.BucketsSummary {
+		for name, checker := range m.bucketCheckers {
+			out, err := checker(bucket, resources)
+			if err != nil {
+				zap.S().Warnw("(Status Monitor) Bucket checker failed", "cluster", uuid, "bucket", bucket.Name,
+					"checker", name, "err", err)
+				failures = append(failures, failedChecker{name: name, bucket: bucket.Name})
+				continue
+			}
+
+			results = append(results, &values.WrappedCheckerResult{Result: out, Bucket: bucket.Name})
+		}
+	}
+
+	for _, result := range results {
+		addRemediation(result.Result)
+	}
+
+	return results, failures
+}
@@ -0,0 +1,259 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/configuration"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/couchbase"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/couchbase/mocks"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/storage"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/storage/sqlite"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func init() {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+	encoderConfig.ConsoleSeparator = " "
+
+	encoder := zapcore.NewConsoleEncoder(encoderConfig)
+	core := zapcore.NewCore(encoder, os.Stdout, zapcore.WarnLevel)
+
+	zap.ReplaceGlobals(zap.New(core))
+}
+
+var testCluster = &values.CouchbaseCluster{
+	UUID:       "uuid-0",
+	Enterprise: true,
+	Name:       "cluster-0",
+	User:       "user",
+	Password:   "password",
+	NodesSummary: values.NodesSummary{
+		{NodeUUID: "N0", Host: "http://localhost:9000", Status: "healthy"},
+		{NodeUUID: "N1", Host: "http://localhost:9001", Status: "unhealthy"},
+	},
+	BucketsSummary: values.BucketsSummary{{Name: "B0"}},
+}
+
+// mockClient returns a client whose method is expected to be called once, with any arguments, and returns ret.
+func mockClient(t *testing.T, name string, ret ...interface{}) *mocks.ClientIFace {
+	client := &mocks.ClientIFace{}
+
+	method, ok := reflect.TypeOf(client).MethodByName(name)
+	require.True(t, ok, "unknown client method %s", name)
+
+	// the first input is the receiver
+	args := make([]interface{}, method.Type.NumIn()-1)
+	for i := range args {
+		args[i] = mock.Anything
+	}
+
+	client.On(name, args...).Return(ret...).Once()
+	t.Cleanup(func() { client.AssertExpectations(t) })
+
+	return client
+}
+
+// mockClientResources returns the resources of the cluster with a client made by mockClient.
+func mockClientResources(t *testing.T, cluster *values.CouchbaseCluster, method string,
+	ret ...interface{}) *clusterResources {
+	return &clusterResources{cluster: cluster, client: mockClient(t, method, ret...)}
+}
+
+func createTestMonitor(t *testing.T) (*Monitor, storage.Store) {
+	store, err := sqlite.NewSQLiteDB(filepath.Join(t.TempDir(), "store.sqlite"), "key")
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+
+	require.NoError(t, store.AddCluster(testCluster))
+
+	monitor := NewMonitor(store, 1, configuration.DefaultCheckerThresholds, time.Hour,
+		couchbase.NewClientCache(time.Hour))
+	monitor.newClient = func(_ context.Context, _ *values.CouchbaseCluster) (couchbase.ClientIFace, error) {
+		return &mocks.ClientIFace{}, nil
+	}
+
+	// the monitor tests only need a couple of checkers, the rest are tested on their own
+	monitor.clusterCheckers = map[string]clusterCheckerFn{values.CheckDuplicateNodeUUID: duplicateNodeUUIDCheck}
+	monitor.nodeCheckers = map[string]nodeCheckerFn{values.CheckUnhealthyNode: unhealthyNodeCheck}
+	monitor.bucketCheckers = map[string]bucketCheckerFn{}
+
+	return monitor, store
+}
+
+func statusChecker(name string, status values.CheckerStatus) nodeCheckerFn {
+	return func(_ values.NodeSummary, _ *clusterResources) (*values.CheckerResult, error) {
+		return newResult(name, status, nil)
+	}
+}
+
+func getResults(t *testing.T, store storage.Store) []*values.WrappedCheckerResult {
+	results, err := store.GetCheckerResult(values.CheckerSearch{Cluster: &testCluster.UUID})
+	require.NoError(t, err)
+	return results
+}
+
+func TestCheckClusterStoresResults(t *testing.T) {
+	monitor, store := createTestMonitor(t)
+
+	require.NoError(t, monitor.CheckCluster(context.Background(), testCluster))
+
+	results := getResults(t, store)
+	// one duplicateNodeUUID and one unhealthyNode result per node
+	require.Len(t, results, 4)
+
+	for _, result := range results {
+		require.Equal(t, testCluster.UUID, result.Cluster)
+		require.Equal(t, 1, result.Result.Version)
+	}
+
+	unhealthy := results[3]
+	require.Equal(t, values.CheckUnhealthyNode, unhealthy.Result.Name)
+	require.Equal(t, "N1", unhealthy.Node)
+	require.Equal(t, values.AlertCheckerStatus, unhealthy.Result.Status)
+	require.Equal(t, values.AllCheckerDefs[values.CheckUnhealthyNode].Remediation, unhealthy.Result.Remediation)
+}
+
+func TestCheckClusterVersioning(t *testing.T) {
+	monitor, store := createTestMonitor(t)
+	monitor.clusterCheckers = map[string]clusterCheckerFn{}
+	monitor.nodeCheckers = map[string]nodeCheckerFn{
+		"checker-0": statusChecker("checker-0", values.GoodCheckerStatus),
+		"checker-1": statusChecker("checker-1", values.WarnCheckerStatus),
+	}
+
+	require.NoError(t, monitor.CheckCluster(context.Background(), testCluster))
+	require.Len(t, getResults(t, store), 4)
+
+	t.Run("stale-results-removed", func(t *testing.T) {
+		delete(monitor.nodeCheckers, "checker-1")
+		require.NoError(t, monitor.CheckCluster(context.Background(), testCluster))
+
+		results := getResults(t, store)
+		require.Len(t, results, 2)
+		for _, result := range results {
+			require.Equal(t, "checker-0", result.Result.Name)
+			require.Equal(t, 2, result.Result.Version)
+		}
+	})
+
+	t.Run("failed-results-carried-over", func(t *testing.T) {
+		monitor.nodeCheckers["checker-0"] = func(node values.NodeSummary,
+			_ *clusterResources) (*values.CheckerResult, error) {
+			if node.NodeUUID == "N1" {
+				return nil, fmt.Errorf("could not run")
+			}
+
+			return newResult("checker-0", values.AlertCheckerStatus, nil)
+		}
+
+		require.NoError(t, monitor.CheckCluster(context.Background(), testCluster))
+
+		results := getResults(t, store)
+		require.Len(t, results, 2)
+		require.Equal(t, "N0", results[0].Node)
+		require.Equal(t, values.AlertCheckerStatus, results[0].Result.Status)
+		require.Equal(t, "N1", results[1].Node)
+		require.Equal(t, values.GoodCheckerStatus, results[1].Result.Status)
+		require.Equal(t, 3, results[1].Result.Version)
+	})
+}
+
+func TestCheckClusterCE(t *testing.T) {
+	monitor, _ := createTestMonitor(t)
+
+	ce := *testCluster
+	ce.Enterprise = false
+	require.Error(t, monitor.CheckCluster(context.Background(), &ce))
+}
+
+func TestCheckClusterInProgress(t *testing.T) {
+	monitor, _ := createTestMonitor(t)
+
+	require.True(t, monitor.markInProgress(testCluster.UUID))
+	require.ErrorIs(t, monitor.CheckCluster(context.Background(), testCluster), ErrCheckInProgress)
+
+	monitor.unmarkInProgress(testCluster.UUID)
+	require.NoError(t, monitor.CheckCluster(context.Background(), testCluster))
+}
+
+func TestCheckClusterCancelled(t *testing.T) {
+	monitor, store := createTestMonitor(t)
+
+	require.NoError(t, monitor.CheckCluster(context.Background(), testCluster))
+	require.Len(t, getResults(t, store), 4)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	monitor.nodeCheckers = map[string]nodeCheckerFn{
+		values.CheckUnhealthyNode: func(_ values.NodeSummary, _ *clusterResources) (*values.CheckerResult, error) {
+			cancel()
+			return nil, context.Canceled
+		},
+	}
+
+	require.ErrorIs(t, monitor.CheckCluster(ctx, testCluster), context.Canceled)
+
+	// the results of the previous check are left as they were
+	results := getResults(t, store)
+	require.Len(t, results, 4)
+	for _, result := range results {
+		require.Equal(t, 1, result.Result.Version)
+	}
+}
+
+func TestMonitorStartStop(t *testing.T) {
+	monitor, store := createTestMonitor(t)
+
+	monitor.Start(200 * time.Millisecond)
+	time.Sleep(500 * time.Millisecond)
+	monitor.Stop()
+
+	results := getResults(t, store)
+	require.Len(t, results, 4)
+	// it runs once on start and then on every tick
+	require.GreaterOrEqual(t, results[0].Result.Version, 2)
+}
+
+func TestCheckClusterNodeClients(t *testing.T) {
+	monitor, store := createTestMonitor(t)
+	monitor.clusterCheckers = map[string]clusterCheckerFn{}
+	monitor.nodeCheckers = map[string]nodeCheckerFn{values.CheckNodeDiskSpace: nodeDiskSpaceCheck}
+
+	usage := map[string]uint64{"N0": 50, "N1": 99}
+	monitor.newNodeClient = func(_ context.Context, cluster *values.CouchbaseCluster,
+		node values.NodeSummary) (couchbase.ClientIFace, error) {
+		require.Equal(t, testCluster.UUID, cluster.UUID)
+
+		client := &mocks.ClientIFace{}
+		client.On("GetNodeStorage", mock.Anything).Return(&values.Storage{Available: values.AvailableStorage{
+			DiskStorage: []values.DiskStorage{{Path: "/", Usage: usage[node.NodeUUID]}},
+		}}, nil)
+		return client, nil
+	}
+
+	require.NoError(t, monitor.CheckCluster(context.Background(), testCluster))
+
+	results := getResults(t, store)
+	require.Len(t, results, 2)
+	require.Equal(t, "N0", results[0].Node)
+	require.Equal(t, values.GoodCheckerStatus, results[0].Result.Status)
+	require.Equal(t, "N1", results[1].Node)
+	require.Equal(t, values.AlertCheckerStatus, results[1].Result.Status)
+}
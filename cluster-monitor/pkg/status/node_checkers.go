@@ -0,0 +1,368 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package status
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+)
+
+// unhealthyNodeCheck alerts if the cluster manager reports the node as unhealthy. Nodes warming up are only a warning
+// as they should become healthy on their own.
+func unhealthyNodeCheck(node values.NodeSummary, _ *clusterResources) (*values.CheckerResult, error) {
+	status := values.GoodCheckerStatus
+	switch node.Status {
+	case "healthy":
+	case "warmup":
+		status = values.WarnCheckerStatus
+	default:
+		status = values.AlertCheckerStatus
+	}
+
+	return newResult(values.CheckUnhealthyNode, status, map[string]string{"status": node.Status})
+}
+
+// nodeSwapUsageCheck warns or alerts when the node's swap usage is above the configured thresholds. Nodes without swap
+// are good.
+func nodeSwapUsageCheck(node values.NodeSummary, resources *clusterResources) (*values.CheckerResult, error) {
+	var usage float64
+	if node.SwapTotal > 0 {
+		usage = percentage(node.SwapUsed, node.SwapTotal)
+	}
+
+	return newResult(values.CheckNodeSwapUsage,
+		usageStatus(usage, resources.thresholds.SwapUsageWarn, resources.thresholds.SwapUsageAlert),
+		map[string]interface{}{"swap_used": node.SwapUsed, "swap_total": node.SwapTotal, "usage_percent": usage})
+}
+
+type diskUsage struct {
+	Path         string `json:"path"`
+	SizeKBytes   uint64 `json:"size_kbytes"`
+	UsagePercent uint64 `json:"usage_percent"`
+}
+
+// servicePathUsage is the usage of the file system a service stores its data in.
+type servicePathUsage struct {
+	Service      string `json:"service"`
+	Path         string `json:"path"`
+	Mount        string `json:"mount"`
+	SizeKBytes   uint64 `json:"size_kbytes"`
+	UsagePercent uint64 `json:"usage_percent"`
+}
+
+// nodeDiskSpaceCheck warns or alerts when the file system any of the node's services stores its data in is fuller than
+// the configured thresholds. The value has the usage for every service path so the full ones can be found. Nodes
+// without any service paths, such as Query Service only nodes, are checked on all their disks instead.
+func nodeDiskSpaceCheck(node values.NodeSummary, resources *clusterResources) (*values.CheckerResult, error) {
+	storage, err := resources.getNodeStorage(node)
+	if err != nil {
+		return nil, err
+	}
+
+	status := values.GoodCheckerStatus
+	paths := nodeServicePaths(node, storage)
+	if len(paths) > 0 {
+		for _, path := range paths {
+			status = worstStatus(status, usageStatus(float64(path.UsagePercent), resources.thresholds.DiskUsageWarn,
+				resources.thresholds.DiskUsageAlert))
+		}
+
+		return newResult(values.CheckNodeDiskSpace, status, map[string]interface{}{"paths": paths})
+	}
+
+	disks := make([]diskUsage, 0, len(storage.Available.DiskStorage))
+	for _, disk := range storage.Available.DiskStorage {
+		disks = append(disks, diskUsage{Path: disk.Path, SizeKBytes: disk.SizeKBytes, UsagePercent: disk.Usage})
+		status = worstStatus(status, usageStatus(float64(disk.Usage), resources.thresholds.DiskUsageWarn,
+			resources.thresholds.DiskUsageAlert))
+	}
+
+	return newResult(values.CheckNodeDiskSpace, status, map[string]interface{}{"disks": disks})
+}
+
+// sharedFilesystemsCheck warns if the data paths of more than one of the node's services are on the same file system.
+func sharedFilesystemsCheck(node values.NodeSummary, resources *clusterResources) (*values.CheckerResult, error) {
+	storage, err := resources.getNodeStorage(node)
+	if err != nil {
+		return nil, err
+	}
+
+	type sharedFilesystem struct {
+		Mount    string   `json:"mount"`
+		Services []string `json:"services"`
+	}
+
+	servicesByMount := make(map[string][]string)
+	mounts := make([]string, 0)
+	for _, path := range nodeServicePaths(node, storage) {
+		services, ok := servicesByMount[path.Mount]
+		if !ok {
+			mounts = append(mounts, path.Mount)
+		}
+
+		if !containsString(services, path.Service) {
+			servicesByMount[path.Mount] = append(services, path.Service)
+		}
+	}
+
+	shared := make([]sharedFilesystem, 0)
+	for _, mount := range mounts {
+		if services := servicesByMount[mount]; len(services) > 1 {
+			shared = append(shared, sharedFilesystem{Mount: mount, Services: services})
+		}
+	}
+
+	status := values.GoodCheckerStatus
+	var value interface{}
+	if len(shared) > 0 {
+		status = values.WarnCheckerStatus
+		value = map[string][]sharedFilesystem{"shared_file_systems": shared}
+	}
+
+	return newResult(values.CheckSharedFilesystems, status, value)
+}
+
+// nodeServicePaths returns the data paths of the services the node runs, sorted by service, together with the file
+// system each of them is on. Paths that are not on any of the node's file systems are left out.
+func nodeServicePaths(node values.NodeSummary, storage *values.Storage) []servicePathUsage {
+	configs := append(append([]values.StorageConfig(nil), storage.NodeStorage.HDD...), storage.NodeStorage.SSD...)
+
+	paths := make([]servicePathUsage, 0)
+	for _, service := range []string{"kv", "index", "fts", "eventing", "cbas"} {
+		if !node.HasService(service) {
+			continue
+		}
+
+		for _, config := range configs {
+			for _, path := range config.GetAllPaths()[service] {
+				disk := pathMount(path, storage.Available.DiskStorage)
+				if disk == nil {
+					continue
+				}
+
+				paths = append(paths, servicePathUsage{
+					Service:      service,
+					Path:         path,
+					Mount:        disk.Path,
+					SizeKBytes:   disk.SizeKBytes,
+					UsagePercent: disk.Usage,
+				})
+			}
+		}
+	}
+
+	return paths
+}
+
+// pathMount returns the file system the path is on, which is the one mounted at the longest prefix of the path.
+func pathMount(path string, disks []values.DiskStorage) *values.DiskStorage {
+	var mount *values.DiskStorage
+	for i, disk := range disks {
+		if !isPathPrefix(disk.Path, path) {
+			continue
+		}
+
+		if mount == nil || len(disk.Path) > len(mount.Path) {
+			mount = &disks[i]
+		}
+	}
+
+	return mount
+}
+
+// isPathPrefix returns whether the path is the prefix directory itself or inside it.
+func isPathPrefix(prefix, path string) bool {
+	if prefix == "" || !strings.HasPrefix(path, prefix) {
+		return false
+	}
+
+	return len(path) == len(prefix) || strings.HasSuffix(prefix, "/") || path[len(prefix)] == '/'
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// minimumNodeMemoryCheck warns if the node has less memory than the configured minimum.
+func minimumNodeMemoryCheck(node values.NodeSummary, resources *clusterResources) (*values.CheckerResult, error) {
+	if node.MemTotal == 0 {
+		return nil, fmt.Errorf("node did not report its memory")
+	}
+
+	status := values.GoodCheckerStatus
+	if node.MemTotal < resources.thresholds.MinimumNodeMemory {
+		status = values.WarnCheckerStatus
+	}
+
+	return newResult(values.CheckMinimumNodeMemory, status,
+		map[string]uint64{"mem_total": node.MemTotal, "minimum": resources.thresholds.MinimumNodeMemory})
+}
+
+// freeMemoryCheck warns or alerts when the memory used on the node is above the configured thresholds.
+func freeMemoryCheck(node values.NodeSummary, resources *clusterResources) (*values.CheckerResult, error) {
+	if node.MemTotal == 0 {
+		return nil, fmt.Errorf("node did not report its memory")
+	}
+
+	var used uint64
+	if node.MemFree < node.MemTotal {
+		used = node.MemTotal - node.MemFree
+	}
+
+	usage := percentage(used, node.MemTotal)
+	return newResult(values.CheckFreeMemory,
+		usageStatus(usage, resources.thresholds.MemoryUsageWarn, resources.thresholds.MemoryUsageAlert),
+		map[string]interface{}{"mem_free": node.MemFree, "mem_total": node.MemTotal, "usage_percent": usage})
+}
+
+// supportedVersionCheck warns when the node's version is close to or past its end of maintenance and alerts once it is
+// past its end of support. Versions that are not in the GA versions list cannot be checked.
+func supportedVersionCheck(node values.NodeSummary, resources *clusterResources) (*values.CheckerResult, error) {
+	build, err := nodeBuild(node)
+	if err != nil {
+		return nil, err
+	}
+
+	version, ok := values.GAVersions[build]
+	if !ok {
+		return newResult(values.CheckSupportedVersion, values.InfoCheckerStatus,
+			map[string]string{"version": node.Version, "reason": "version is not in the GA versions list"})
+	}
+
+	value := map[string]string{"version": node.Version}
+	if !version.EOM.IsZero() {
+		value["eom"] = version.EOM.Format("2006-01")
+	}
+
+	if !version.EOS.IsZero() {
+		value["eos"] = version.EOS.Format("2006-01")
+	}
+
+	return newResult(values.CheckSupportedVersion,
+		versionSupportStatus(version, time.Now().UTC(), resources.thresholds.EOMWarnMonths), value)
+}
+
+// versionSupportStatus alerts after the end of support of the version and warns from warnMonths before its end of
+// maintenance.
+func versionSupportStatus(version values.Version, now time.Time, warnMonths int) values.CheckerStatus {
+	switch {
+	case !version.EOS.IsZero() && !now.Before(version.EOS):
+		return values.AlertCheckerStatus
+	case !version.EOM.IsZero() && !now.Before(version.EOM.AddDate(0, -warnMonths, 0)):
+		return values.WarnCheckerStatus
+	default:
+		return values.GoodCheckerStatus
+	}
+}
+
+// gaBuildCheck warns if the node is running a build that is not in the GA versions list.
+func gaBuildCheck(node values.NodeSummary, _ *clusterResources) (*values.CheckerResult, error) {
+	build, err := nodeBuild(node)
+	if err != nil {
+		return nil, err
+	}
+
+	status := values.GoodCheckerStatus
+	if _, ok := values.GAVersions[build]; !ok {
+		status = values.WarnCheckerStatus
+	}
+
+	return newResult(values.CheckGABuild, status, map[string]string{"version": node.Version})
+}
+
+// supportedOSCheck alerts if the node's operating system is not supported by the version it runs and warns if it is
+// deprecated. Operating systems that are not supported by any version are reported as info as the node may have given
+// a name that is not in the GA versions list.
+func supportedOSCheck(node values.NodeSummary, _ *clusterResources) (*values.CheckerResult, error) {
+	build, err := nodeBuild(node)
+	if err != nil {
+		return nil, err
+	}
+
+	value := map[string]string{"version": node.Version, "os": node.OS}
+	info := func(reason string) (*values.CheckerResult, error) {
+		value["reason"] = reason
+		return newResult(values.CheckSupportedOS, values.InfoCheckerStatus, value)
+	}
+
+	version, ok := values.GAVersions[build]
+	if !ok {
+		return info("version is not in the GA versions list")
+	}
+
+	if node.OS == "" {
+		return info("node did not report its operating system")
+	}
+
+	for _, os := range version.OS {
+		if !strings.HasPrefix(node.OS, os.Prefix) {
+			continue
+		}
+
+		if os.Deprecated {
+			return newResult(values.CheckSupportedOS, values.WarnCheckerStatus, value)
+		}
+
+		return newResult(values.CheckSupportedOS, values.GoodCheckerStatus, value)
+	}
+
+	for _, other := range values.GAVersions {
+		for _, os := range other.OS {
+			if strings.HasPrefix(node.OS, os.Prefix) {
+				return newResult(values.CheckSupportedOS, values.AlertCheckerStatus, value)
+			}
+		}
+	}
+
+	return info("operating system is not in the GA versions list")
+}
+
+// nodeBuild returns the version and build number the node is running without the edition, in the same format as the
+// GA versions list. For example 7.0.3-7031-enterprise becomes 7.0.3-7031.
+func nodeBuild(node values.NodeSummary) (string, error) {
+	if node.Version == "" {
+		return "", fmt.Errorf("node did not report its version")
+	}
+
+	parts := strings.SplitN(node.Version, "-", 3)
+	if len(parts) < 2 {
+		return node.Version, nil
+	}
+
+	return parts[0] + "-" + parts[1], nil
+}
+
+// oneServicePerNodeCheck warns if the node runs more than one service.
+func oneServicePerNodeCheck(node values.NodeSummary, _ *clusterResources) (*values.CheckerResult, error) {
+	status := values.GoodCheckerStatus
+	if len(node.Services) > 1 {
+		status = values.WarnCheckerStatus
+	}
+
+	return newResult(values.CheckOneServicePerNode, status, map[string][]string{"services": node.Services})
+}
+
+// cpuBucketCountCheck warns if the node has fewer CPUs than there are buckets. Only the Data Service nodes hold the
+// buckets so the rest are always good.
+func cpuBucketCountCheck(node values.NodeSummary, resources *clusterResources) (*values.CheckerResult, error) {
+	buckets := len(resources.cluster.BucketsSummary)
+
+	status := values.GoodCheckerStatus
+	if node.HasService("kv") && node.CPUCount < buckets {
+		status = values.WarnCheckerStatus
+	}
+
+	return newResult(values.CheckCPUBucketCount, status, map[string]int{"cpu_count": node.CPUCount, "buckets": buckets})
+}
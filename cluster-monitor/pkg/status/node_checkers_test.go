@@ -0,0 +1,462 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/configuration"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/couchbase"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/couchbase/mocks"
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnhealthyNodeCheck(t *testing.T) {
+	cases := []struct {
+		name           string
+		status         string
+		expectedStatus values.CheckerStatus
+	}{
+		{name: "healthy", status: "healthy", expectedStatus: values.GoodCheckerStatus},
+		{name: "warmup", status: "warmup", expectedStatus: values.WarnCheckerStatus},
+		{name: "unhealthy", status: "unhealthy", expectedStatus: values.AlertCheckerStatus},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := unhealthyNodeCheck(values.NodeSummary{NodeUUID: "N0", Status: tc.status}, nil)
+			require.NoError(t, err)
+			require.Equal(t, values.CheckUnhealthyNode, result.Name)
+			require.Equal(t, tc.expectedStatus, result.Status)
+		})
+	}
+}
+
+func testResources(t *testing.T, storage *values.Storage) *clusterResources {
+	return &clusterResources{
+		cluster:    testCluster,
+		thresholds: &configuration.DefaultCheckerThresholds,
+		newNodeClient: func(node values.NodeSummary) (couchbase.ClientIFace, error) {
+			return mockClient(t, "GetNodeStorage", storage, nil), nil
+		},
+	}
+}
+
+func TestNodeSwapUsageCheck(t *testing.T) {
+	cases := []struct {
+		name           string
+		used           uint64
+		total          uint64
+		expectedStatus values.CheckerStatus
+		expectedUsage  float64
+	}{
+		{name: "no-swap", expectedStatus: values.GoodCheckerStatus},
+		{name: "unused", total: 1000, expectedStatus: values.GoodCheckerStatus},
+		{name: "warn", used: 50, total: 1000, expectedStatus: values.WarnCheckerStatus, expectedUsage: 5},
+		{name: "alert", used: 950, total: 1000, expectedStatus: values.AlertCheckerStatus, expectedUsage: 95},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := nodeSwapUsageCheck(values.NodeSummary{SwapUsed: tc.used, SwapTotal: tc.total},
+				testResources(t, nil))
+			require.NoError(t, err)
+			require.Equal(t, values.CheckNodeSwapUsage, result.Name)
+			require.Equal(t, tc.expectedStatus, result.Status)
+
+			var value struct {
+				Usage float64 `json:"usage_percent"`
+			}
+			require.NoError(t, json.Unmarshal(result.Value, &value))
+			require.Equal(t, tc.expectedUsage, value.Usage)
+		})
+	}
+}
+
+func TestNodeDiskSpaceCheck(t *testing.T) {
+	cases := []struct {
+		name           string
+		disks          []values.DiskStorage
+		expectedStatus values.CheckerStatus
+	}{
+		{name: "no-disks", expectedStatus: values.GoodCheckerStatus},
+		{
+			name:           "good",
+			disks:          []values.DiskStorage{{Path: "/", Usage: 50}, {Path: "/data", Usage: 90}},
+			expectedStatus: values.GoodCheckerStatus,
+		},
+		{
+			name:           "warn",
+			disks:          []values.DiskStorage{{Path: "/", Usage: 50}, {Path: "/data", Usage: 91}},
+			expectedStatus: values.WarnCheckerStatus,
+		},
+		{
+			name:           "alert",
+			disks:          []values.DiskStorage{{Path: "/", Usage: 96}, {Path: "/data", Usage: 91}},
+			expectedStatus: values.AlertCheckerStatus,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resources := testResources(t, &values.Storage{Available: values.AvailableStorage{DiskStorage: tc.disks}})
+
+			result, err := nodeDiskSpaceCheck(values.NodeSummary{NodeUUID: "N0"}, resources)
+			require.NoError(t, err)
+			require.Equal(t, values.CheckNodeDiskSpace, result.Name)
+			require.Equal(t, tc.expectedStatus, result.Status)
+
+			var value struct {
+				Disks []diskUsage `json:"disks"`
+			}
+			require.NoError(t, json.Unmarshal(result.Value, &value))
+			require.Len(t, value.Disks, len(tc.disks))
+
+			// the storage is only requested once per node
+			_, err = nodeDiskSpaceCheck(values.NodeSummary{NodeUUID: "N0"}, resources)
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		resources := testResources(t, nil)
+		resources.newNodeClient = func(_ values.NodeSummary) (couchbase.ClientIFace, error) {
+			client := &mocks.ClientIFace{}
+			client.On("GetNodeStorage", mock.Anything).Return(nil, fmt.Errorf("connection refused"))
+			return client, nil
+		}
+
+		_, err := nodeDiskSpaceCheck(values.NodeSummary{NodeUUID: "N0"}, resources)
+		require.Error(t, err)
+	})
+}
+
+// serviceStorage returns the storage of a node with the data and index paths on the given file systems.
+func serviceStorage(dataPath, indexPath string, disks ...values.DiskStorage) *values.Storage {
+	return &values.Storage{
+		Available: values.AvailableStorage{DiskStorage: disks},
+		NodeStorage: values.NodeStorageSet{
+			HDD: []values.StorageConfig{{Path: dataPath, IndexPath: indexPath}},
+		},
+	}
+}
+
+func TestNodeDiskSpaceCheckServicePaths(t *testing.T) {
+	storage := serviceStorage("/data/kv", "/index",
+		values.DiskStorage{Path: "/", SizeKBytes: 100, Usage: 99},
+		values.DiskStorage{Path: "/data", SizeKBytes: 200, Usage: 50},
+		values.DiskStorage{Path: "/index", SizeKBytes: 300, Usage: 92},
+	)
+
+	// the full root file system is not used by any of the services
+	result, err := nodeDiskSpaceCheck(values.NodeSummary{NodeUUID: "N0", Services: []string{"kv", "index"}},
+		testResources(t, storage))
+	require.NoError(t, err)
+	require.Equal(t, values.WarnCheckerStatus, result.Status)
+	require.JSONEq(t, `{"paths":[`+
+		`{"service":"kv","path":"/data/kv","mount":"/data","size_kbytes":200,"usage_percent":50},`+
+		`{"service":"index","path":"/index","mount":"/index","size_kbytes":300,"usage_percent":92}]}`,
+		string(result.Value))
+}
+
+func TestSharedFilesystemsCheck(t *testing.T) {
+	disks := []values.DiskStorage{{Path: "/"}, {Path: "/data"}, {Path: "/index"}}
+
+	cases := []struct {
+		name           string
+		services       []string
+		dataPath       string
+		indexPath      string
+		expectedStatus values.CheckerStatus
+		expectedValue  string
+	}{
+		{
+			name:           "separate",
+			services:       []string{"kv", "index"},
+			dataPath:       "/data/kv",
+			indexPath:      "/index/gsi",
+			expectedStatus: values.GoodCheckerStatus,
+		},
+		{
+			name:           "shared",
+			services:       []string{"kv", "index", "fts"},
+			dataPath:       "/data/kv",
+			indexPath:      "/data/index",
+			expectedStatus: values.WarnCheckerStatus,
+			expectedValue:  `{"shared_file_systems":[{"mount":"/data","services":["kv","index","fts"]}]}`,
+		},
+		{
+			// /database is not on the /data file system
+			name:           "similar-prefix",
+			services:       []string{"kv", "index"},
+			dataPath:       "/data/kv",
+			indexPath:      "/database",
+			expectedStatus: values.GoodCheckerStatus,
+		},
+		{
+			name:           "service-not-running",
+			services:       []string{"kv"},
+			dataPath:       "/data/kv",
+			indexPath:      "/data/index",
+			expectedStatus: values.GoodCheckerStatus,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resources := testResources(t, serviceStorage(tc.dataPath, tc.indexPath, disks...))
+
+			result, err := sharedFilesystemsCheck(values.NodeSummary{NodeUUID: "N0", Services: tc.services}, resources)
+			require.NoError(t, err)
+			require.Equal(t, values.CheckSharedFilesystems, result.Name)
+			require.Equal(t, tc.expectedStatus, result.Status)
+
+			if tc.expectedValue != "" {
+				require.JSONEq(t, tc.expectedValue, string(result.Value))
+			}
+		})
+	}
+}
+
+func TestMinimumNodeMemoryCheck(t *testing.T) {
+	const gib = 1024 * 1024 * 1024
+
+	cases := []struct {
+		name           string
+		memTotal       uint64
+		expectedStatus values.CheckerStatus
+	}{
+		{name: "enough", memTotal: 16 * gib, expectedStatus: values.GoodCheckerStatus},
+		{name: "minimum", memTotal: 4 * gib, expectedStatus: values.GoodCheckerStatus},
+		{name: "below", memTotal: 2 * gib, expectedStatus: values.WarnCheckerStatus},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := minimumNodeMemoryCheck(values.NodeSummary{MemTotal: tc.memTotal}, testResources(t, nil))
+			require.NoError(t, err)
+			require.Equal(t, values.CheckMinimumNodeMemory, result.Name)
+			require.Equal(t, tc.expectedStatus, result.Status)
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := minimumNodeMemoryCheck(values.NodeSummary{}, testResources(t, nil))
+		require.Error(t, err)
+	})
+}
+
+func TestFreeMemoryCheck(t *testing.T) {
+	cases := []struct {
+		name           string
+		memFree        uint64
+		expectedStatus values.CheckerStatus
+	}{
+		{name: "good", memFree: 500, expectedStatus: values.GoodCheckerStatus},
+		{name: "warn", memFree: 80, expectedStatus: values.WarnCheckerStatus},
+		{name: "alert", memFree: 10, expectedStatus: values.AlertCheckerStatus},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := freeMemoryCheck(values.NodeSummary{MemFree: tc.memFree, MemTotal: 1000},
+				testResources(t, nil))
+			require.NoError(t, err)
+			require.Equal(t, values.CheckFreeMemory, result.Name)
+			require.Equal(t, tc.expectedStatus, result.Status)
+		})
+	}
+
+	t.Run("custom-thresholds", func(t *testing.T) {
+		resources := testResources(t, nil)
+		resources.thresholds = &configuration.CheckerThresholds{MemoryUsageWarn: 40, MemoryUsageAlert: 60}
+
+		result, err := freeMemoryCheck(values.NodeSummary{MemFree: 500, MemTotal: 1000}, resources)
+		require.NoError(t, err)
+		require.Equal(t, values.WarnCheckerStatus, result.Status)
+	})
+}
+
+func TestVersionSupportStatus(t *testing.T) {
+	version := values.Version{
+		Build: "6.6.3-9808",
+		EOM:   time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		EOS:   time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	cases := []struct {
+		name           string
+		version        values.Version
+		now            time.Time
+		expectedStatus values.CheckerStatus
+	}{
+		{
+			name:           "supported",
+			version:        version,
+			now:            time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC),
+			expectedStatus: values.GoodCheckerStatus,
+		},
+		{
+			name:           "close-to-eom",
+			version:        version,
+			now:            time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC),
+			expectedStatus: values.WarnCheckerStatus,
+		},
+		{
+			name:           "after-eom",
+			version:        version,
+			now:            time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC),
+			expectedStatus: values.WarnCheckerStatus,
+		},
+		{
+			name:           "after-eos",
+			version:        version,
+			now:            time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+			expectedStatus: values.AlertCheckerStatus,
+		},
+		{
+			name:           "eom-tbd",
+			version:        values.Version{EOS: version.EOS},
+			now:            time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC),
+			expectedStatus: values.GoodCheckerStatus,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedStatus, versionSupportStatus(tc.version, tc.now, 6))
+		})
+	}
+}
+
+func TestSupportedVersionCheck(t *testing.T) {
+	result, err := supportedVersionCheck(values.NodeSummary{Version: "6.6.3-9808-enterprise"}, testResources(t, nil))
+	require.NoError(t, err)
+	require.Equal(t, values.CheckSupportedVersion, result.Name)
+	require.NotEqual(t, values.InfoCheckerStatus, result.Status)
+	require.JSONEq(t, `{"version":"6.6.3-9808-enterprise","eom":"2023-01","eos":"2023-10"}`, string(result.Value))
+
+	result, err = supportedVersionCheck(values.NodeSummary{Version: "7.1.0-1000-enterprise"}, testResources(t, nil))
+	require.NoError(t, err)
+	require.Equal(t, values.InfoCheckerStatus, result.Status)
+
+	_, err = supportedVersionCheck(values.NodeSummary{}, testResources(t, nil))
+	require.Error(t, err)
+}
+
+func TestGABuildCheck(t *testing.T) {
+	cases := []struct {
+		name           string
+		version        string
+		expectedStatus values.CheckerStatus
+	}{
+		{name: "ga", version: "7.0.3-7031-enterprise", expectedStatus: values.GoodCheckerStatus},
+		{name: "ga-no-edition", version: "7.0.3-7031", expectedStatus: values.GoodCheckerStatus},
+		{name: "non-ga", version: "7.0.3-6999-enterprise", expectedStatus: values.WarnCheckerStatus},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := gaBuildCheck(values.NodeSummary{Version: tc.version}, nil)
+			require.NoError(t, err)
+			require.Equal(t, values.CheckGABuild, result.Name)
+			require.Equal(t, tc.expectedStatus, result.Status)
+		})
+	}
+}
+
+func TestSupportedOSCheck(t *testing.T) {
+	cases := []struct {
+		name           string
+		version        string
+		os             string
+		expectedStatus values.CheckerStatus
+	}{
+		{name: "supported", version: "7.0.3-7031-enterprise", os: "Ubuntu 20.04.3 LTS",
+			expectedStatus: values.GoodCheckerStatus},
+		{name: "deprecated", version: "7.0.3-7031-enterprise", os: "CentOS Linux release 8.5.2111",
+			expectedStatus: values.WarnCheckerStatus},
+		{name: "unsupported", version: "7.0.3-7031-enterprise", os: "Ubuntu 12.04 LTS",
+			expectedStatus: values.AlertCheckerStatus},
+		{name: "unknown-os", version: "7.0.3-7031-enterprise", os: "x86_64-unknown-linux-gnu",
+			expectedStatus: values.InfoCheckerStatus},
+		{name: "no-os", version: "7.0.3-7031-enterprise", expectedStatus: values.InfoCheckerStatus},
+		{name: "unknown-version", version: "7.0.3-6999-enterprise", os: "Ubuntu 20.04.3 LTS",
+			expectedStatus: values.InfoCheckerStatus},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := supportedOSCheck(values.NodeSummary{Version: tc.version, OS: tc.os}, nil)
+			require.NoError(t, err)
+			require.Equal(t, values.CheckSupportedOS, result.Name)
+			require.Equal(t, tc.expectedStatus, result.Status)
+		})
+	}
+}
+
+func TestOneServicePerNodeCheck(t *testing.T) {
+	cases := []struct {
+		name           string
+		services       []string
+		expectedStatus values.CheckerStatus
+	}{
+		{name: "one-service", services: []string{"kv"}, expectedStatus: values.GoodCheckerStatus},
+		{name: "multiple-services", services: []string{"kv", "index", "n1ql"}, expectedStatus: values.WarnCheckerStatus},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := oneServicePerNodeCheck(values.NodeSummary{Services: tc.services}, nil)
+			require.NoError(t, err)
+			require.Equal(t, values.CheckOneServicePerNode, result.Name)
+			require.Equal(t, tc.expectedStatus, result.Status)
+		})
+	}
+}
+
+func TestCPUBucketCountCheck(t *testing.T) {
+	resources := &clusterResources{
+		cluster: &values.CouchbaseCluster{BucketsSummary: values.BucketsSummary{{Name: "B0"}, {Name: "B1"}}},
+	}
+
+	cases := []struct {
+		name           string
+		node           values.NodeSummary
+		expectedStatus values.CheckerStatus
+	}{
+		{
+			name:           "enough-cpus",
+			node:           values.NodeSummary{Services: []string{"kv"}, CPUCount: 2},
+			expectedStatus: values.GoodCheckerStatus,
+		},
+		{
+			name:           "too-few-cpus",
+			node:           values.NodeSummary{Services: []string{"kv"}, CPUCount: 1},
+			expectedStatus: values.WarnCheckerStatus,
+		},
+		{
+			name:           "not-data-node",
+			node:           values.NodeSummary{Services: []string{"n1ql"}, CPUCount: 1},
+			expectedStatus: values.GoodCheckerStatus,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := cpuBucketCountCheck(tc.node, resources)
+			require.NoError(t, err)
+			require.Equal(t, values.CheckCPUBucketCount, result.Name)
+			require.Equal(t, tc.expectedStatus, result.Status)
+		})
+	}
+}
@@ -6,6 +6,8 @@
 package storage
 
 import (
+	"time"
+
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
 )
 
@@ -18,6 +20,9 @@ type Store interface {
 	// cluster manager user functions
 	AddUser(user *values.User) error
 	GetUser(user string) (*values.User, error)
+	GetUsers() ([]*values.User, error)
+	UpdateUser(user *values.User) error
+	DeleteUser(user string) error
 
 	// couchbase cluster management functions
 	GetClusters(sensitive bool, enterpriseOnly bool) ([]*values.CouchbaseCluster, error)
@@ -26,6 +31,18 @@ type Store interface {
 	DeleteCluster(uuid string) error
 	UpdateCluster(cluster *values.CouchbaseCluster) error
 
+	// heartbeat history functions
+	AddHeartbeat(record *values.HeartbeatRecord) error
+	GetHeartbeats(clusterUUID string, since time.Time) ([]*values.HeartbeatRecord, error)
+	DeleteHeartbeatsBefore(before time.Time) error
+
+	// monitor frequencies functions
+	GetFrequencies() (*values.Frequencies, error)
+	SetFrequencies(frequencies *values.Frequencies) error
+	GetClusterFrequencies() ([]*values.ClusterFrequencies, error)
+	SetClusterFrequencies(frequencies *values.ClusterFrequencies) error
+	DeleteClusterFrequencies(clusterUUID string) error
+
 	// manage cluster alias functions
 	AddAlias(alias *values.ClusterAlias) error
 	DeleteAlias(alias string) error
@@ -33,4 +50,14 @@ type Store interface {
 
 	AddCloudCredentials(creds *values.Credential) error
 	GetCloudCredentials(sensitive bool) ([]*values.Credential, error)
+
+	// checker results functions
+	SetCheckerResult(result *values.WrappedCheckerResult) error
+	GetCheckerResult(search values.CheckerSearch) ([]*values.WrappedCheckerResult, error)
+	DeleteOldCheckerResults(clusterUUID string, version int) error
+
+	// checker dismissal functions
+	AddDismissal(dismissal values.Dismissal) error
+	GetDismissals(search values.DismissalSearchSpace) ([]*values.Dismissal, error)
+	DeleteDismissal(id string) error
 }
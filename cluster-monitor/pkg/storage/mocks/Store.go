@@ -5,6 +5,8 @@ package mocks
 import (
 	mock "github.com/stretchr/testify/mock"
 
+	time "time"
+
 	values "github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
 )
 
@@ -69,6 +71,20 @@ func (_m *Store) AddDismissal(dismissal values.Dismissal) error {
 	return r0
 }
 
+// AddHeartbeat provides a mock function with given fields: record
+func (_m *Store) AddHeartbeat(record *values.HeartbeatRecord) error {
+	ret := _m.Called(record)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*values.HeartbeatRecord) error); ok {
+		r0 = rf(record)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddUser provides a mock function with given fields: user
 func (_m *Store) AddUser(user *values.User) error {
 	ret := _m.Called(user)
@@ -125,6 +141,76 @@ func (_m *Store) DeleteCluster(uuid string) error {
 	return r0
 }
 
+// DeleteClusterFrequencies provides a mock function with given fields: clusterUUID
+func (_m *Store) DeleteClusterFrequencies(clusterUUID string) error {
+	ret := _m.Called(clusterUUID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(clusterUUID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteDismissal provides a mock function with given fields: id
+func (_m *Store) DeleteDismissal(id string) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteHeartbeatsBefore provides a mock function with given fields: before
+func (_m *Store) DeleteHeartbeatsBefore(before time.Time) error {
+	ret := _m.Called(before)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(time.Time) error); ok {
+		r0 = rf(before)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteOldCheckerResults provides a mock function with given fields: clusterUUID, version
+func (_m *Store) DeleteOldCheckerResults(clusterUUID string, version int) error {
+	ret := _m.Called(clusterUUID, version)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int) error); ok {
+		r0 = rf(clusterUUID, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteUser provides a mock function with given fields: user
+func (_m *Store) DeleteUser(user string) error {
+	ret := _m.Called(user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetAlias provides a mock function with given fields: alias
 func (_m *Store) GetAlias(alias string) (*values.ClusterAlias, error) {
 	ret := _m.Called(alias)
@@ -148,6 +234,29 @@ func (_m *Store) GetAlias(alias string) (*values.ClusterAlias, error) {
 	return r0, r1
 }
 
+// GetCheckerResult provides a mock function with given fields: search
+func (_m *Store) GetCheckerResult(search values.CheckerSearch) ([]*values.WrappedCheckerResult, error) {
+	ret := _m.Called(search)
+
+	var r0 []*values.WrappedCheckerResult
+	if rf, ok := ret.Get(0).(func(values.CheckerSearch) []*values.WrappedCheckerResult); ok {
+		r0 = rf(search)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*values.WrappedCheckerResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(values.CheckerSearch) error); ok {
+		r1 = rf(search)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetCloudCredentials provides a mock function with given fields: sensitive
 func (_m *Store) GetCloudCredentials(sensitive bool) ([]*values.Credential, error) {
 	ret := _m.Called(sensitive)
@@ -194,6 +303,29 @@ func (_m *Store) GetCluster(uuid string, sensitive bool) (*values.CouchbaseClust
 	return r0, r1
 }
 
+// GetClusterFrequencies provides a mock function with given fields:
+func (_m *Store) GetClusterFrequencies() ([]*values.ClusterFrequencies, error) {
+	ret := _m.Called()
+
+	var r0 []*values.ClusterFrequencies
+	if rf, ok := ret.Get(0).(func() []*values.ClusterFrequencies); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*values.ClusterFrequencies)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetClusters provides a mock function with given fields: sensitive, enterpriseOnly
 func (_m *Store) GetClusters(sensitive bool, enterpriseOnly bool) ([]*values.CouchbaseCluster, error) {
 	ret := _m.Called(sensitive, enterpriseOnly)
@@ -240,6 +372,52 @@ func (_m *Store) GetDismissals(search values.DismissalSearchSpace) ([]*values.Di
 	return r0, r1
 }
 
+// GetFrequencies provides a mock function with given fields:
+func (_m *Store) GetFrequencies() (*values.Frequencies, error) {
+	ret := _m.Called()
+
+	var r0 *values.Frequencies
+	if rf, ok := ret.Get(0).(func() *values.Frequencies); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*values.Frequencies)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHeartbeats provides a mock function with given fields: clusterUUID, since
+func (_m *Store) GetHeartbeats(clusterUUID string, since time.Time) ([]*values.HeartbeatRecord, error) {
+	ret := _m.Called(clusterUUID, since)
+
+	var r0 []*values.HeartbeatRecord
+	if rf, ok := ret.Get(0).(func(string, time.Time) []*values.HeartbeatRecord); ok {
+		r0 = rf(clusterUUID, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*values.HeartbeatRecord)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, time.Time) error); ok {
+		r1 = rf(clusterUUID, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUser provides a mock function with given fields: user
 func (_m *Store) GetUser(user string) (*values.User, error) {
 	ret := _m.Called(user)
@@ -263,6 +441,29 @@ func (_m *Store) GetUser(user string) (*values.User, error) {
 	return r0, r1
 }
 
+// GetUsers provides a mock function with given fields:
+func (_m *Store) GetUsers() ([]*values.User, error) {
+	ret := _m.Called()
+
+	var r0 []*values.User
+	if rf, ok := ret.Get(0).(func() []*values.User); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*values.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // IsInitialized provides a mock function with given fields:
 func (_m *Store) IsInitialized() (bool, error) {
 	ret := _m.Called()
@@ -284,6 +485,48 @@ func (_m *Store) IsInitialized() (bool, error) {
 	return r0, r1
 }
 
+// SetCheckerResult provides a mock function with given fields: result
+func (_m *Store) SetCheckerResult(result *values.WrappedCheckerResult) error {
+	ret := _m.Called(result)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*values.WrappedCheckerResult) error); ok {
+		r0 = rf(result)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetClusterFrequencies provides a mock function with given fields: frequencies
+func (_m *Store) SetClusterFrequencies(frequencies *values.ClusterFrequencies) error {
+	ret := _m.Called(frequencies)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*values.ClusterFrequencies) error); ok {
+		r0 = rf(frequencies)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetFrequencies provides a mock function with given fields: frequencies
+func (_m *Store) SetFrequencies(frequencies *values.Frequencies) error {
+	ret := _m.Called(frequencies)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*values.Frequencies) error); ok {
+		r0 = rf(frequencies)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateCluster provides a mock function with given fields: cluster
 func (_m *Store) UpdateCluster(cluster *values.CouchbaseCluster) error {
 	ret := _m.Called(cluster)
@@ -297,3 +540,17 @@ func (_m *Store) UpdateCluster(cluster *values.CouchbaseCluster) error {
 
 	return r0
 }
+
+// UpdateUser provides a mock function with given fields: user
+func (_m *Store) UpdateUser(user *values.User) error {
+	ret := _m.Called(user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*values.User) error); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -0,0 +1,145 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package sqlite
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+)
+
+// SetCheckerResult will add the result or replace the existing result for the same checker and scope.
+func (db *DB) SetCheckerResult(result *values.WrappedCheckerResult) error {
+	if result.Result == nil {
+		return fmt.Errorf("no result given")
+	}
+
+	byteTime, err := json.Marshal(result.Result.Time)
+	if err != nil {
+		return fmt.Errorf("could not marshal result time: %w", err)
+	}
+
+	// the value column is a BLOB so store NULL rather than an empty value
+	var value []byte
+	if len(result.Result.Value) > 0 {
+		value = result.Result.Value
+	}
+
+	_, err = db.sqlDB.Exec(`
+		INSERT OR REPLACE INTO checkerResults (name, remediation, value, status, time, version, clusterUUID, nodeUUID,
+		                                       bucketName, logFile)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`, result.Result.Name, result.Result.Remediation, value,
+		result.Result.Status, byteTime, result.Result.Version, result.Cluster, result.Node, result.Bucket,
+		result.LogFile)
+	if err != nil {
+		return fmt.Errorf("could not set checker result: %w", err)
+	}
+
+	return nil
+}
+
+// GetCheckerResult returns all the results that match the search.
+func (db *DB) GetCheckerResult(search values.CheckerSearch) ([]*values.WrappedCheckerResult, error) {
+	where, params := checkerSearchToWhere(search)
+
+	rows, err := db.sqlDB.Query(`
+		SELECT name, remediation, value, status, time, version, clusterUUID, nodeUUID, bucketName, logFile
+		FROM checkerResults`+where+` ORDER BY clusterUUID, name, nodeUUID, bucketName, logFile;`, params...)
+	if err != nil {
+		return nil, fmt.Errorf("could not get checker results: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]*values.WrappedCheckerResult, 0)
+	for rows.Next() {
+		result, err := scanCheckerResult(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed scanning checker result: %w", err)
+		}
+
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// DeleteOldCheckerResults deletes all the results for the cluster that are older than the given version.
+func (db *DB) DeleteOldCheckerResults(clusterUUID string, version int) error {
+	_, err := db.sqlDB.Exec("DELETE FROM checkerResults WHERE clusterUUID = ? AND version < ?;", clusterUUID,
+		version)
+	if err != nil {
+		return fmt.Errorf("could not delete old checker results: %w", err)
+	}
+
+	return nil
+}
+
+func checkerSearchToWhere(search values.CheckerSearch) (string, []interface{}) {
+	conditions := make([]string, 0)
+	params := make([]interface{}, 0)
+
+	addCondition := func(column string, value *string) {
+		if value == nil {
+			return
+		}
+
+		conditions = append(conditions, column+" = ?")
+		params = append(params, *value)
+	}
+
+	addCondition("name", search.Name)
+	addCondition("clusterUUID", search.Cluster)
+	addCondition("nodeUUID", search.Node)
+	addCondition("bucketName", search.Bucket)
+	addCondition("logFile", search.LogFile)
+
+	if search.Status != nil {
+		conditions = append(conditions, "status = ?")
+		params = append(params, *search.Status)
+	}
+
+	if len(conditions) == 0 {
+		return "", params
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), params
+}
+
+func scanCheckerResult(row scannable) (*values.WrappedCheckerResult, error) {
+	var (
+		result      values.WrappedCheckerResult
+		remediation *string
+		value       []byte
+		byteTime    []byte
+	)
+
+	result.Result = &values.CheckerResult{}
+	err := row.Scan(&result.Result.Name, &remediation, &value, &result.Result.Status, &byteTime,
+		&result.Result.Version, &result.Cluster, &result.Node, &result.Bucket, &result.LogFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if remediation != nil {
+		result.Result.Remediation = *remediation
+	}
+
+	if len(value) > 0 {
+		result.Result.Value = value
+	}
+
+	if err = json.Unmarshal(byteTime, &result.Result.Time); err != nil {
+		return nil, fmt.Errorf("could not unmarshal time for checker result '%s': %w", result.Result.Name, err)
+	}
+
+	return &result, nil
+}
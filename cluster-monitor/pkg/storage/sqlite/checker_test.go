@@ -0,0 +1,112 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package sqlite
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetAndGetCheckerResults(t *testing.T) {
+	db, _ := createEmptyDB(t)
+	defer db.Close()
+
+	now := time.Now().UTC()
+	results := []*values.WrappedCheckerResult{
+		{
+			Cluster: "c0",
+			Result: &values.CheckerResult{
+				Name:    "checker-0",
+				Status:  values.GoodCheckerStatus,
+				Time:    now,
+				Version: 1,
+			},
+		},
+		{
+			Cluster: "c0",
+			Node:    "n0",
+			Result: &values.CheckerResult{
+				Name:        "checker-1",
+				Status:      values.AlertCheckerStatus,
+				Remediation: "fix it",
+				Value:       json.RawMessage(`{"a":1}`),
+				Time:        now,
+				Version:     1,
+			},
+		},
+		{
+			Cluster: "c1",
+			Bucket:  "b0",
+			Result: &values.CheckerResult{
+				Name:    "checker-0",
+				Status:  values.WarnCheckerStatus,
+				Time:    now,
+				Version: 3,
+			},
+		},
+	}
+
+	for _, result := range results {
+		require.NoError(t, db.SetCheckerResult(result))
+	}
+
+	t.Run("get-all", func(t *testing.T) {
+		got, err := db.GetCheckerResult(values.CheckerSearch{})
+		require.NoError(t, err)
+		require.Equal(t, results, got)
+	})
+
+	t.Run("get-by-cluster", func(t *testing.T) {
+		cluster := "c0"
+		got, err := db.GetCheckerResult(values.CheckerSearch{Cluster: &cluster})
+		require.NoError(t, err)
+		require.Equal(t, results[:2], got)
+	})
+
+	t.Run("get-by-name-and-status", func(t *testing.T) {
+		name := "checker-0"
+		status := values.WarnCheckerStatus
+		got, err := db.GetCheckerResult(values.CheckerSearch{Name: &name, Status: &status})
+		require.NoError(t, err)
+		require.Equal(t, results[2:], got)
+	})
+
+	t.Run("get-none", func(t *testing.T) {
+		node := "n1"
+		got, err := db.GetCheckerResult(values.CheckerSearch{Node: &node})
+		require.NoError(t, err)
+		require.Len(t, got, 0)
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		updated := *results[0].Result
+		updated.Status = values.WarnCheckerStatus
+		updated.Version = 2
+		require.NoError(t, db.SetCheckerResult(&values.WrappedCheckerResult{Cluster: "c0", Result: &updated}))
+
+		name, cluster := "checker-0", "c0"
+		got, err := db.GetCheckerResult(values.CheckerSearch{Name: &name, Cluster: &cluster})
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		require.Equal(t, &updated, got[0].Result)
+	})
+
+	t.Run("delete-old", func(t *testing.T) {
+		require.NoError(t, db.DeleteOldCheckerResults("c0", 2))
+
+		got, err := db.GetCheckerResult(values.CheckerSearch{})
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		require.Equal(t, "checker-0", got[0].Result.Name)
+		require.Equal(t, 2, got[0].Result.Version)
+		require.Equal(t, "c1", got[1].Cluster)
+	})
+}
@@ -62,9 +62,9 @@ func (db *DB) AddCluster(cluster *values.CouchbaseCluster) error {
 
 	_, err = tx.Exec(`
 		INSERT INTO clusters (uuid, enterprise, name, nodes, buckets, info,  user, password, heartbeatIssue, cacert,
-		                      lastUpdate)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`, cluster.UUID, cluster.Enterprise, cluster.Name, nodes, buckets,
-		info, cluster.User, cluster.Password, cluster.HeartBeatIssue, cluster.CaCert, byteTime)
+		                      lastUpdate, requestTimeout)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`, cluster.UUID, cluster.Enterprise, cluster.Name, nodes, buckets,
+		info, cluster.User, cluster.Password, cluster.HeartBeatIssue, cluster.CaCert, byteTime, cluster.RequestTimeout)
 	if err != nil {
 		_ = tx.Rollback()
 		return fmt.Errorf("could not add cluster: %w", err)
@@ -89,7 +89,7 @@ func (db *DB) AddCluster(cluster *values.CouchbaseCluster) error {
 func (db *DB) GetClusters(sensitive bool, enterpriseOnly bool) ([]*values.CouchbaseCluster, error) {
 	clusters := make([]*values.CouchbaseCluster, 0)
 
-	parameters := "uuid, enterprise, name, nodes, buckets, info, heartbeatIssue, lastUpdate, alias"
+	parameters := "uuid, enterprise, name, nodes, buckets, info, heartbeatIssue, lastUpdate, requestTimeout, alias"
 	if sensitive {
 		parameters += ", user, password, cacert"
 	}
@@ -123,7 +123,7 @@ func (db *DB) GetClusters(sensitive bool, enterpriseOnly bool) ([]*values.Couchb
 }
 
 func (db *DB) GetCluster(uuid string, sensitive bool) (*values.CouchbaseCluster, error) {
-	parameters := "uuid, enterprise, name, nodes, buckets, info, heartbeatIssue, lastUpdate, alias"
+	parameters := "uuid, enterprise, name, nodes, buckets, info, heartbeatIssue, lastUpdate, requestTimeout, alias"
 	if sensitive {
 		parameters += ", user, password, cacert"
 	}
@@ -143,13 +143,45 @@ func (db *DB) GetCluster(uuid string, sensitive bool) (*values.CouchbaseCluster,
 	return cluster, nil
 }
 
+// DeleteCluster deletes the cluster as well as all the checker results, dismissals, heartbeat history and frequencies
+// for it.
 func (db *DB) DeleteCluster(uuid string) error {
-	_, err := db.sqlDB.Exec("DELETE FROM clusters WHERE uuid = ?;", uuid)
+	tx, err := db.sqlDB.BeginTx(context.Background(), nil)
 	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM clusters WHERE uuid = ?;", uuid)
+	if err != nil {
+		_ = tx.Rollback()
 		return fmt.Errorf("could not delete cluster: %w", err)
 	}
 
-	return nil
+	_, err = tx.Exec("DELETE FROM checkerResults WHERE clusterUUID = ?;", uuid)
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("could not delete checker results: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM dismissals WHERE clusterUUID = ?;", uuid)
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("could not delete dismissals: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM heartbeats WHERE clusterUUID = ?;", uuid)
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("could not delete heartbeats: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM clusterFrequencies WHERE clusterUUID = ?;", uuid)
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("could not delete cluster frequencies: %w", err)
+	}
+
+	return tx.Commit()
 }
 
 // UpdateCluster will update the value of any field in the cluster that is not empty/null. The uuid is immutable so that
@@ -221,6 +253,11 @@ func (db *DB) UpdateCluster(cluster *values.CouchbaseCluster) error {
 		toScan = append(toScan, cluster.CaCert)
 	}
 
+	if cluster.RequestTimeout != 0 {
+		parameters = append(parameters, "requestTimeout = ?")
+		toScan = append(toScan, cluster.RequestTimeout)
+	}
+
 	toScan = append(toScan, cluster.UUID)
 
 	_, err = db.sqlDB.Exec("UPDATE clusters SET "+strings.Join(parameters, ", ")+" WHERE uuid = ?;", toScan...)
@@ -238,10 +275,11 @@ func scanCluster(row scannable, sensitive bool) (*values.CouchbaseCluster, error
 	var err error
 	if sensitive {
 		err = row.Scan(&cluster.UUID, &cluster.Enterprise, &cluster.Name, &nodes, &buckets, &info,
-			&cluster.HeartBeatIssue, &byteTime, &alias, &cluster.User, &cluster.Password, &cluster.CaCert)
+			&cluster.HeartBeatIssue, &byteTime, &cluster.RequestTimeout, &alias, &cluster.User, &cluster.Password,
+			&cluster.CaCert)
 	} else {
 		err = row.Scan(&cluster.UUID, &cluster.Enterprise, &cluster.Name, &nodes, &buckets, &info,
-			&cluster.HeartBeatIssue, &byteTime, &alias)
+			&cluster.HeartBeatIssue, &byteTime, &cluster.RequestTimeout, &alias)
 	}
 
 	if err != nil {
@@ -392,6 +392,7 @@ func TestUpdateCluster(t *testing.T) {
 		DiskUsedByData: 1,
 	}
 	original.CaCert = []byte("not a cert but it does not matter")
+	original.RequestTimeout = 10 * time.Second
 
 	t.Run("update-all", func(t *testing.T) {
 		err := db.UpdateCluster(original)
@@ -412,5 +413,5 @@ func compareClusters(in, out *values.CouchbaseCluster, sensitive bool) bool {
 	return in.UUID == out.UUID && in.Name == out.Name && reflect.DeepEqual(in.NodesSummary, out.NodesSummary) &&
 		in.Enterprise == out.Enterprise && reflect.DeepEqual(in.BucketsSummary, out.BucketsSummary) &&
 		reflect.DeepEqual(in.ClusterInfo, out.ClusterInfo) && in.HeartBeatIssue == out.HeartBeatIssue &&
-		(!sensitive || (in.User == out.User && in.Password == out.Password))
+		in.RequestTimeout == out.RequestTimeout && (!sensitive || (in.User == out.User && in.Password == out.Password))
 }
@@ -20,7 +20,7 @@ import (
 
 type Version uint8
 
-const CurrentVersion = 1
+const CurrentVersion = 5
 
 // storeUpgradeFunctions has the functions to upgrade the DB from an older version. In general, storeUpgradeFunctions[N]
 // must execute the SQL needed to upgrade the DB from version N-1 to N, including incrementing the user_version.
@@ -120,6 +120,91 @@ var storeUpgradeFunctions = map[Version]func(db *sql.DB) error{
 		}
 		return nil
 	},
+	2: func(db *sql.DB) error {
+		// add user roles, existing users keep the access they had
+		_, err := db.Exec("ALTER TABLE users ADD COLUMN role VARCHAR(20);")
+		if err != nil {
+			return fmt.Errorf("could not add role column to users table: %w", err)
+		}
+
+		_, err = db.Exec("UPDATE users SET role = CASE WHEN admin THEN 'admin' ELSE 'viewer' END;")
+		if err != nil {
+			return fmt.Errorf("could not set roles for existing users: %w", err)
+		}
+
+		_, err = db.Exec("PRAGMA user_version=2;")
+		if err != nil {
+			return fmt.Errorf("could not set user_version: %w", err)
+		}
+		return nil
+	},
+	3: func(db *sql.DB) error {
+		// create a table for the heartbeat transitions of the clusters
+		_, err := db.Exec(`
+		CREATE TABLE heartbeats (
+			id INTEGER NOT NULL PRIMARY KEY,
+			clusterUUID VARCHAR(50) NOT NULL,
+			issue INT NOT NULL,
+			error TEXT,
+			latency INT,
+			time TIMESTAMP NOT NULL
+		);`)
+		if err != nil {
+			return fmt.Errorf("could not create heartbeats table: %w", err)
+		}
+
+		_, err = db.Exec("CREATE INDEX heartbeatsClusterTime ON heartbeats (clusterUUID, time);")
+		if err != nil {
+			return fmt.Errorf("could not create heartbeats index: %w", err)
+		}
+
+		_, err = db.Exec("PRAGMA user_version=3;")
+		if err != nil {
+			return fmt.Errorf("could not set user_version: %w", err)
+		}
+		return nil
+	},
+	4: func(db *sql.DB) error {
+		// create a table for the settings that can be changed at runtime
+		_, err := db.Exec(`
+		CREATE TABLE settings (
+			name VARCHAR(100) NOT NULL PRIMARY KEY,
+			value BLOB NOT NULL
+		);`)
+		if err != nil {
+			return fmt.Errorf("could not create settings table: %w", err)
+		}
+
+		// create a table for the frequencies that override the global ones for a cluster
+		_, err = db.Exec(`
+		CREATE TABLE clusterFrequencies (
+			clusterUUID VARCHAR(50) NOT NULL PRIMARY KEY,
+			heart INT NOT NULL DEFAULT 0,
+			status INT NOT NULL DEFAULT 0
+		);`)
+		if err != nil {
+			return fmt.Errorf("could not create clusterFrequencies table: %w", err)
+		}
+
+		_, err = db.Exec("PRAGMA user_version=4;")
+		if err != nil {
+			return fmt.Errorf("could not set user_version: %w", err)
+		}
+		return nil
+	},
+	5: func(db *sql.DB) error {
+		// add the per cluster request timeout, existing clusters use the default one
+		_, err := db.Exec("ALTER TABLE clusters ADD COLUMN requestTimeout INT NOT NULL DEFAULT 0;")
+		if err != nil {
+			return fmt.Errorf("could not add requestTimeout column to clusters table: %w", err)
+		}
+
+		_, err = db.Exec("PRAGMA user_version=5;")
+		if err != nil {
+			return fmt.Errorf("could not set user_version: %w", err)
+		}
+		return nil
+	},
 }
 
 type scannable interface {
@@ -183,7 +268,8 @@ func verifyStore(db *DB) error {
 
 	// confirm that the tables we need exists
 	// the interface{} is because that's the parameter type of QueryRow
-	requiredTables := []interface{}{"clusters", "users", "checkerResults", "dismissals", "aliases"}
+	requiredTables := []interface{}{"clusters", "users", "checkerResults", "dismissals", "aliases", "heartbeats",
+		"settings", "clusterFrequencies"}
 	requiredTableParams := strings.TrimSuffix(strings.Repeat("?,", len(requiredTables)), ",")
 	results := db.sqlDB.QueryRow(fmt.Sprintf(`
 		SELECT count(*) FROM sqlite_master
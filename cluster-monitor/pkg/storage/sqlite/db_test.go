@@ -149,10 +149,12 @@ func TestDBIsInitialized(t *testing.T) {
 }
 
 func TestDBUpgrades(t *testing.T) {
-	db, _ := createEmptyDBOnVersion0(t)
 	for targetVersion := 1; targetVersion <= CurrentVersion; targetVersion++ {
 		t.Run(fmt.Sprintf("%d-to-%d", targetVersion-1, targetVersion), func(t *testing.T) {
-			for runUpgradeVersion := 1; runUpgradeVersion < targetVersion; runUpgradeVersion++ {
+			db, _ := createEmptyDBOnVersion0(t)
+			defer db.Close()
+
+			for runUpgradeVersion := 1; runUpgradeVersion <= targetVersion; runUpgradeVersion++ {
 				err := storeUpgradeFunctions[Version(runUpgradeVersion)](db.sqlDB)
 				require.NoErrorf(t, err, "failed upgrade to %d", runUpgradeVersion)
 				row := db.sqlDB.QueryRow("PRAGMA user_version")
@@ -0,0 +1,115 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+)
+
+func (db *DB) AddDismissal(dismissal values.Dismissal) error {
+	if err := dismissal.Validate(); err != nil {
+		return fmt.Errorf("invalid dismissal: %w", err)
+	}
+
+	var until *time.Time
+	if !dismissal.Forever {
+		utcUntil := dismissal.Until.UTC()
+		until = &utcUntil
+	}
+
+	_, err := db.sqlDB.Exec(`
+		INSERT INTO dismissals (id, level, checkerName, clusterUUID, bucket, nodeUUID, file, forever, until)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);`, dismissal.ID, dismissal.Level, dismissal.CheckerName,
+		dismissal.ClusterUUID, dismissal.BucketName, dismissal.NodeUUID, dismissal.LogFile, dismissal.Forever, until)
+	if err != nil {
+		return fmt.Errorf("could not add dismissal: %w", err)
+	}
+
+	return nil
+}
+
+// GetDismissals returns the dismissals that match the search. Unless the search says otherwise expired dismissals are
+// not returned.
+func (db *DB) GetDismissals(search values.DismissalSearchSpace) ([]*values.Dismissal, error) {
+	conditions := make([]string, 0)
+	params := make([]interface{}, 0)
+
+	if search.ID != nil {
+		conditions = append(conditions, "id = ?")
+		params = append(params, *search.ID)
+	}
+
+	if search.CheckerName != nil {
+		conditions = append(conditions, "checkerName = ?")
+		params = append(params, *search.CheckerName)
+	}
+
+	if search.ClusterUUID != nil {
+		conditions = append(conditions, "(clusterUUID = ? OR level = ?)")
+		params = append(params, *search.ClusterUUID, values.AllDismissLevel)
+	}
+
+	if !search.IncludeExpired {
+		conditions = append(conditions, "(forever = true OR until > ?)")
+		params = append(params, time.Now().UTC())
+	}
+
+	var where string
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	rows, err := db.sqlDB.Query(`
+		SELECT id, level, checkerName, clusterUUID, bucket, nodeUUID, file, forever, until
+		FROM dismissals`+where+` ORDER BY id;`, params...)
+	if err != nil {
+		return nil, fmt.Errorf("could not get dismissals: %w", err)
+	}
+	defer rows.Close()
+
+	dismissals := make([]*values.Dismissal, 0)
+	for rows.Next() {
+		var (
+			dismissal                           values.Dismissal
+			clusterUUID, bucket, nodeUUID, file sql.NullString
+			until                               sql.NullTime
+		)
+
+		err = rows.Scan(&dismissal.ID, &dismissal.Level, &dismissal.CheckerName, &clusterUUID, &bucket, &nodeUUID,
+			&file, &dismissal.Forever, &until)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan dismissal: %w", err)
+		}
+
+		dismissal.ClusterUUID = clusterUUID.String
+		dismissal.BucketName = bucket.String
+		dismissal.NodeUUID = nodeUUID.String
+		dismissal.LogFile = file.String
+		dismissal.Until = until.Time
+
+		dismissals = append(dismissals, &dismissal)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	return dismissals, nil
+}
+
+func (db *DB) DeleteDismissal(id string) error {
+	res, err := db.sqlDB.Exec("DELETE FROM dismissals WHERE id = ?;", id)
+	if err != nil {
+		return fmt.Errorf("could not delete dismissal: %w", err)
+	}
+
+	return checkAffected(res)
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddGetAndDeleteDismissals(t *testing.T) {
+	db, _ := createEmptyDB(t)
+	defer db.Close()
+
+	until := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	dismissals := []values.Dismissal{
+		{ID: "d0", Level: values.AllDismissLevel, CheckerName: "checker-0", Forever: true},
+		{
+			ID:          "d1",
+			Level:       values.BucketDismissLevel,
+			CheckerName: "checker-1",
+			ClusterUUID: "c0",
+			BucketName:  "b0",
+			Until:       until,
+		},
+		{
+			ID:          "d2",
+			Level:       values.NodeDismissLevel,
+			CheckerName: "checker-1",
+			ClusterUUID: "c1",
+			NodeUUID:    "n0",
+			Until:       time.Now().UTC().Add(-time.Hour),
+		},
+	}
+
+	for _, dismissal := range dismissals {
+		require.NoError(t, db.AddDismissal(dismissal))
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		require.Error(t, db.AddDismissal(values.Dismissal{ID: "d3", Level: values.ClusterDismissLevel,
+			CheckerName: "checker-0", Forever: true}))
+	})
+
+	t.Run("getActive", func(t *testing.T) {
+		got, err := db.GetDismissals(values.DismissalSearchSpace{})
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		require.Equal(t, &dismissals[0], got[0])
+		require.Equal(t, "d1", got[1].ID)
+		require.True(t, until.Equal(got[1].Until))
+	})
+
+	t.Run("includeExpired", func(t *testing.T) {
+		got, err := db.GetDismissals(values.DismissalSearchSpace{IncludeExpired: true})
+		require.NoError(t, err)
+		require.Len(t, got, 3)
+	})
+
+	t.Run("byCluster", func(t *testing.T) {
+		cluster := "c0"
+		got, err := db.GetDismissals(values.DismissalSearchSpace{ClusterUUID: &cluster})
+		require.NoError(t, err)
+		// the global dismissal applies to every cluster
+		require.Len(t, got, 2)
+
+		checker := "checker-1"
+		got, err = db.GetDismissals(values.DismissalSearchSpace{ClusterUUID: &cluster, CheckerName: &checker})
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		require.Equal(t, "d1", got[0].ID)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, db.DeleteDismissal("d0"))
+		require.ErrorIs(t, db.DeleteDismissal("d0"), values.ErrNotFound)
+
+		got, err := db.GetDismissals(values.DismissalSearchSpace{IncludeExpired: true})
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+	})
+}
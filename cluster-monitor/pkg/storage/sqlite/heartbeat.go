@@ -0,0 +1,92 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+)
+
+// AddHeartbeat records the heartbeat if it changes the state of the cluster, that is if the issue is different from the
+// one in the last record for the cluster. Heartbeats that do not change the state are ignored.
+func (db *DB) AddHeartbeat(record *values.HeartbeatRecord) error {
+	var lastIssue values.HeartIssue
+	err := db.sqlDB.QueryRow("SELECT issue FROM heartbeats WHERE clusterUUID = ? ORDER BY time DESC, id DESC LIMIT 1;",
+		record.ClusterUUID).Scan(&lastIssue)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("could not get last heartbeat: %w", err)
+	}
+
+	if err == nil && lastIssue == record.Issue {
+		return nil
+	}
+
+	_, err = db.sqlDB.Exec("INSERT INTO heartbeats (clusterUUID, issue, error, latency, time) VALUES (?, ?, ?, ?, ?);",
+		record.ClusterUUID, record.Issue, record.Error, int64(record.Latency), record.Time.UTC())
+	if err != nil {
+		return fmt.Errorf("could not add heartbeat: %w", err)
+	}
+
+	return nil
+}
+
+// GetHeartbeats returns the heartbeat history of the cluster since the given time in chronological order. The last
+// record before since is also returned as it gives the state of the cluster at that time.
+func (db *DB) GetHeartbeats(clusterUUID string, since time.Time) ([]*values.HeartbeatRecord, error) {
+	rows, err := db.sqlDB.Query(`
+		SELECT clusterUUID, issue, error, latency, time FROM heartbeats
+		WHERE clusterUUID = ? AND (time >= ? OR id = (
+			SELECT id FROM heartbeats WHERE clusterUUID = ? AND time < ? ORDER BY time DESC, id DESC LIMIT 1
+		))
+		ORDER BY time ASC, id ASC;`, clusterUUID, since.UTC(), clusterUUID, since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("could not get heartbeats: %w", err)
+	}
+	defer rows.Close()
+
+	records := make([]*values.HeartbeatRecord, 0)
+	for rows.Next() {
+		var (
+			record  values.HeartbeatRecord
+			errMsg  sql.NullString
+			latency sql.NullInt64
+		)
+
+		if err = rows.Scan(&record.ClusterUUID, &record.Issue, &errMsg, &latency, &record.Time); err != nil {
+			return nil, fmt.Errorf("could not scan heartbeat: %w", err)
+		}
+
+		record.Error = errMsg.String
+		record.Latency = time.Duration(latency.Int64)
+		records = append(records, &record)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	return records, nil
+}
+
+// DeleteHeartbeatsBefore removes the heartbeat records older than the given time. The latest record for each cluster is
+// always kept so its current state is not lost.
+func (db *DB) DeleteHeartbeatsBefore(before time.Time) error {
+	_, err := db.sqlDB.Exec(`
+		DELETE FROM heartbeats WHERE time < ? AND id NOT IN (
+			SELECT id FROM heartbeats AS latest WHERE latest.id = (
+				SELECT id FROM heartbeats WHERE clusterUUID = latest.clusterUUID ORDER BY time DESC, id DESC LIMIT 1
+			)
+		);`, before.UTC())
+	if err != nil {
+		return fmt.Errorf("could not delete old heartbeats: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddGetAndDeleteHeartbeats(t *testing.T) {
+	db, _ := createEmptyDB(t)
+	defer db.Close()
+
+	start := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	records := []*values.HeartbeatRecord{
+		{ClusterUUID: "c0", Issue: values.NoHeartIssue, Latency: time.Millisecond, Time: start},
+		// same state so it is ignored
+		{ClusterUUID: "c0", Issue: values.NoHeartIssue, Latency: time.Millisecond, Time: start.Add(time.Minute)},
+		{
+			ClusterUUID: "c0",
+			Issue:       values.NoConnectionHeartIssue,
+			Error:       "connection refused",
+			Latency:     time.Second,
+			Time:        start.Add(2 * time.Minute),
+		},
+		{ClusterUUID: "c0", Issue: values.NoHeartIssue, Latency: time.Millisecond, Time: start.Add(5 * time.Minute)},
+		{ClusterUUID: "c1", Issue: values.BadAuthHeartIssue, Error: "bad auth", Time: start},
+	}
+
+	for _, record := range records {
+		require.NoError(t, db.AddHeartbeat(record))
+	}
+
+	t.Run("all", func(t *testing.T) {
+		got, err := db.GetHeartbeats("c0", time.Time{})
+		require.NoError(t, err)
+		require.Equal(t, []*values.HeartbeatRecord{records[0], records[2], records[3]}, got)
+	})
+
+	t.Run("since-includes-previous-state", func(t *testing.T) {
+		got, err := db.GetHeartbeats("c0", start.Add(3*time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, []*values.HeartbeatRecord{records[2], records[3]}, got)
+	})
+
+	t.Run("delete-keeps-latest", func(t *testing.T) {
+		require.NoError(t, db.DeleteHeartbeatsBefore(start.Add(time.Hour)))
+
+		got, err := db.GetHeartbeats("c0", time.Time{})
+		require.NoError(t, err)
+		require.Equal(t, []*values.HeartbeatRecord{records[3]}, got)
+
+		got, err = db.GetHeartbeats("c1", time.Time{})
+		require.NoError(t, err)
+		require.Equal(t, []*values.HeartbeatRecord{records[4]}, got)
+	})
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+)
+
+const frequenciesSetting = "frequencies"
+
+// GetFrequencies returns the monitor frequencies, if they have never been set all of them are zero.
+func (db *DB) GetFrequencies() (*values.Frequencies, error) {
+	var frequencies values.Frequencies
+	if err := db.getSetting(frequenciesSetting, &frequencies); err != nil {
+		return nil, err
+	}
+
+	return &frequencies, nil
+}
+
+func (db *DB) SetFrequencies(frequencies *values.Frequencies) error {
+	return db.setSetting(frequenciesSetting, frequencies)
+}
+
+// getSetting unmarshals the setting into value. If the setting does not exist value is left as it is.
+func (db *DB) getSetting(name string, value interface{}) error {
+	var data []byte
+	err := db.sqlDB.QueryRow("SELECT value FROM settings WHERE name = ?;", name).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("could not get setting '%s': %w", name, err)
+	}
+
+	if err = json.Unmarshal(data, value); err != nil {
+		return fmt.Errorf("could not unmarshal setting '%s': %w", name, err)
+	}
+
+	return nil
+}
+
+func (db *DB) setSetting(name string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("could not marshal setting '%s': %w", name, err)
+	}
+
+	_, err = db.sqlDB.Exec("INSERT OR REPLACE INTO settings (name, value) VALUES (?, ?);", name, data)
+	if err != nil {
+		return fmt.Errorf("could not set setting '%s': %w", name, err)
+	}
+
+	return nil
+}
+
+// GetClusterFrequencies returns the frequencies of all the clusters that override the global ones.
+func (db *DB) GetClusterFrequencies() ([]*values.ClusterFrequencies, error) {
+	rows, err := db.sqlDB.Query("SELECT clusterUUID, heart, status FROM clusterFrequencies ORDER BY clusterUUID ASC;")
+	if err != nil {
+		return nil, fmt.Errorf("could not get cluster frequencies: %w", err)
+	}
+	defer rows.Close()
+
+	frequencies := make([]*values.ClusterFrequencies, 0)
+	for rows.Next() {
+		var (
+			clusterFrequencies values.ClusterFrequencies
+			heart, status      int64
+		)
+
+		if err = rows.Scan(&clusterFrequencies.ClusterUUID, &heart, &status); err != nil {
+			return nil, fmt.Errorf("could not scan cluster frequencies: %w", err)
+		}
+
+		clusterFrequencies.Heart = time.Duration(heart)
+		clusterFrequencies.Status = time.Duration(status)
+		frequencies = append(frequencies, &clusterFrequencies)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	return frequencies, nil
+}
+
+// SetClusterFrequencies sets the frequencies for the cluster replacing any it had before.
+func (db *DB) SetClusterFrequencies(frequencies *values.ClusterFrequencies) error {
+	_, err := db.sqlDB.Exec("INSERT OR REPLACE INTO clusterFrequencies (clusterUUID, heart, status) VALUES (?, ?, ?);",
+		frequencies.ClusterUUID, int64(frequencies.Heart), int64(frequencies.Status))
+	if err != nil {
+		return fmt.Errorf("could not set cluster frequencies: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteClusterFrequencies removes the frequencies for the cluster so it uses the global ones again.
+func (db *DB) DeleteClusterFrequencies(clusterUUID string) error {
+	res, err := db.sqlDB.Exec("DELETE FROM clusterFrequencies WHERE clusterUUID = ?;", clusterUUID)
+	if err != nil {
+		return fmt.Errorf("could not delete cluster frequencies: %w", err)
+	}
+
+	return checkAffected(res)
+}
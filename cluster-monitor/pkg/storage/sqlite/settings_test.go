@@ -0,0 +1,64 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAndSetFrequencies(t *testing.T) {
+	db, _ := createEmptyDB(t)
+	defer db.Close()
+
+	frequencies, err := db.GetFrequencies()
+	require.NoError(t, err)
+	require.Equal(t, &values.Frequencies{}, frequencies)
+
+	expected := &values.Frequencies{Heart: 10 * time.Second, Status: time.Minute, Discovery: time.Hour}
+	require.NoError(t, db.SetFrequencies(expected))
+
+	frequencies, err = db.GetFrequencies()
+	require.NoError(t, err)
+	require.Equal(t, expected, frequencies)
+
+	expected.Heart = 30 * time.Second
+	require.NoError(t, db.SetFrequencies(expected))
+
+	frequencies, err = db.GetFrequencies()
+	require.NoError(t, err)
+	require.Equal(t, expected, frequencies)
+}
+
+func TestClusterFrequencies(t *testing.T) {
+	db, _ := createEmptyDB(t)
+	defer db.Close()
+
+	critical := &values.ClusterFrequencies{ClusterUUID: "c0", Heart: 10 * time.Second}
+	dev := &values.ClusterFrequencies{ClusterUUID: "c1", Heart: 10 * time.Minute, Status: time.Hour}
+
+	require.NoError(t, db.SetClusterFrequencies(dev))
+	require.NoError(t, db.SetClusterFrequencies(critical))
+
+	frequencies, err := db.GetClusterFrequencies()
+	require.NoError(t, err)
+	require.Equal(t, []*values.ClusterFrequencies{critical, dev}, frequencies)
+
+	// setting them again replaces the old ones
+	critical.Status = time.Minute
+	require.NoError(t, db.SetClusterFrequencies(critical))
+
+	require.NoError(t, db.DeleteClusterFrequencies("c1"))
+	require.ErrorIs(t, db.DeleteClusterFrequencies("c1"), values.ErrNotFound)
+
+	frequencies, err = db.GetClusterFrequencies()
+	require.NoError(t, err)
+	require.Equal(t, []*values.ClusterFrequencies{critical}, frequencies)
+}
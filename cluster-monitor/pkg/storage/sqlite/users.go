@@ -9,6 +9,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/storage"
 
@@ -17,9 +18,24 @@ import (
 	"github.com/couchbaselabs/workbench-prototype/cluster-monitor/pkg/values"
 )
 
+// AddUser adds the user. If no role is given the user gets the admin role if Admin is set or the viewer role
+// otherwise, the user is updated to reflect that.
 func (db *DB) AddUser(user *values.User) error {
-	_, err := db.sqlDB.Exec("INSERT INTO users (user, password, admin) VALUES (?, ?, ?);", user.User, user.Password,
-		user.Admin)
+	if user.Role == "" {
+		user.Role = values.ViewerRole
+		if user.Admin {
+			user.Role = values.AdminRole
+		}
+	}
+
+	if err := user.Role.Validate(); err != nil {
+		return err
+	}
+
+	user.Admin = user.Role == values.AdminRole
+
+	_, err := db.sqlDB.Exec("INSERT INTO users (user, password, admin, role) VALUES (?, ?, ?, ?);", user.User,
+		user.Password, user.Admin, user.Role)
 	if err != nil {
 		if sqlErr, ok := err.(sqlite3.Error); ok {
 			if errors.Is(sqlErr.ExtendedCode, sqlite3.ErrConstraintUnique) {
@@ -33,9 +49,9 @@ func (db *DB) AddUser(user *values.User) error {
 }
 
 func (db *DB) GetUser(user string) (*values.User, error) {
-	result := db.sqlDB.QueryRow("SELECT password, admin FROM users WHERE user = ?;", user)
-	returnUser := &values.User{User: user}
-	if err := result.Scan(&returnUser.Password, &returnUser.Admin); err != nil {
+	result := db.sqlDB.QueryRow("SELECT user, password, admin, role FROM users WHERE user = ?;", user)
+	returnUser, err := scanUser(result)
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, values.ErrNotFound
 		}
@@ -45,3 +61,103 @@ func (db *DB) GetUser(user string) (*values.User, error) {
 
 	return returnUser, nil
 }
+
+func (db *DB) GetUsers() ([]*values.User, error) {
+	rows, err := db.sqlDB.Query("SELECT user, password, admin, role FROM users ORDER BY user;")
+	if err != nil {
+		return nil, fmt.Errorf("could not get users: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]*values.User, 0)
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan user: %w", err)
+		}
+
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	return users, nil
+}
+
+// UpdateUser updates the password and role of the user if they are set.
+func (db *DB) UpdateUser(user *values.User) error {
+	parameters := make([]string, 0, 3)
+	toScan := make([]interface{}, 0, 4)
+
+	if len(user.Password) != 0 {
+		parameters = append(parameters, "password = ?")
+		toScan = append(toScan, user.Password)
+	}
+
+	if user.Role != "" {
+		if err := user.Role.Validate(); err != nil {
+			return err
+		}
+
+		parameters = append(parameters, "role = ?", "admin = ?")
+		toScan = append(toScan, user.Role, user.Role == values.AdminRole)
+	}
+
+	if len(parameters) == 0 {
+		return fmt.Errorf("nothing to update")
+	}
+
+	toScan = append(toScan, user.User)
+	res, err := db.sqlDB.Exec("UPDATE users SET "+strings.Join(parameters, ", ")+" WHERE user = ?;", toScan...)
+	if err != nil {
+		return fmt.Errorf("could not update user: %w", err)
+	}
+
+	return checkAffected(res)
+}
+
+func (db *DB) DeleteUser(user string) error {
+	res, err := db.sqlDB.Exec("DELETE FROM users WHERE user = ?;", user)
+	if err != nil {
+		return fmt.Errorf("could not delete user: %w", err)
+	}
+
+	return checkAffected(res)
+}
+
+// checkAffected returns values.ErrNotFound if the statement did not affect any rows.
+func checkAffected(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("could not get number of affected rows: %w", err)
+	}
+
+	if affected == 0 {
+		return values.ErrNotFound
+	}
+
+	return nil
+}
+
+func scanUser(row scannable) (*values.User, error) {
+	var (
+		user values.User
+		role sql.NullString
+	)
+
+	if err := row.Scan(&user.User, &user.Password, &user.Admin, &role); err != nil {
+		return nil, err
+	}
+
+	user.Role = values.Role(role.String)
+	if user.Role == "" {
+		user.Role = values.ViewerRole
+		if user.Admin {
+			user.Role = values.AdminRole
+		}
+	}
+
+	return &user, nil
+}
@@ -78,3 +78,74 @@ func TestDBAddAndGetUser(t *testing.T) {
 		}
 	})
 }
+
+func TestDBUpdateAndDeleteUsers(t *testing.T) {
+	db, _ := createEmptyDB(t)
+	defer db.Close()
+
+	require.NoError(t, db.AddUser(&values.User{User: "doc", Password: []byte("password"), Admin: true}))
+	require.NoError(t, db.AddUser(&values.User{User: "bashful", Password: []byte("password"),
+		Role: values.OperatorRole}))
+	require.Error(t, db.AddUser(&values.User{User: "sleepy", Password: []byte("password"), Role: "owner"}))
+
+	t.Run("get-all", func(t *testing.T) {
+		users, err := db.GetUsers()
+		require.NoError(t, err)
+		require.Equal(t, []*values.User{
+			{User: "bashful", Password: []byte("password"), Role: values.OperatorRole},
+			{User: "doc", Password: []byte("password"), Admin: true, Role: values.AdminRole},
+		}, users)
+	})
+
+	t.Run("update-role", func(t *testing.T) {
+		require.NoError(t, db.UpdateUser(&values.User{User: "bashful", Role: values.AdminRole}))
+
+		user, err := db.GetUser("bashful")
+		require.NoError(t, err)
+		require.Equal(t, &values.User{User: "bashful", Password: []byte("password"), Admin: true,
+			Role: values.AdminRole}, user)
+	})
+
+	t.Run("update-password", func(t *testing.T) {
+		require.NoError(t, db.UpdateUser(&values.User{User: "doc", Password: []byte("new")}))
+
+		user, err := db.GetUser("doc")
+		require.NoError(t, err)
+		require.Equal(t, []byte("new"), user.Password)
+		require.Equal(t, values.AdminRole, user.Role)
+	})
+
+	t.Run("update-does-not-exist", func(t *testing.T) {
+		require.ErrorIs(t, db.UpdateUser(&values.User{User: "happy", Role: values.ViewerRole}), values.ErrNotFound)
+	})
+
+	t.Run("update-nothing", func(t *testing.T) {
+		require.Error(t, db.UpdateUser(&values.User{User: "happy"}))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, db.DeleteUser("bashful"))
+		require.ErrorIs(t, db.DeleteUser("bashful"), values.ErrNotFound)
+
+		_, err := db.GetUser("bashful")
+		require.ErrorIs(t, err, values.ErrNotFound)
+	})
+}
+
+func TestDBUserRolesUpgrade(t *testing.T) {
+	db, _ := createEmptyDBOnVersion0(t)
+	defer db.Close()
+
+	require.NoError(t, storeUpgradeFunctions[1](db.sqlDB))
+	_, err := db.sqlDB.Exec(`INSERT INTO users (user, password, admin) VALUES ("doc", "password", true),
+		("grumpy", "password", false);`)
+	require.NoError(t, err)
+
+	require.NoError(t, storeUpgradeFunctions[2](db.sqlDB))
+
+	users, err := db.GetUsers()
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	require.Equal(t, values.AdminRole, users[0].Role)
+	require.Equal(t, values.ViewerRole, users[1].Role)
+}
@@ -0,0 +1,569 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package values
+
+// CheckerDefinition describes a checker. The ID is the stable identifier used in the documentation (checkers.adoc)
+// while the name is the identifier used for the results.
+type CheckerDefinition struct {
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	Remediation string      `json:"remediation"`
+	Type        CheckerType `json:"type"`
+}
+
+// Cluster checker names.
+const (
+	CheckSingleOrTwoNodeCluster    = "singleOrTwoNodeCluster"
+	CheckMixedMode                 = "mixedMode"
+	CheckServerQuota               = "serverQuota"
+	CheckGlobalAutoCompaction      = "globalAutoCompaction"
+	CheckAutoFailoverEnabled       = "autoFailoverEnabled"
+	CheckNumberOfBuckets           = "numberOfBuckets"
+	CheckMissingActiveVBuckets     = "missingActiveVBuckets"
+	CheckMissingReplicaVBuckets    = "missingReplicaVBuckets"
+	CheckDataLoss                  = "dataLoss"
+	CheckActiveClusterNodes        = "activeClusterNodes"
+	CheckAsymmetricalCluster       = "asymmetricalCluster"
+	CheckBackupLocation            = "backupLocation"
+	CheckOrphanedBackupTasks       = "orphanedBackupTasks"
+	CheckIndexWithNoRedundancy     = "indexWithNoRedundancy"
+	CheckBadRedundantIndex         = "badRedundantIndex"
+	CheckTooManyIndexReplicas      = "tooManyIndexReplicas"
+	CheckEmptyServerGroup          = "emptyServerGroup"
+	CheckDeveloperPreview          = "developerPreview"
+	CheckNodeToNodeCommunication   = "nodeToNodeCommunication"
+	CheckMissingIndexPartitions    = "missingIndexPartitions"
+	CheckImbalancedIndexPartitions = "imbalancedIndexPartitions"
+)
+
+// Node checker names.
+const (
+	CheckOneServicePerNode     = "oneServicePerNode"
+	CheckUnhealthyNode         = "unhealthyNode"
+	CheckSupportedVersion      = "supportedVersion"
+	CheckGABuild               = "gaBuild"
+	CheckNodeSwapUsage         = "nodeSwapUsage"
+	CheckCPUBucketCount        = "cpuBucketCount"
+	CheckNodeDiskSpace         = "nodeDiskSpace"
+	CheckTransparentHugePages  = "transparentHugePages"
+	CheckServiceStatus         = "serviceStatus"
+	CheckGSILogLevel           = "gsiLogLevel"
+	CheckSharedFilesystems     = "sharedFilesystems"
+	CheckMinimumNodeMemory     = "minimumNodeMemory"
+	CheckSupportedOS           = "supportedOS"
+	CheckSegmentationFaults    = "segmentationFaults"
+	CheckManagedProcessCrash   = "managedProcessCrash"
+	CheckFreeMemory            = "freeMemory"
+	CheckProcessLimits         = "processLimits"
+	CheckOOMKills              = "oomKills"
+	CheckDuplicateNodeUUID     = "duplicateNodeUUID"
+	CheckNodeExporterAlert     = "nodeExporterAlert"
+	CheckSYNFlooding           = "synFlooding"
+	CheckCPUSoftLockup         = "cpuSoftLockup"
+	CheckConnTrackingTableFull = "connTrackingTableFull"
+)
+
+// Bucket checker names.
+const (
+	CheckResidentRatio          = "residentRatio"
+	CheckReplicaVBucketNumber   = "replicaVBucketNumber"
+	CheckBucketMemoryUsage      = "bucketMemoryUsage"
+	CheckDCPPaused              = "dcpPaused"
+	CheckLargeCheckpoints       = "largeCheckpoints"
+	CheckSlowOperations         = "slowOperations"
+	CheckMemcachedFragmentation = "memcachedFragmentation"
+	CheckUnknownStorageEngine   = "unknownStorageEngine"
+	CheckTooManyFTSReplicas     = "tooManyFTSReplicas"
+	CheckHistogramUnderflow     = "histogramUnderflow"
+	CheckMaxTTL                 = "maxTTL"
+	CheckNonDefaultVBucketCount = "nonDefaultVBucketCount"
+)
+
+// AllCheckerDefs has the definition of every checker keyed by name. Every ID must be documented in checkers.adoc, this
+// is validated by tools/validate-checker-docs.go.
+var AllCheckerDefs = map[string]CheckerDefinition{
+	CheckSingleOrTwoNodeCluster: {
+		ID:    "CB90002",
+		Name:  CheckSingleOrTwoNodeCluster,
+		Title: "Single or Two-Node Cluster",
+		Description: "Checks that the cluster has at least three nodes. Clusters with fewer nodes cannot automatically " +
+			"fail over and are limited to 0 or 1 bucket replicas.",
+		Remediation: "Add more nodes to the cluster.",
+		Type:        ClusterCheckerType,
+	},
+	CheckMixedMode: {
+		ID:    "CB90004",
+		Name:  CheckMixedMode,
+		Title: "Mixed Mode Cluster",
+		Description: "Checks that all nodes run the same Couchbase Server version. Running multiple versions is only " +
+			"recommended during an upgrade.",
+		Remediation: "Upgrade all nodes to the same version. If this alert is present during an upgrade, it can " +
+			"safely be disregarded until the upgrade is complete.",
+		Type: ClusterCheckerType,
+	},
+	CheckServerQuota: {
+		ID:    "CB90005",
+		Name:  CheckServerQuota,
+		Title: "Server Quota",
+		Description: "Checks that the memory allocated to Couchbase Server is no more than 80% of the hosts' memory, " +
+			"so the operating system has enough memory left to function.",
+		Remediation: "Increase the amount of memory on the nodes, or reduce the Couchbase Server memory quota.",
+		Type:        ClusterCheckerType,
+	},
+	CheckGlobalAutoCompaction: {
+		ID:          "CB90006",
+		Name:        CheckGlobalAutoCompaction,
+		Title:       "Global Auto-Compaction",
+		Description: "Checks that an auto-compaction threshold is set, otherwise performance can be degraded.",
+		Remediation: "Enable auto-compaction in the cluster settings.",
+		Type:        ClusterCheckerType,
+	},
+	CheckAutoFailoverEnabled: {
+		ID:    "CB90007",
+		Name:  CheckAutoFailoverEnabled,
+		Title: "Auto-Failover Enabled",
+		Description: "Checks that auto-failover is enabled. If it is disabled node failure will result in some " +
+			"requests being unable to be serviced.",
+		Remediation: "Adjust auto-failover settings.",
+		Type:        ClusterCheckerType,
+	},
+	CheckNumberOfBuckets: {
+		ID:    "CB90008",
+		Name:  CheckNumberOfBuckets,
+		Title: "Number of Buckets",
+		Description: "Checks that there are no more than 30 buckets in the cluster. Going above this number may " +
+			"cause performance degradation.",
+		Remediation: "Reduce the number of buckets in the cluster.",
+		Type:        ClusterCheckerType,
+	},
+	CheckMissingActiveVBuckets: {
+		ID:          "CB90009",
+		Name:        CheckMissingActiveVBuckets,
+		Title:       "Missing Active vBuckets",
+		Description: "Checks that no active vBuckets are reported as missing by the Cluster Manager.",
+		Remediation: "Rebalance the cluster, adding new nodes if necessary.",
+		Type:        ClusterCheckerType,
+	},
+	CheckMissingReplicaVBuckets: {
+		ID:          "CB90010",
+		Name:        CheckMissingReplicaVBuckets,
+		Title:       "Missing Replica vBuckets",
+		Description: "Checks that no replica vBuckets are reported as missing by the Cluster Manager.",
+		Remediation: "Rebalance the cluster, adding new nodes if necessary.",
+		Type:        ClusterCheckerType,
+	},
+	CheckDataLoss: {
+		ID:          "CB90011",
+		Name:        CheckDataLoss,
+		Title:       "Data Loss Messages",
+		Description: "Looks for messages indicating data loss due to failover in the cluster logs.",
+		Remediation: "Contact Couchbase Technical Support immediately. Isolate the failed-over node, and do not make " +
+			"any changes to its configuration or attempt to recover it unless instructed by Couchbase Technical " +
+			"Support.",
+		Type: ClusterCheckerType,
+	},
+	CheckActiveClusterNodes: {
+		ID:          "CB90016",
+		Name:        CheckActiveClusterNodes,
+		Title:       "All Nodes are Active",
+		Description: "Checks that the Cluster Manager reports all nodes in the cluster as active.",
+		Remediation: "Rebalance the unhealthy nodes out of the cluster, and replace them if appropriate. Examine the " +
+			"other health check results to identify the potential cause, or contact Couchbase Technical Support.",
+		Type: ClusterCheckerType,
+	},
+	CheckAsymmetricalCluster: {
+		ID:    "CB90019",
+		Name:  CheckAsymmetricalCluster,
+		Title: "Asymmetrical Cluster",
+		Description: "Checks that all nodes in the cluster have the same number of CPUs and amount of RAM, " +
+			"differing hardware can lead to unpredictable application performance.",
+		Remediation: "Ensure all nodes have identical hardware.",
+		Type:        ClusterCheckerType,
+	},
+	CheckBackupLocation: {
+		ID:    "CB90022",
+		Name:  CheckBackupLocation,
+		Title: "Node Backup Location",
+		Description: "Checks that the number of Backup Service archive location errors has not increased in the " +
+			"past three days.",
+		Remediation: "Ensure the Backup Service has consistent access to its archive location.",
+		Type:        ClusterCheckerType,
+	},
+	CheckOrphanedBackupTasks: {
+		ID:          "CB90023",
+		Name:        CheckOrphanedBackupTasks,
+		Title:       "Orphaned Backup Tasks",
+		Description: "Checks that the number of orphaned backup tasks has not increased in the past three days.",
+		Remediation: "Review the Backup Service logs to identify the cause of the problem, or contact Couchbase " +
+			"Technical Support.",
+		Type: ClusterCheckerType,
+	},
+	CheckIndexWithNoRedundancy: {
+		ID:          "CB90030",
+		Name:        CheckIndexWithNoRedundancy,
+		Title:       "Index With No Redundancy",
+		Description: "Checks that every index has either replicas or equivalent indexes.",
+		Remediation: "Either increase the number of replicas or add equivalent indexes.",
+		Type:        ClusterCheckerType,
+	},
+	CheckBadRedundantIndex: {
+		ID:          "CB90031",
+		Name:        CheckBadRedundantIndex,
+		Title:       "Bad Redundant Index",
+		Description: "Checks that equivalent indexes are not placed on the same node.",
+		Remediation: "Move the indexes to different Index Service nodes. Consider using index replicas instead.",
+		Type:        ClusterCheckerType,
+	},
+	CheckTooManyIndexReplicas: {
+		ID:          "CB90032",
+		Name:        CheckTooManyIndexReplicas,
+		Title:       "Too Many Index Replicas",
+		Description: "Checks that no index has more replicas than there are Index Service nodes.",
+		Remediation: "Either reduce the number of replicas, or add more Index Service nodes.",
+		Type:        ClusterCheckerType,
+	},
+	CheckEmptyServerGroup: {
+		ID:          "CB90035",
+		Name:        CheckEmptyServerGroup,
+		Title:       "Empty Server Group",
+		Description: "Checks that there are no server groups without any nodes.",
+		Remediation: "Remove the empty server group.",
+		Type:        ClusterCheckerType,
+	},
+	CheckDeveloperPreview: {
+		ID:          "CB90059",
+		Name:        CheckDeveloperPreview,
+		Title:       "Developer Preview",
+		Description: "Checks that the cluster is not in Developer Preview mode, which is unsupported.",
+		Remediation: "If this is a development only cluster, you do not need to do anything, otherwise create a new " +
+			"cluster that is not in Developer Preview mode.",
+		Type: ClusterCheckerType,
+	},
+	CheckNodeToNodeCommunication: {
+		ID:          "CB90064",
+		Name:        CheckNodeToNodeCommunication,
+		Title:       "Node-to-Node Communication Issues",
+		Description: "Checks that all nodes can establish TCP connections to each other.",
+		Remediation: "Verify the ports listed in the alert, and ensure there are no firewalls or other network " +
+			"configuration issues between the listed nodes.",
+		Type: ClusterCheckerType,
+	},
+	CheckMissingIndexPartitions: {
+		ID:    "CB90068",
+		Name:  CheckMissingIndexPartitions,
+		Title: "Missing Index Partition",
+		Description: "Checks that the number of index partitions present matches the number defined when creating " +
+			"the index.",
+		Remediation: "Check if a node has been failed over. If this is not the case, recreate the index again and " +
+			"contact Couchbase Technical Support.",
+		Type: ClusterCheckerType,
+	},
+	CheckImbalancedIndexPartitions: {
+		ID:    "CB90069",
+		Name:  CheckImbalancedIndexPartitions,
+		Title: "Imbalanced Index Partitions",
+		Description: "Checks that no index partition is more than 20% larger than the partitions for the same index " +
+			"on other nodes.",
+		Remediation: "Recreate the imbalanced index to redistribute the index partition data, making sure the index " +
+			"partitions are hashed to valid fields.",
+		Type: ClusterCheckerType,
+	},
+	CheckOneServicePerNode: {
+		ID:          "CB90001",
+		Name:        CheckOneServicePerNode,
+		Title:       "One Service Per Node",
+		Description: "Checks that each node runs only one service.",
+		Remediation: "Move services to their own dedicated nodes.",
+		Type:        NodeCheckerType,
+	},
+	CheckUnhealthyNode: {
+		ID:          "CB90003",
+		Name:        CheckUnhealthyNode,
+		Title:       "Unhealthy Node",
+		Description: "Checks that the Cluster Manager does not report the node as unhealthy.",
+		Remediation: "Rebalance the unhealthy nodes out of the cluster, and replace them if appropriate. Examine the " +
+			"other health check results to identify the potential cause, or contact Couchbase Technical Support.",
+		Type: NodeCheckerType,
+	},
+	CheckSupportedVersion: {
+		ID:    "CB90012",
+		Name:  CheckSupportedVersion,
+		Title: "Server Version Supportability",
+		Description: "Checks that the node runs a version of Couchbase Server that is still supported under the " +
+			"Enterprise Software Support Policy.",
+		Remediation: "Upgrade the node to a supported version of Couchbase Server. If this is not possible, contact " +
+			"your Couchbase Account Manager.",
+		Type: NodeCheckerType,
+	},
+	CheckGABuild: {
+		ID:          "CB90014",
+		Name:        CheckGABuild,
+		Title:       "Generally Available Build",
+		Description: "Checks that the node runs a generally available build of Couchbase Server.",
+		Remediation: "Upgrade the node to a generally available build of Couchbase Server. If you have a specific " +
+			"agreement with Couchbase to operate a non-GA build, it is safe to disregard this warning.",
+		Type: NodeCheckerType,
+	},
+	CheckNodeSwapUsage: {
+		ID:          "CB90018",
+		Name:        CheckNodeSwapUsage,
+		Title:       "Node Swap Usage",
+		Description: "Checks that the node is not using swap space.",
+		Remediation: "Increase available RAM on the nodes.",
+		Type:        NodeCheckerType,
+	},
+	CheckCPUBucketCount: {
+		ID:          "CB90020",
+		Name:        CheckCPUBucketCount,
+		Title:       "CPU and Bucket Count",
+		Description: "Checks that the node has at least as many CPUs as there are buckets.",
+		Remediation: "Upgrade the nodes' hardware or reduce the number of buckets.",
+		Type:        NodeCheckerType,
+	},
+	CheckNodeDiskSpace: {
+		ID:          "CB90021",
+		Name:        CheckNodeDiskSpace,
+		Title:       "Node Disk Space",
+		Description: "Checks that the disks used by the node are less than 90% full.",
+		Remediation: "Increase the amount of disk space available.",
+		Type:        NodeCheckerType,
+	},
+	CheckTransparentHugePages: {
+		ID:          "CB90025",
+		Name:        CheckTransparentHugePages,
+		Title:       "Transparent Huge Pages",
+		Description: "Checks that Transparent Huge Pages are not set to always.",
+		Remediation: "Set the THP configuration to madvise or never.",
+		Type:        NodeCheckerType,
+	},
+	CheckServiceStatus: {
+		ID:          "CB90026",
+		Name:        CheckServiceStatus,
+		Title:       "Service Status",
+		Description: "Checks that the services running on the node can be reached on their ports.",
+		Remediation: "Ensure there is no firewall blocking communication. Review your infrastructure for networking " +
+			"issues.",
+		Type: NodeCheckerType,
+	},
+	CheckGSILogLevel: {
+		ID:          "CB90027",
+		Name:        CheckGSILogLevel,
+		Title:       "Index Service Log Level",
+		Description: "Checks that the Index Service log level is set to the default of Info.",
+		Remediation: "Change the log level to Info.",
+		Type:        NodeCheckerType,
+	},
+	CheckSharedFilesystems: {
+		ID:          "CB90028",
+		Name:        CheckSharedFilesystems,
+		Title:       "Services Sharing File Systems",
+		Description: "Checks that the data directories of different services are on separate partitions.",
+		Remediation: "Move all services to separate partitions or logical volumes.",
+		Type:        NodeCheckerType,
+	},
+	CheckMinimumNodeMemory: {
+		ID:          "CB90034",
+		Name:        CheckMinimumNodeMemory,
+		Title:       "Below Minimum Node Memory",
+		Description: "Checks that the node has at least 4GB of RAM.",
+		Remediation: "Upgrade the node's hardware.",
+		Type:        NodeCheckerType,
+	},
+	CheckSupportedOS: {
+		ID:    "CB90040",
+		Name:  CheckSupportedOS,
+		Title: "Unsupported/Deprecated Operating System",
+		Description: "Checks that the node's operating system is supported by the version of Couchbase Server in " +
+			"use.",
+		Remediation: "Upgrade the operating system of the node.",
+		Type:        NodeCheckerType,
+	},
+	CheckSegmentationFaults: {
+		ID:          "CB90042",
+		Name:        CheckSegmentationFaults,
+		Title:       "Segmentation Faults",
+		Description: "Looks for segmentation faults in the system logs.",
+		Remediation: "Examine the system logs. If a Couchbase process was the one to crash, contact Couchbase " +
+			"Technical Support.",
+		Type: NodeCheckerType,
+	},
+	CheckManagedProcessCrash: {
+		ID:          "CB90044",
+		Name:        CheckManagedProcessCrash,
+		Title:       "Managed Process Crash",
+		Description: "Looks for crashes of processes managed by the babysitter.",
+		Remediation: "If this is happening repeatedly or you notice disruption in your cluster, contact Couchbase " +
+			"Technical Support.",
+		Type: NodeCheckerType,
+	},
+	CheckFreeMemory: {
+		ID:          "CB90045",
+		Name:        CheckFreeMemory,
+		Title:       "Free Memory",
+		Description: "Checks that no more than 90% of the node's RAM is in use.",
+		Remediation: "Add more RAM to the node, or review the resource usage of other applications on the server.",
+		Type:        NodeCheckerType,
+	},
+	CheckProcessLimits: {
+		ID:    "CB90058",
+		Name:  CheckProcessLimits,
+		Title: "Open File / User Process Limit",
+		Description: "Checks that the open file and process limits for the babysitter process are at or above the " +
+			"recommended values.",
+		Remediation: "Increase the open file / process limit for the Couchbase Server processes.",
+		Type:        NodeCheckerType,
+	},
+	CheckOOMKills: {
+		ID:          "CB90060",
+		Name:        CheckOOMKills,
+		Title:       "Out-Of-Memory Killer Activity",
+		Description: "Looks for OOM killer messages in the kernel log.",
+		Remediation: "Review available memory on the node.",
+		Type:        NodeCheckerType,
+	},
+	CheckDuplicateNodeUUID: {
+		ID:          "CB90063",
+		Name:        CheckDuplicateNodeUUID,
+		Title:       "Duplicate Node UUID",
+		Description: "Checks that every node UUID is unique in the cluster.",
+		Remediation: "Contact Couchbase Technical Support.",
+		Type:        NodeCheckerType,
+	},
+	CheckNodeExporterAlert: {
+		ID:          "CB90072",
+		Name:        CheckNodeExporterAlert,
+		Title:       "Node Exporter Alert",
+		Description: "Evaluated by the observability stack alerting rules rather than by the cluster monitor.",
+		Remediation: "See the observability documentation for this alert.",
+		Type:        NodeCheckerType,
+	},
+	CheckSYNFlooding: {
+		ID:          "CB90074",
+		Name:        CheckSYNFlooding,
+		Title:       "SYN flooding",
+		Description: "Looks for SYN flooding messages in the kernel log.",
+		Remediation: "Reduce the number of incoming connections to the specified port.",
+		Type:        NodeCheckerType,
+	},
+	CheckCPUSoftLockup: {
+		ID:          "CB90075",
+		Name:        CheckCPUSoftLockup,
+		Title:       "CPU Soft Lockup",
+		Description: "Looks for CPU soft lockup messages in the kernel log.",
+		Remediation: "If deploying Couchbase Server in a virtual environment check if said environment is " +
+			"overcommitted.",
+		Type: NodeCheckerType,
+	},
+	CheckConnTrackingTableFull: {
+		ID:          "CB90076",
+		Name:        CheckConnTrackingTableFull,
+		Title:       "Connection Tracking Table Full",
+		Description: "Looks for connection tracking table full messages in the kernel log.",
+		Remediation: "Check your clients are closing connections to Couchbase Server properly.",
+		Type:        NodeCheckerType,
+	},
+	CheckResidentRatio: {
+		ID:          "CB90013",
+		Name:        CheckResidentRatio,
+		Title:       "Resident Ratio Too Low",
+		Description: "Checks that the bucket's resident ratio is at least 10%.",
+		Remediation: "Increase the bucket's memory quota.",
+		Type:        BucketCheckerType,
+	},
+	CheckReplicaVBucketNumber: {
+		ID:    "CB90015",
+		Name:  CheckReplicaVBucketNumber,
+		Title: "Replica vBucket Number",
+		Description: "Checks that there are enough nodes for the number of replicas requested, 5 or more for 2 " +
+			"replicas and 10 or more for 3 replicas.",
+		Remediation: "Add more nodes to the cluster, or reduce the number of replicas.",
+		Type:        BucketCheckerType,
+	},
+	CheckBucketMemoryUsage: {
+		ID:          "CB90017",
+		Name:        CheckBucketMemoryUsage,
+		Title:       "Bucket Memory Usage",
+		Description: "Checks that the bucket's memory usage is not at or above 95% of its quota.",
+		Remediation: "Increase the bucket's memory quota.",
+		Type:        BucketCheckerType,
+	},
+	CheckDCPPaused: {
+		ID:          "CB90024",
+		Name:        CheckDCPPaused,
+		Title:       "Bucket DCP Paused",
+		Description: "Checks for DCP replications paused due to MB-46482.",
+		Remediation: "Upgrade to Couchbase Server 6.6.3. If this is not viable, contact Couchbase Technical Support.",
+		Type:        BucketCheckerType,
+	},
+	CheckLargeCheckpoints: {
+		ID:          "CB90029",
+		Name:        CheckLargeCheckpoints,
+		Title:       "Large Checkpoints",
+		Description: "Checks that no vBucket checkpoints are larger than 50MB or 1% of the bucket quota.",
+		Remediation: "Contact Couchbase Technical Support for analysis.",
+		Type:        BucketCheckerType,
+	},
+	CheckSlowOperations: {
+		ID:          "CB90033",
+		Name:        CheckSlowOperations,
+		Title:       "Slow Operations",
+		Description: "Evaluated by the observability stack alerting rules rather than by the cluster monitor.",
+		Remediation: "See the observability documentation for this alert.",
+		Type:        BucketCheckerType,
+	},
+	CheckMemcachedFragmentation: {
+		ID:          "CB90039",
+		Name:        CheckMemcachedFragmentation,
+		Title:       "Memcached Heap Fragmentation",
+		Description: "Checks that no more than 15% of the memcached heap is fragmented.",
+		Remediation: "Contact Couchbase Technical Support for analysis.",
+		Type:        BucketCheckerType,
+	},
+	CheckUnknownStorageEngine: {
+		ID:          "CB90053",
+		Name:        CheckUnknownStorageEngine,
+		Title:       "Unknown Storage Engine",
+		Description: "Checks that the bucket uses one of the couchstore, ephemeral or magma storage engines.",
+		Remediation: "Contact Couchbase Technical Support for analysis.",
+		Type:        BucketCheckerType,
+	},
+	CheckTooManyFTSReplicas: {
+		ID:          "CB90065",
+		Name:        CheckTooManyFTSReplicas,
+		Title:       "Too many Full Text Search (FTS) Replicas",
+		Description: "Checks that there are fewer FTS index replicas than nodes running the Search Service.",
+		Remediation: "Ensure there are strictly fewer FTS index replicas than nodes running the Search Service.",
+		Type:        BucketCheckerType,
+	},
+	CheckHistogramUnderflow: {
+		ID:          "CB90077",
+		Name:        CheckHistogramUnderflow,
+		Title:       "Timing Histogram Underflow",
+		Description: "Checks for command timing histograms affected by MB-40967.",
+		Remediation: "Upgrade to Couchbase Server 6.6.1 or later. If this is not feasible, use cbstats reset to " +
+			"reset the histograms.",
+		Type: BucketCheckerType,
+	},
+	CheckMaxTTL: {
+		ID:          "CB90078",
+		Name:        CheckMaxTTL,
+		Title:       "MaxTTL for Bucket Exceeded 30 Days",
+		Description: "Checks for buckets with a max TTL of 30 days or more on versions that apply it incorrectly.",
+		Remediation: "Upgrade to Couchbase Server 6.0.4 or later. If not feasible at the moment, use absolute time " +
+			"if the TTL exceeds 30 days.",
+		Type: BucketCheckerType,
+	},
+	CheckNonDefaultVBucketCount: {
+		ID:          "CB90079",
+		Name:        CheckNonDefaultVBucketCount,
+		Title:       "Set VBucket Count to Default",
+		Description: "Checks that the bucket uses the default number of vBuckets for the platform.",
+		Remediation: "Set the vBucket number back to the default value (64 on Mac, 1024 on Windows/Linux).",
+		Type:        BucketCheckerType,
+	},
+}
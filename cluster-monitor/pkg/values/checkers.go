@@ -0,0 +1,84 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package values
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CheckerStatus is the outcome of running a checker.
+type CheckerStatus string
+
+const (
+	GoodCheckerStatus  CheckerStatus = "good"
+	WarnCheckerStatus  CheckerStatus = "warn"
+	AlertCheckerStatus CheckerStatus = "alert"
+	InfoCheckerStatus  CheckerStatus = "info"
+)
+
+// CheckerType is the scope a checker runs at.
+type CheckerType string
+
+const (
+	ClusterCheckerType CheckerType = "cluster"
+	NodeCheckerType    CheckerType = "node"
+	BucketCheckerType  CheckerType = "bucket"
+)
+
+// CheckerResult is the result of running a single checker. Value holds any checker specific data that helps explain
+// the status. Version is the status run that produced the result, results from older runs are considered stale.
+type CheckerResult struct {
+	Name        string          `json:"name"`
+	Remediation string          `json:"remediation,omitempty"`
+	Value       json.RawMessage `json:"value,omitempty"`
+	Status      CheckerStatus   `json:"status"`
+	Time        time.Time       `json:"time"`
+	Version     int             `json:"version"`
+}
+
+// WrappedCheckerResult is a checker result together with the scope it applies to. Cluster is always set, Node, Bucket
+// and LogFile are only set for checkers at that scope.
+type WrappedCheckerResult struct {
+	Result  *CheckerResult `json:"result"`
+	Cluster string         `json:"cluster"`
+	Node    string         `json:"node,omitempty"`
+	Bucket  string         `json:"bucket,omitempty"`
+	LogFile string         `json:"log_file,omitempty"`
+}
+
+// CheckerSearch is used to filter checker results. Any nil field matches all results.
+type CheckerSearch struct {
+	Name    *string
+	Cluster *string
+	Node    *string
+	Bucket  *string
+	LogFile *string
+	Status  *CheckerStatus
+}
+
+// ClusterStatusSummary is the number of checker results of each status for a cluster.
+type ClusterStatusSummary struct {
+	Good      int `json:"good"`
+	Warnings  int `json:"warnings"`
+	Alerts    int `json:"alerts"`
+	Info      int `json:"info"`
+	Dismissed int `json:"dismissed"`
+}
+
+// Add counts the result in the summary.
+func (s *ClusterStatusSummary) Add(result *CheckerResult) {
+	switch result.Status {
+	case GoodCheckerStatus:
+		s.Good++
+	case WarnCheckerStatus:
+		s.Warnings++
+	case AlertCheckerStatus:
+		s.Alerts++
+	case InfoCheckerStatus:
+		s.Info++
+	}
+}
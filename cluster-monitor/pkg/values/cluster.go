@@ -31,8 +31,30 @@ const (
 	BadAuthHeartIssue
 	NoConnectionHeartIssue
 	UUIDMismatchHeartIssue
+	// TimeoutHeartIssue is when the cluster could be reached but did not respond within the request timeout.
+	TimeoutHeartIssue
 )
 
+// DefaultRequestTimeout is how long requests to a cluster can take when the cluster does not set its own timeout.
+const DefaultRequestTimeout = 30 * time.Second
+
+func (h HeartIssue) String() string {
+	switch h {
+	case NoHeartIssue:
+		return "no issue"
+	case BadAuthHeartIssue:
+		return "bad authentication"
+	case NoConnectionHeartIssue:
+		return "no connection"
+	case UUIDMismatchHeartIssue:
+		return "UUID mismatch"
+	case TimeoutHeartIssue:
+		return "timeout"
+	}
+
+	return fmt.Sprintf("unknown(%d)", uint8(h))
+}
+
 type Status string
 
 const (
@@ -65,6 +87,21 @@ type CouchbaseCluster struct {
 	HeartBeatIssue HeartIssue     `json:"heart_beat_issue,omitempty"`
 	LastUpdate     time.Time      `json:"last_update"`
 	CaCert         []byte         `json:"-"`
+
+	// RequestTimeout bounds each request made to the cluster, zero uses DefaultRequestTimeout.
+	RequestTimeout time.Duration `json:"request_timeout,omitempty"`
+
+	// StatusSummary is only set when returning the cluster through the REST API and only for enterprise clusters.
+	StatusSummary *ClusterStatusSummary `json:"status_summary,omitempty"`
+}
+
+// GetRequestTimeout returns how long each request to the cluster can take.
+func (c *CouchbaseCluster) GetRequestTimeout() time.Duration {
+	if c.RequestTimeout <= 0 {
+		return DefaultRequestTimeout
+	}
+
+	return c.RequestTimeout
 }
 
 // GetTLSConfig returns a TLS config that has the CA if the cluster has an associated CA.
@@ -152,6 +189,7 @@ type NodeSummary struct {
 	NodeUUID          string   `json:"node_uuid"`
 	Version           string   `json:"version,omitempty"`
 	Host              string   `json:"host,omitempty"`
+	KVHost            string   `json:"kv_host,omitempty"`
 	OS                string   `json:"os,omitempty"`
 	Status            string   `json:"status,omitempty"`
 	ClusterMembership string   `json:"cluster_membership,omitempty"`
@@ -0,0 +1,179 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package values
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DismissLevel is the scope a dismissal applies to.
+type DismissLevel int
+
+const (
+	// AllDismissLevel dismisses the checker in every cluster.
+	AllDismissLevel DismissLevel = iota
+	// ClusterDismissLevel dismisses all the results of the checker in a cluster.
+	ClusterDismissLevel
+	// BucketDismissLevel dismisses the result of the checker for a bucket.
+	BucketDismissLevel
+	// NodeDismissLevel dismisses the result of the checker for a node.
+	NodeDismissLevel
+	// FileDismissLevel dismisses the result of the checker for a log file in a node.
+	FileDismissLevel
+)
+
+var dismissLevelNames = map[DismissLevel]string{
+	AllDismissLevel:     "all",
+	ClusterDismissLevel: "cluster",
+	BucketDismissLevel:  "bucket",
+	NodeDismissLevel:    "node",
+	FileDismissLevel:    "file",
+}
+
+func (l DismissLevel) String() string {
+	if name, ok := dismissLevelNames[l]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("unknown(%d)", int(l))
+}
+
+func (l DismissLevel) MarshalJSON() ([]byte, error) {
+	name, ok := dismissLevelNames[l]
+	if !ok {
+		return nil, fmt.Errorf("unknown dismiss level %d", int(l))
+	}
+
+	return json.Marshal(name)
+}
+
+func (l *DismissLevel) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("dismiss level must be a string: %w", err)
+	}
+
+	for level, levelName := range dismissLevelNames {
+		if levelName == name {
+			*l = level
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown dismiss level '%s'", name)
+}
+
+// Dismissal silences the results of a checker at the given level. It applies until the Until time unless Forever is
+// set.
+type Dismissal struct {
+	ID          string       `json:"id"`
+	Level       DismissLevel `json:"level"`
+	CheckerName string       `json:"checker_name"`
+	ClusterUUID string       `json:"cluster_uuid,omitempty"`
+	BucketName  string       `json:"bucket_name,omitempty"`
+	NodeUUID    string       `json:"node_uuid,omitempty"`
+	LogFile     string       `json:"log_file,omitempty"`
+	Forever     bool         `json:"forever"`
+	Until       time.Time    `json:"until"`
+}
+
+// Applies returns whether the result is silenced by the dismissal. It does not check if the dismissal has expired.
+func (d *Dismissal) Applies(result *WrappedCheckerResult) bool {
+	if result.Result == nil || result.Result.Name != d.CheckerName {
+		return false
+	}
+
+	switch d.Level {
+	case AllDismissLevel:
+		return true
+	case ClusterDismissLevel:
+		return result.Cluster == d.ClusterUUID
+	case BucketDismissLevel:
+		return result.Cluster == d.ClusterUUID && result.Bucket == d.BucketName
+	case NodeDismissLevel:
+		return result.Cluster == d.ClusterUUID && result.Node == d.NodeUUID
+	case FileDismissLevel:
+		return result.Cluster == d.ClusterUUID && result.Node == d.NodeUUID && result.LogFile == d.LogFile
+	}
+
+	return false
+}
+
+// Validate checks that the dismissal has all the fields its level requires.
+func (d *Dismissal) Validate() error {
+	if d.CheckerName == "" {
+		return fmt.Errorf("checker name is required")
+	}
+
+	if !d.Forever && d.Until.IsZero() {
+		return fmt.Errorf("dismissal must either be forever or have an end time")
+	}
+
+	switch d.Level {
+	case AllDismissLevel:
+		if d.ClusterUUID != "" || d.BucketName != "" || d.NodeUUID != "" || d.LogFile != "" {
+			return fmt.Errorf("all level dismissals cannot have a cluster, bucket, node or log file")
+		}
+
+		return nil
+	case ClusterDismissLevel, BucketDismissLevel, NodeDismissLevel, FileDismissLevel:
+		if d.ClusterUUID == "" {
+			return fmt.Errorf("cluster UUID is required for %s level dismissals", d.Level)
+		}
+	default:
+		return fmt.Errorf("unknown dismiss level %d", int(d.Level))
+	}
+
+	if d.Level == BucketDismissLevel && d.BucketName == "" {
+		return fmt.Errorf("bucket name is required for bucket level dismissals")
+	}
+
+	if (d.Level == NodeDismissLevel || d.Level == FileDismissLevel) && d.NodeUUID == "" {
+		return fmt.Errorf("node UUID is required for %s level dismissals", d.Level)
+	}
+
+	if d.Level == FileDismissLevel && d.LogFile == "" {
+		return fmt.Errorf("log file is required for file level dismissals")
+	}
+
+	return nil
+}
+
+// DismissalSearchSpace is used to filter dismissals. Any nil field matches all dismissals. As global dismissals apply to
+// every cluster they are always matched by ClusterUUID.
+type DismissalSearchSpace struct {
+	ID          *string
+	CheckerName *string
+	ClusterUUID *string
+	// IncludeExpired also returns dismissals whose end time has passed.
+	IncludeExpired bool
+}
+
+// DismissResults splits the results into the ones that are not silenced by any of the dismissals and the number of the
+// ones that are.
+func DismissResults(results []*WrappedCheckerResult, dismissals []*Dismissal) ([]*WrappedCheckerResult, int) {
+	kept := make([]*WrappedCheckerResult, 0, len(results))
+	for _, result := range results {
+		if !IsDismissed(result, dismissals) {
+			kept = append(kept, result)
+		}
+	}
+
+	return kept, len(results) - len(kept)
+}
+
+// IsDismissed returns whether any of the dismissals apply to the result.
+func IsDismissed(result *WrappedCheckerResult, dismissals []*Dismissal) bool {
+	for _, dismissal := range dismissals {
+		if dismissal.Applies(result) {
+			return true
+		}
+	}
+
+	return false
+}
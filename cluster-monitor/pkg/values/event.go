@@ -0,0 +1,111 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package values
+
+import (
+	"sort"
+	"time"
+)
+
+// EventType is the kind of change an event describes.
+type EventType string
+
+const (
+	ClusterAddedEvent          EventType = "cluster_added"
+	ClusterRemovedEvent        EventType = "cluster_removed"
+	HeartbeatIssueChangedEvent EventType = "heartbeat_issue_changed"
+	NodeStatusChangedEvent     EventType = "node_status_changed"
+	BucketAddedEvent           EventType = "bucket_added"
+	BucketRemovedEvent         EventType = "bucket_removed"
+)
+
+// Event is a change in the state of a cluster. Node and Bucket are only set for the events about them, and Old and New
+// are only set for the events that change a value.
+type Event struct {
+	Type        EventType `json:"type"`
+	ClusterUUID string    `json:"cluster_uuid"`
+	ClusterName string    `json:"cluster_name,omitempty"`
+	Node        string    `json:"node,omitempty"`
+	Bucket      string    `json:"bucket,omitempty"`
+	Old         string    `json:"old,omitempty"`
+	New         string    `json:"new,omitempty"`
+	Time        time.Time `json:"time"`
+}
+
+// ClusterChangeEvents returns the events needed to go from the old to the new state of the cluster. The new state can
+// be partial, as given to Store.UpdateCluster, so the nodes and buckets are only compared if they are set.
+func ClusterChangeEvents(old, new *CouchbaseCluster, now time.Time) []Event {
+	newEvent := func(eventType EventType) Event {
+		return Event{Type: eventType, ClusterUUID: old.UUID, ClusterName: old.Name, Time: now}
+	}
+
+	var events []Event
+	if old.HeartBeatIssue != new.HeartBeatIssue {
+		event := newEvent(HeartbeatIssueChangedEvent)
+		event.Old, event.New = old.HeartBeatIssue.String(), new.HeartBeatIssue.String()
+		events = append(events, event)
+	}
+
+	if new.NodesSummary != nil {
+		oldStatuses := make(map[string]string, len(old.NodesSummary))
+		for _, node := range old.NodesSummary {
+			oldStatuses[node.NodeUUID] = node.Status
+		}
+
+		for _, node := range new.NodesSummary {
+			oldStatus, ok := oldStatuses[node.NodeUUID]
+			if !ok || oldStatus == node.Status {
+				continue
+			}
+
+			event := newEvent(NodeStatusChangedEvent)
+			event.Node, event.Old, event.New = node.NodeUUID, oldStatus, node.Status
+			events = append(events, event)
+		}
+	}
+
+	if new.BucketsSummary != nil {
+		oldBuckets := bucketNames(old.BucketsSummary)
+		newBuckets := bucketNames(new.BucketsSummary)
+
+		for _, name := range sortedKeys(newBuckets) {
+			if !oldBuckets[name] {
+				event := newEvent(BucketAddedEvent)
+				event.Bucket = name
+				events = append(events, event)
+			}
+		}
+
+		for _, name := range sortedKeys(oldBuckets) {
+			if !newBuckets[name] {
+				event := newEvent(BucketRemovedEvent)
+				event.Bucket = name
+				events = append(events, event)
+			}
+		}
+	}
+
+	return events
+}
+
+func bucketNames(buckets BucketsSummary) map[string]bool {
+	names := make(map[string]bool, len(buckets))
+	for _, bucket := range buckets {
+		names[bucket.Name] = true
+	}
+
+	return names
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
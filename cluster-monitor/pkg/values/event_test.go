@@ -0,0 +1,78 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package values
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterChangeEvents(t *testing.T) {
+	now := time.Now()
+	old := &CouchbaseCluster{
+		UUID: "uuid-0",
+		Name: "cluster-0",
+		NodesSummary: NodesSummary{
+			{NodeUUID: "node-0", Status: "healthy"},
+			{NodeUUID: "node-1", Status: "healthy"},
+		},
+		BucketsSummary: BucketsSummary{{Name: "a"}, {Name: "b"}},
+	}
+
+	newEvent := func(eventType EventType) Event {
+		return Event{Type: eventType, ClusterUUID: "uuid-0", ClusterName: "cluster-0", Time: now}
+	}
+
+	type testCase struct {
+		name     string
+		new      *CouchbaseCluster
+		expected []Event
+	}
+
+	nodeChanged := newEvent(NodeStatusChangedEvent)
+	nodeChanged.Node, nodeChanged.Old, nodeChanged.New = "node-1", "healthy", "unhealthy"
+
+	issueChanged := newEvent(HeartbeatIssueChangedEvent)
+	issueChanged.Old, issueChanged.New = NoHeartIssue.String(), NoConnectionHeartIssue.String()
+
+	bucketAdded := newEvent(BucketAddedEvent)
+	bucketAdded.Bucket = "c"
+
+	bucketRemoved := newEvent(BucketRemovedEvent)
+	bucketRemoved.Bucket = "a"
+
+	cases := []testCase{
+		{
+			name: "noChanges",
+			new:  old,
+		},
+		{
+			name:     "partialUpdate",
+			new:      &CouchbaseCluster{UUID: "uuid-0", HeartBeatIssue: NoConnectionHeartIssue},
+			expected: []Event{issueChanged},
+		},
+		{
+			name: "nodesAndBuckets",
+			new: &CouchbaseCluster{
+				UUID: "uuid-0",
+				NodesSummary: NodesSummary{
+					{NodeUUID: "node-1", Status: "unhealthy"},
+					{NodeUUID: "node-2", Status: "healthy"},
+				},
+				BucketsSummary: BucketsSummary{{Name: "b"}, {Name: "c"}},
+			},
+			expected: []Event{nodeChanged, bucketAdded, bucketRemoved},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, ClusterChangeEvents(old, tc.new, now))
+		})
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package values
+
+import "time"
+
+// Frequencies are how often the monitors run. Zero values have not been set.
+type Frequencies struct {
+	Heart     time.Duration `json:"heart"`
+	Status    time.Duration `json:"status"`
+	Discovery time.Duration `json:"discovery"`
+}
+
+// Merge returns the frequencies with the values not set in f taken from defaults.
+func (f Frequencies) Merge(defaults Frequencies) Frequencies {
+	if f.Heart == 0 {
+		f.Heart = defaults.Heart
+	}
+
+	if f.Status == 0 {
+		f.Status = defaults.Status
+	}
+
+	if f.Discovery == 0 {
+		f.Discovery = defaults.Discovery
+	}
+
+	return f
+}
+
+// ClusterFrequencies override how often a single cluster is heartbeat and checked. Zero values use the global
+// frequencies.
+type ClusterFrequencies struct {
+	ClusterUUID string        `json:"cluster_uuid"`
+	Heart       time.Duration `json:"heart"`
+	Status      time.Duration `json:"status"`
+}
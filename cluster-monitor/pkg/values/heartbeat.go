@@ -0,0 +1,56 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package values
+
+import "time"
+
+// HeartbeatRecord is an entry in the heartbeat history of a cluster. Only the heartbeats that change the cluster state
+// are recorded so each record marks the start of a period with the given issue.
+type HeartbeatRecord struct {
+	ClusterUUID string     `json:"cluster_uuid"`
+	Issue       HeartIssue `json:"issue"`
+	Error       string     `json:"error,omitempty"`
+	// Latency is how long it took to connect to the cluster in nanoseconds.
+	Latency time.Duration `json:"latency"`
+	Time    time.Time     `json:"time"`
+}
+
+// Availability returns the percentage of the time between since and until in which the cluster had no heartbeat
+// issues. The records must be in chronological order, time before the first record is unknown so it is not taken into
+// account. If none of the time is known ok is false.
+func Availability(records []*HeartbeatRecord, since, until time.Time) (availability float64, ok bool) {
+	var known, available time.Duration
+	for i, record := range records {
+		start := record.Time
+		if start.Before(since) {
+			start = since
+		}
+
+		end := until
+		if i+1 < len(records) {
+			end = records[i+1].Time
+		}
+
+		if end.After(until) {
+			end = until
+		}
+
+		if !end.After(start) {
+			continue
+		}
+
+		known += end.Sub(start)
+		if record.Issue == NoHeartIssue {
+			available += end.Sub(start)
+		}
+	}
+
+	if known == 0 {
+		return 0, false
+	}
+
+	return 100 * float64(available) / float64(known), true
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2021 Couchbase, Inc.
+//
+// Use of this software is subject to the Couchbase Inc. License Agreement
+// which may be found at https://www.couchbase.com/LA03012021.
+
+package values
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAvailability(t *testing.T) {
+	since := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	until := since.Add(10 * time.Hour)
+
+	type testCase struct {
+		name       string
+		records    []*HeartbeatRecord
+		expected   float64
+		expectedOK bool
+	}
+
+	cases := []testCase{
+		{
+			name: "none",
+		},
+		{
+			name:       "always-up",
+			records:    []*HeartbeatRecord{{Time: since.Add(-time.Hour)}},
+			expected:   100,
+			expectedOK: true,
+		},
+		{
+			name: "down-for-two-hours",
+			records: []*HeartbeatRecord{
+				{Time: since.Add(-time.Hour)},
+				{Time: since.Add(4 * time.Hour), Issue: NoConnectionHeartIssue},
+				{Time: since.Add(6 * time.Hour)},
+			},
+			expected:   80,
+			expectedOK: true,
+		},
+		{
+			name: "unknown-start",
+			records: []*HeartbeatRecord{
+				{Time: since.Add(5 * time.Hour), Issue: BadAuthHeartIssue},
+				{Time: since.Add(9 * time.Hour)},
+			},
+			expected:   20,
+			expectedOK: true,
+		},
+		{
+			name:    "after-until",
+			records: []*HeartbeatRecord{{Time: until.Add(time.Hour)}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			availability, ok := Availability(tc.records, since, until)
+			require.Equal(t, tc.expectedOK, ok)
+			require.InDelta(t, tc.expected, availability, 0.001)
+		})
+	}
+}
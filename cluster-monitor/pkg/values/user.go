@@ -5,9 +5,50 @@
 
 package values
 
-// User is a basic representation of a multi cluster user.
+import "fmt"
+
+// Role determines what a user is allowed to do. Each role can do everything the roles below it can.
+type Role string
+
+const (
+	// ViewerRole can only read the cluster information, checker results and definitions.
+	ViewerRole Role = "viewer"
+	// OperatorRole can also add and update clusters, manage aliases and dismiss checker results.
+	OperatorRole Role = "operator"
+	// AdminRole can do everything including managing users, deleting clusters and managing cloud credentials.
+	AdminRole Role = "admin"
+)
+
+var roleRanks = map[Role]int{
+	ViewerRole:   0,
+	OperatorRole: 1,
+	AdminRole:    2,
+}
+
+// Validate returns an error if the role is not one of the known roles.
+func (r Role) Validate() error {
+	if _, ok := roleRanks[r]; !ok {
+		return fmt.Errorf("unknown role '%s'", r)
+	}
+
+	return nil
+}
+
+// Allows returns true if a user with this role can perform actions that require the given role.
+func (r Role) Allows(required Role) bool {
+	rank, ok := roleRanks[r]
+	if !ok {
+		return false
+	}
+
+	return rank >= roleRanks[required]
+}
+
+// User is a basic representation of a multi cluster user. Admin is kept for compatibility and is true only for users
+// with the admin role.
 type User struct {
 	User     string `json:"user"`
 	Password []byte `json:"-"`
 	Admin    bool   `json:"admin"`
+	Role     Role   `json:"role"`
 }